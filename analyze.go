@@ -0,0 +1,71 @@
+package mp3
+
+// A FrameAnalysis is the result of AnalyzeFrame.
+type FrameAnalysis struct {
+	FrameInfo
+
+	// Power is the mean square of the samples of each channel over the
+	// frame, on a scale where full-scale samples have a power of 1. It is
+	// computed from the frequency lines, so it can differ slightly from the
+	// power of the decoded PCM, whose granules overlap. Mono frames have the
+	// same power in both channels.
+	Power [2]float64
+}
+
+// AnalyzeFrame reads the next frame and decodes it only up to its frequency
+// lines, without running the synthesis filterbank, which takes most of the
+// decoding time. It lets tools that only need frame metadata or levels, such
+// as library scanners, go through a stream several times faster than
+// decoding it.
+//
+// The PCM left over from the current frame and the priming silence of
+// WithPrimingSilence are skipped, and the position advances past the frame.
+// The synthesis state is not updated, so PCM read after AnalyzeFrame
+// glitches for a granule or two; Seek restores exact output. At the end of
+// the stream, AnalyzeFrame returns io.EOF.
+func (d *Decoder) AnalyzeFrame() (FrameAnalysis, error) {
+	d.pos = max(d.pos, d.priming) + int64(len(d.blockTail)+d.buf.Len())
+	d.buf.Reset()
+	d.blockTail = d.blockTail[:0]
+	if d.pending {
+		// The first frame, read by NewDecoder.
+		d.pending = false
+	} else {
+		if err := d.awaitFrameData(); err != nil {
+			return FrameAnalysis{}, err
+		}
+		pos := d.source.pos
+		if err := d.nextFrame(); err != nil {
+			return FrameAnalysis{}, err
+		}
+		if d.length == invalidLength {
+			d.estimate.addFrame(d.source.pos-pos, d.pcmBytes(d.frame.Header()))
+		}
+	}
+	h := d.frame.Header()
+	d.pos += d.pcmBytes(h)
+
+	a := FrameAnalysis{FrameInfo: d.frameInfo()}
+	granules := d.frame.Analyze()
+	nch := h.NumberOfChannels()
+	for gr := range granules {
+		for ch := range nch {
+			lines := d.frame.Lines(gr, ch)
+			if d.spectrum != nil {
+				d.reportSpectrum(gr, ch, lines)
+			}
+			for _, v := range lines {
+				a.Power[ch] += float64(v) * float64(v)
+			}
+		}
+	}
+	// The power of a granule of 576 samples is half the sum of the squares
+	// of its lines.
+	for ch := range nch {
+		a.Power[ch] /= float64(2 * granules)
+	}
+	if nch == 1 {
+		a.Power[1] = a.Power[0]
+	}
+	return a, nil
+}
@@ -0,0 +1,57 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"testing"
+)
+
+func TestAnalyzeFrame(t *testing.T) {
+	for _, name := range []string{"example/classic_lame.mp3", "example/mpeg2.mp3"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("failed to read test file: %v", err)
+		}
+		_, pcm := decodeFresh(t, data)
+		var pcmPower float64
+		for i := 0; i < len(pcm); i += 2 {
+			s := float64(int16(uint16(pcm[i])|uint16(pcm[i+1])<<8)) / 32768
+			pcmPower += s * s
+		}
+		pcmPower /= float64(len(pcm) / 2)
+
+		d, err := NewDecoder(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var power float64
+		n := 0
+		for {
+			a, err := d.AnalyzeFrame()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: AnalyzeFrame: %v", name, err)
+			}
+			if info, _ := d.FrameInfo(n); a.FrameInfo != info {
+				t.Fatalf("%s: frame %d: info %+v, want %+v", name, n, a.FrameInfo, info)
+			}
+			power += a.Power[0] + a.Power[1]
+			n++
+		}
+		if n != d.FrameCount() {
+			t.Errorf("%s: analyzed %d frames, want %d", name, n, d.FrameCount())
+		}
+		if d.SamplePosition() != d.SampleCount() {
+			t.Errorf("%s: sample position %d at the end, want %d", name, d.SamplePosition(), d.SampleCount())
+		}
+		power /= float64(2 * n)
+		if math.Abs(power-pcmPower) > pcmPower/20 {
+			t.Errorf("%s: mean power %g, want about %g", name, power, pcmPower)
+		}
+	}
+}
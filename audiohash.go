@@ -0,0 +1,90 @@
+package mp3
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+)
+
+// AudioHash writes the audio frames of the stream to h and returns the
+// resulting hash, such as an MD5 or SHA-256 sum. The ID3v2, ID3v1 and APE
+// tags of the stream, its Xing header frame, whose counts taggers sometimes
+// rewrite, and the bytes between or after the frames are left out, so that
+// copies of the same rip tagged differently have the same hash. h is not
+// reset first.
+//
+// AudioHash returns an error if the source is not io.Seeker. The position of
+// the decoder is left unchanged.
+func (d *Decoder) AudioHash(h hash.Hash) ([]byte, error) {
+	if d.length == invalidLength {
+		return nil, errors.New("mp3: AudioHash requires a seekable source")
+	}
+	pos := d.source.pos
+	defer func() {
+		_, _ = d.source.Seek(pos, io.SeekStart)
+	}()
+	first := d.firstAudioFrame()
+	end, err := d.source.trailingTagsStart()
+	if err != nil {
+		return nil, err
+	}
+	if d.audioEnd >= 0 {
+		end = min(end, d.audioEnd)
+	}
+	// Leave out the frames found in the tags at the end of the stream and
+	// a truncated last frame.
+	last := int64(len(d.frameStarts))
+	for last > first {
+		size, err := d.frameHeaders[last-1].FrameSize()
+		if err == nil && d.frameStarts[last-1]+int64(size) <= end {
+			break
+		}
+		last--
+	}
+	if _, _, err := d.writeFrames(h, first, last); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// trailingTagsStart returns the offset of the ID3v1 and APE tags at the end
+// of the stream, or its size if there are none. The source position is left
+// undefined.
+func (s *source) trailingTagsStart() (int64, error) {
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	buf := make([]byte, 32)
+	if end >= 128 {
+		if _, err := s.Seek(end-128, io.SeekStart); err != nil {
+			return 0, err
+		}
+		if _, err := s.ReadFull(buf[:3]); err != nil {
+			return 0, err
+		}
+		if string(buf[:3]) == "TAG" {
+			end -= 128
+		}
+	}
+	if end < 32 {
+		return end, nil
+	}
+	// An APE tag ends with a 32-byte footer holding the size of the tag
+	// without its header, and a flag telling whether it has one.
+	if _, err := s.Seek(end-32, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if _, err := s.ReadFull(buf); err != nil {
+		return 0, err
+	}
+	if string(buf[:8]) != "APETAGEX" {
+		return end, nil
+	}
+	size := int64(binary.LittleEndian.Uint32(buf[12:]))
+	if buf[23]&0x80 != 0 {
+		size += 32
+	}
+	return max(end-size, 0), nil
+}
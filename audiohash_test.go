@@ -0,0 +1,97 @@
+package mp3
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+func TestAudioHash(t *testing.T) {
+	plain, err := testsupport.Generate(testsupport.Options{Frames: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagged, err := testsupport.Generate(testsupport.Options{
+		Frames: 10, Xing: true, ID3v1: true, Tags: map[string]string{"TIT2": "Title"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An empty APE tag: a header and a footer, which are alike.
+	ape := createAPETagHeader(32)
+	apeTagged := slices.Concat(plain, ape, ape)
+	longer, err := testsupport.Generate(testsupport.Options{Frames: 11})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := func(data []byte) string {
+		d, err := NewDecoder(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum, err := d.AudioHash(md5.New())
+		if err != nil {
+			t.Fatalf("AudioHash() error = %v", err)
+		}
+		return string(sum)
+	}
+	want := hash(plain)
+	if got := hash(tagged); got != want {
+		t.Errorf("hash of the tagged stream %x, want %x", got, want)
+	}
+	if got := hash(apeTagged); got != want {
+		t.Errorf("hash of the stream with an APE tag %x, want %x", got, want)
+	}
+	if got := hash(longer); got == want {
+		t.Error("streams with different audio have the same hash")
+	}
+}
+
+func TestAudioHash_Position(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{Frames: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4*1152*3)
+	if _, err := d.Read(buf[:4*1152]); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := d.AudioHash(sha256.New())
+	if err != nil {
+		t.Fatalf("AudioHash() error = %v", err)
+	}
+	if len(sum) != sha256.Size {
+		t.Errorf("len(sum) = %d, want %d", len(sum), sha256.Size)
+	}
+	rest, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 4 * 1152 * 9; len(rest) != want {
+		t.Errorf("read %d bytes after AudioHash, want %d", len(rest), want)
+	}
+}
+
+func TestAudioHash_NonSeekable(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{Frames: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDecoder(nonSeekable{bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.AudioHash(md5.New()); err == nil {
+		t.Error("AudioHash() error = nil, want an error")
+	}
+}
@@ -0,0 +1,93 @@
+package mp3
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// A FileFunc processes a file opened by DecodeFiles, typically by reading
+// its decoded audio from d. The file is closed when it returns. It can
+// report the progress within the file with d.Progress.
+type FileFunc func(ctx context.Context, path string, d *Decoder) error
+
+// A FileResult is the outcome of processing a file with DecodeFiles.
+type FileResult struct {
+	// Path is the path of the file.
+	Path string
+
+	// Err is the error met opening the file, creating its decoder or
+	// returned by the FileFunc, or the error of the context if the file was
+	// not processed because it was canceled.
+	Err error
+
+	// Duration is the duration of the audio of the file, and Elapsed the
+	// time taken to process it.
+	Duration time.Duration
+	Elapsed  time.Duration
+}
+
+// DecodeFiles opens the files of paths with decoders created with opts and
+// calls fn for each of them, processing up to workers files at once, for
+// library scanners and transcoding farms that handle many files. With
+// workers 0 or less, GOMAXPROCS files are processed at once. fn is called
+// from several goroutines.
+//
+// If progress is not nil, it is called with the result of each file as soon
+// as it is done, along with the number of files done so far, from a single
+// goroutine at a time.
+//
+// DecodeFiles returns the results of the files in the order of paths, once
+// all of them are done. The files not started when ctx is canceled are
+// skipped and get the error of ctx.
+func DecodeFiles(ctx context.Context, paths []string, workers int, fn FileFunc, progress func(r FileResult, done int), opts ...Option) []FileResult {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	results := make([]FileResult, len(paths))
+	jobs := make(chan int)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+	)
+	for range min(workers, len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r := FileResult{Path: paths[i]}
+				if r.Err = ctx.Err(); r.Err == nil {
+					start := time.Now()
+					r.Duration, r.Err = decodeFile(ctx, paths[i], fn, opts)
+					r.Elapsed = time.Since(start)
+				}
+				results[i] = r
+				if progress != nil {
+					mu.Lock()
+					done++
+					progress(r, done)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// decodeFile opens the file path and calls fn with its decoder. It returns
+// the duration of the audio.
+func decodeFile(ctx context.Context, path string, fn FileFunc, opts []Option) (time.Duration, error) {
+	d, err := NewDecoderFromFile(path, opts...)
+	if err != nil {
+		return 0, err
+	}
+	defer d.Close()
+	return d.Duration(), fn(ctx, path, d)
+}
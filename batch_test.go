@@ -0,0 +1,126 @@
+package mp3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+// writeFiles writes n generated streams of increasing length to a temporary
+// directory and returns their paths.
+func writeFiles(t *testing.T, n int) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var paths []string
+	for i := range n {
+		data, err := testsupport.Generate(testsupport.Options{Frames: 10 + i})
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, string(rune('a'+i))+".mp3")
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestDecodeFiles(t *testing.T) {
+	paths := writeFiles(t, 6)
+	paths = append(paths, filepath.Join(t.TempDir(), "missing.mp3"))
+	fnErr := errors.New("fn error")
+
+	var running, maxRunning atomic.Int32
+	var mu sync.Mutex
+	decoded := map[string]int{}
+	fn := func(_ context.Context, path string, d *Decoder) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		pcm, err := io.ReadAll(d)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		decoded[path] = len(pcm)
+		mu.Unlock()
+		if path == paths[2] {
+			return fnErr
+		}
+		return nil
+	}
+	var dones []int
+	progress := func(_ FileResult, done int) {
+		dones = append(dones, done)
+	}
+
+	results := DecodeFiles(context.Background(), paths, 2, fn, progress)
+	if len(results) != len(paths) {
+		t.Fatalf("got %d results, want %d", len(results), len(paths))
+	}
+	for i, r := range results {
+		if r.Path != paths[i] {
+			t.Errorf("results[%d].Path = %s, want %s", i, r.Path, paths[i])
+		}
+		switch i {
+		case 2:
+			if !errors.Is(r.Err, fnErr) {
+				t.Errorf("results[2].Err = %v, want the error of fn", r.Err)
+			}
+		case 6:
+			if !errors.Is(r.Err, os.ErrNotExist) {
+				t.Errorf("results[6].Err = %v, want os.ErrNotExist", r.Err)
+			}
+		default:
+			if r.Err != nil {
+				t.Errorf("results[%d].Err = %v", i, r.Err)
+			}
+			if want := (10 + i) * 1152 * 4; decoded[r.Path] != want {
+				t.Errorf("decoded %d bytes of %s, want %d", decoded[r.Path], r.Path, want)
+			}
+			if r.Duration <= 0 || r.Elapsed <= 0 {
+				t.Errorf("results[%d] = %+v, want a duration and elapsed time", i, r)
+			}
+		}
+	}
+	if m := maxRunning.Load(); m != 2 {
+		t.Errorf("up to %d files processed at once, want 2", m)
+	}
+	if len(dones) != len(paths) || dones[len(dones)-1] != len(paths) {
+		t.Errorf("progress called with %v, want 1 to %d", dones, len(paths))
+	}
+}
+
+func TestDecodeFiles_Canceled(t *testing.T) {
+	paths := writeFiles(t, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	results := DecodeFiles(ctx, paths, 0, func(context.Context, string, *Decoder) error {
+		called = true
+		return nil
+	}, nil)
+	if called {
+		t.Error("fn called after the context was canceled")
+	}
+	for i, r := range results {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("results[%d].Err = %v, want context.Canceled", i, r.Err)
+		}
+	}
+}
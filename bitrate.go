@@ -0,0 +1,57 @@
+package mp3
+
+// Bitrate returns the bitrate in bits per second of the current frame, the
+// frame whose PCM Read returns next, so that players can display the live
+// bitrate of VBR streams. It returns 0 when there is no current frame.
+func (d *Decoder) Bitrate() int {
+	h, ok := d.currentHeader()
+	if !ok {
+		return 0
+	}
+	return h.Bitrate()
+}
+
+// AverageBitrate returns the average bitrate in bits per second of the
+// stream, the size of its audio frames over their duration. For io.Seeker
+// sources it comes from the frames found by the scan made when the decoder
+// is created. Otherwise it comes from the frame and byte counts of the Xing
+// header, or without them from the frames read so far.
+func (d *Decoder) AverageBitrate() int {
+	if d.length != invalidLength {
+		headers := d.frameHeaders
+		if d.xing != nil {
+			headers = headers[1:]
+		}
+		var bits, seconds float64
+		for _, h := range headers {
+			size, err := h.FrameSize()
+			if err != nil {
+				continue
+			}
+			rate, err := h.SamplingFrequencyValue()
+			if err != nil {
+				continue
+			}
+			bits += float64(8 * size)
+			seconds += float64(h.SamplesPerFrame()) / float64(rate)
+		}
+		if seconds == 0 {
+			return 0
+		}
+		return int(bits / seconds)
+	}
+
+	h, ok := d.currentHeader()
+	if x := d.xing; x != nil && x.HasFrameCount() && x.HasByteCount() && x.FrameCount > 0 && ok {
+		if rate, err := h.SamplingFrequencyValue(); err == nil {
+			samples := int64(x.FrameCount) * int64(h.SamplesPerFrame())
+			return int(8 * int64(x.ByteCount) * int64(rate) / samples)
+		}
+	}
+	e := &d.estimate
+	if e.pcm == 0 {
+		return 0
+	}
+	// pcm holds 4 bytes per sample at the output rate.
+	return int(8 * e.frameBytes * 4 * int64(d.sampleRate) / e.pcm)
+}
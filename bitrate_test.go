@@ -0,0 +1,72 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"testing"
+)
+
+func TestBitrate_FollowsFrames(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	seen := map[int]bool{}
+	for {
+		_, _, err := d.DecodeFrame()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("DecodeFrame failed: %v", err)
+		}
+		info, _ := d.CurrentFrameInfo()
+		if got, want := d.Bitrate(), info.Header.Bitrate; got != want {
+			t.Fatalf("Bitrate = %d, want %d of the frame at %d", got, want, info.Offset)
+		}
+		seen[d.Bitrate()] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("Bitrate took the values %v, want several on a VBR stream", seen)
+	}
+}
+
+func TestAverageBitrate(t *testing.T) {
+	for _, name := range []string{"example/classic_lame.mp3", "example/mpeg2.mp3"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("failed to read test file: %v", err)
+		}
+		d, err := NewDecoder(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("NewDecoder failed: %v", err)
+		}
+		s, err := d.StreamStats()
+		if err != nil {
+			t.Fatalf("StreamStats failed: %v", err)
+		}
+		if got := d.AverageBitrate(); math.Abs(float64(got-s.AverageBitrate)) > 1 {
+			t.Errorf("%s: AverageBitrate = %d, want %d", name, got, s.AverageBitrate)
+		}
+
+		// Without seeking, the Xing header or the frames read give an
+		// estimate.
+		d, err = NewDecoder(&nonSeekableReader{bytes.NewReader(data)})
+		if err != nil {
+			t.Fatalf("NewDecoder failed: %v", err)
+		}
+		if _, err := io.Copy(io.Discard, d); err != nil {
+			t.Fatalf("decoding failed: %v", err)
+		}
+		if got := d.AverageBitrate(); math.Abs(float64(got-s.AverageBitrate)) > 0.02*float64(s.AverageBitrate) {
+			t.Errorf("%s: non-seekable AverageBitrate = %d, want about %d", name, got, s.AverageBitrate)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+)
+
+// WithBlockSize makes Read return whole blocks of the given number of
+// samples (per channel), such as the 441 samples of a 10 ms period at
+// 44100 Hz, for audio APIs that consume fixed-size periods.
+//
+// Read then returns a multiple of the block size, carrying the PCM left
+// over from a frame to the next call, and returns io.ErrShortBuffer for
+// buffers smaller than a block. The last block of the stream is padded with
+// silence. The padding is not counted in Length or Position.
+func WithBlockSize(samples int) Option {
+	return func(c *config) {
+		c.blockSamples = max(samples, 0)
+	}
+}
+
+// readBlocks implements Read for decoders with a block size.
+func (d *Decoder) readBlocks(buf []byte) (int, error) {
+	bs := d.blockBytes
+	size := len(buf) / bs * bs
+	if size == 0 {
+		return 0, io.ErrShortBuffer
+	}
+	n := copy(buf[:size], d.blockTail)
+	d.pos += int64(n)
+	d.blockTail = d.blockTail[:0]
+	for n < bs {
+		m, err := d.readPCM(buf[n:size])
+		n += m
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, io.EOF) && n > 0 {
+			pad := bs - n%bs
+			if pad == bs {
+				return n, nil
+			}
+			clear(buf[n : n+pad])
+			return n + pad, nil
+		}
+		d.keepBlockTail(buf[:n])
+		return 0, err
+	}
+	whole := n / bs * bs
+	d.keepBlockTail(buf[whole:n])
+	return whole, nil
+}
+
+// keepBlockTail keeps b, which was read but not returned, for the next call
+// to readBlocks.
+func (d *Decoder) keepBlockTail(b []byte) {
+	d.blockTail = append(d.blockTail, b...)
+	d.pos -= int64(len(b))
+}
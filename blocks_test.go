@@ -0,0 +1,109 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestWithBlockSize(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	ref, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := io.ReadAll(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const block = 441
+	d, err := NewDecoder(bytes.NewReader(data), WithBlockSize(block))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []byte
+	for i := 0; ; i++ {
+		// Vary the buffer size, including sizes that are not whole blocks.
+		buf := make([]byte, (1+i%5)*block*4+i%7)
+		n, err := d.Read(buf)
+		if n%(block*4) != 0 {
+			t.Fatalf("Read returned %d bytes, not a multiple of the block size", n)
+		}
+		got = append(got, buf[:n]...)
+		if pos, _ := d.Seek(0, io.SeekCurrent); pos != min(int64(len(got)), int64(len(want))) {
+			t.Fatalf("position = %d after %d bytes read", pos, len(got))
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(got)%(block*4) != 0 || len(got)-len(want) >= block*4 || len(got) < len(want) {
+		t.Fatalf("read %d bytes, want %d padded to a whole block", len(got), len(want))
+	}
+	if !bytes.Equal(got[:len(want)], want) {
+		t.Error("blocked output differs from unblocked output")
+	}
+	if !bytes.Equal(got[len(want):], make([]byte, len(got)-len(want))) {
+		t.Error("last block is not padded with silence")
+	}
+}
+
+func TestWithBlockSize_ShortBufferAndSeek(t *testing.T) {
+	f, err := os.Open("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	const block = 480
+	d, err := NewDecoder(f, WithBlockSize(block))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Read(make([]byte, block*4-1)); !errors.Is(err, io.ErrShortBuffer) {
+		t.Errorf("Read into a buffer smaller than a block: error = %v, want io.ErrShortBuffer", err)
+	}
+
+	// Leave PCM of a partial block behind, then seek.
+	if _, err := d.Read(make([]byte, block*4)); err != nil {
+		t.Fatal(err)
+	}
+	target := int64(100000 * 4)
+	if _, err := d.Seek(target, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, block*4)
+	if _, err := io.ReadFull(d, got); err != nil {
+		t.Fatal(err)
+	}
+
+	f2, err := os.Open("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f2.Close()
+	ref, err := NewDecoder(f2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ref.Seek(target, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	want := make([]byte, block*4)
+	if _, err := io.ReadFull(ref, want); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("PCM after Seek does not match the reference")
+	}
+}
@@ -0,0 +1,232 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"time"
+)
+
+// Parameters of the track boundary analysis.
+const (
+	// boundaryWindow is the length of the analysis windows.
+	boundaryWindow = 100 * time.Millisecond
+
+	// boundaryMinGap is the shortest quiet stretch proposed as a boundary.
+	boundaryMinGap = time.Second
+
+	// boundaryFullGap is the length of a quiet stretch that is a boundary
+	// with full confidence as far as its length goes.
+	boundaryFullGap = 3 * time.Second
+
+	// boundarySilenceRange is how far, in dB, a window must be below the
+	// loud level of the recording to be quiet. It is relative so that the
+	// surface noise of vinyl and tape counts as silence.
+	boundarySilenceRange = 35
+
+	// boundaryContext is the length of audio on each side of a gap whose
+	// spectra are compared.
+	boundaryContext = 5 * time.Second
+
+	// boundarySilenceDB is the level, in dB, given to digital silence.
+	boundarySilenceDB = -120
+)
+
+// A TrackBoundary is a proposed split point between two tracks of a long
+// recording, such as a digitized tape or vinyl side.
+type TrackBoundary struct {
+	// Time is the proposed split point: the middle of the gap.
+	Time time.Duration
+
+	// GapStart and GapEnd delimit the quiet stretch between the tracks.
+	GapStart time.Duration
+	GapEnd   time.Duration
+
+	// Confidence rates the boundary from 0 to 1. Long, deep gaps between
+	// audio of different spectral character score highest; a short pause
+	// within a song scores low.
+	Confidence float64
+}
+
+// windowFeatures holds the features of an analysis window.
+type windowFeatures struct {
+	// power is the mean square of the samples.
+	power float64
+
+	// brightness is the energy of the first difference of the signal
+	// relative to its energy, which grows with the spectral centroid.
+	brightness float64
+}
+
+// boundaryAnalyzer computes track boundaries from a sequence of stereo
+// samples.
+type boundaryAnalyzer struct {
+	windowLen  int
+	inWindow   int
+	energy     float64
+	diffEnergy float64
+	prev       float64
+	windows    []windowFeatures
+}
+
+func newBoundaryAnalyzer(sampleRate int) *boundaryAnalyzer {
+	return &boundaryAnalyzer{
+		windowLen: int(int64(sampleRate) * int64(boundaryWindow) / int64(time.Second)),
+	}
+}
+
+// add adds a sample of each channel, scaled to [-1, 1].
+func (a *boundaryAnalyzer) add(l, r float64) {
+	m := (l + r) / 2
+	d := m - a.prev
+	a.prev = m
+	a.energy += m * m
+	a.diffEnergy += d * d
+	a.inWindow++
+	if a.inWindow == a.windowLen {
+		a.closeWindow()
+	}
+}
+
+// level returns the RMS level of w in dBFS.
+func (w windowFeatures) level() float64 {
+	if w.power == 0 {
+		return boundarySilenceDB
+	}
+	return max(10*math.Log10(w.power), boundarySilenceDB)
+}
+
+func (a *boundaryAnalyzer) closeWindow() {
+	w := windowFeatures{power: a.energy / float64(a.inWindow)}
+	if a.energy > 0 {
+		w.brightness = a.diffEnergy / a.energy
+	}
+	a.windows = append(a.windows, w)
+	a.inWindow = 0
+	a.energy = 0
+	a.diffEnergy = 0
+}
+
+func (a *boundaryAnalyzer) windowTime(i int) time.Duration {
+	return time.Duration(i) * boundaryWindow
+}
+
+func (a *boundaryAnalyzer) report() []TrackBoundary {
+	if a.inWindow > 0 {
+		a.closeWindow()
+	}
+	if len(a.windows) == 0 {
+		return nil
+	}
+	levels := make([]float64, len(a.windows))
+	for i, w := range a.windows {
+		levels[i] = w.level()
+	}
+	slices.Sort(levels)
+	loud := levels[len(levels)*9/10]
+	if loud <= boundarySilenceDB {
+		return nil
+	}
+	threshold := loud - boundarySilenceRange
+	quiet := silencePower(threshold)
+
+	var boundaries []TrackBoundary
+	minGap := int(boundaryMinGap / boundaryWindow)
+	for i := 0; i < len(a.windows); {
+		if a.windows[i].power >= quiet {
+			i++
+			continue
+		}
+		start := i
+		for i < len(a.windows) && a.windows[i].power < quiet {
+			i++
+		}
+		// Silence at the very start or end of the recording is lead-in or
+		// run-out, not a boundary.
+		if start == 0 || i == len(a.windows) || i-start < minGap {
+			continue
+		}
+		boundaries = append(boundaries, a.boundary(start, i, threshold))
+	}
+	return boundaries
+}
+
+// boundary rates the gap made of windows [start, end).
+func (a *boundaryAnalyzer) boundary(start, end int, threshold float64) TrackBoundary {
+	gap := a.windowTime(end - start)
+	length := min(float64(gap)/float64(boundaryFullGap), 1)
+	var level float64
+	for _, w := range a.windows[start:end] {
+		level += w.level()
+	}
+	level /= float64(end - start)
+	depth := min((threshold-level)/boundarySilenceRange, 1)
+	silence := (length + depth) / 2
+
+	side := int(boundaryContext / boundaryWindow)
+	quiet := silencePower(threshold)
+	before := a.brightness(max(start-side, 0), start, quiet)
+	after := a.brightness(end, min(end+side, len(a.windows)), quiet)
+	var change float64
+	if before+after > 0 {
+		change = math.Abs(before-after) / (before + after)
+	}
+
+	return TrackBoundary{
+		Time:       a.windowTime(start+end) / 2,
+		GapStart:   a.windowTime(start),
+		GapEnd:     a.windowTime(end),
+		Confidence: min(0.7*silence+0.3*change, 1),
+	}
+}
+
+// brightness returns the average brightness of the windows in [start, end)
+// whose power is not below quiet.
+func (a *boundaryAnalyzer) brightness(start, end int, quiet float64) float64 {
+	var sum float64
+	n := 0
+	for _, w := range a.windows[start:end] {
+		if w.power >= quiet {
+			sum += w.brightness
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// TrackBoundaries decodes r, a long recording holding several tracks, and
+// proposes split points between them. Boundaries are found in quiet stretches
+// of at least a second, relative to the loudness of the recording, and are
+// rated by the length and depth of the gap and by how much the spectral
+// character of the audio changes across it.
+//
+// The boundaries are returned in order; silence at the start and end of the
+// recording is not reported. Applications typically keep boundaries above a
+// confidence threshold and let the user review the others.
+func TrackBoundaries(r io.Reader) ([]TrackBoundary, error) {
+	d, err := NewDecoder(r)
+	if err != nil {
+		return nil, err
+	}
+	a := newBoundaryAnalyzer(d.SampleRate())
+	buf := make([]byte, 16*1024)
+	for {
+		n, err := d.Read(buf)
+		for i := 0; i+3 < n; i += 4 {
+			l := float64(int16(uint16(buf[i])|uint16(buf[i+1])<<8)) / 32768
+			r := float64(int16(uint16(buf[i+2])|uint16(buf[i+3])<<8)) / 32768
+			a.add(l, r)
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+	}
+	return a.report(), nil
+}
@@ -0,0 +1,92 @@
+package mp3
+
+import (
+	"math"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+)
+
+// addSeconds feeds sec seconds of signal f(i) at 1000 Hz to a.
+func addSeconds(a *boundaryAnalyzer, sec int, f func(i int) float64) {
+	for i := range sec * 1000 {
+		s := f(i)
+		a.add(s, s)
+	}
+}
+
+func tone(i int) float64 { return 0.5 * math.Sin(float64(i)*0.2) }
+
+func TestBoundaryAnalyzer_GapBetweenDifferentTracks(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	noise := func(int) float64 { return 0.3 * (rng.Float64()*2 - 1) }
+	hiss := func(int) float64 { return 0.0001 * (rng.Float64()*2 - 1) }
+
+	a := newBoundaryAnalyzer(1000)
+	addSeconds(a, 1, hiss) // Lead-in
+	addSeconds(a, 20, tone)
+	addSeconds(a, 3, hiss)
+	addSeconds(a, 20, noise)
+	addSeconds(a, 2, hiss) // Run-out
+	got := a.report()
+	if len(got) != 1 {
+		t.Fatalf("found %d boundaries, want 1: %+v", len(got), got)
+	}
+	b := got[0]
+	if b.GapStart != 21*time.Second || b.GapEnd != 24*time.Second || b.Time != 22500*time.Millisecond {
+		t.Errorf("boundary = %+v, want a gap from 21s to 24s", b)
+	}
+	if b.Confidence < 0.9 {
+		t.Errorf("Confidence = %v, want at least 0.9", b.Confidence)
+	}
+}
+
+func TestBoundaryAnalyzer_SimilarTracksScoreLower(t *testing.T) {
+	a := newBoundaryAnalyzer(1000)
+	addSeconds(a, 20, tone)
+	addSeconds(a, 1, func(int) float64 { return 0 })
+	addSeconds(a, 20, tone)
+	got := a.report()
+	if len(got) != 1 {
+		t.Fatalf("found %d boundaries, want 1: %+v", len(got), got)
+	}
+	if c := got[0].Confidence; c < 0.3 || c > 0.7 {
+		t.Errorf("Confidence = %v for a short gap between similar tracks, want between 0.3 and 0.7", c)
+	}
+}
+
+func TestBoundaryAnalyzer_ShortPauseAndSilence(t *testing.T) {
+	a := newBoundaryAnalyzer(1000)
+	addSeconds(a, 10, tone)
+	for range 300 {
+		a.add(0, 0)
+	}
+	addSeconds(a, 10, tone)
+	if got := a.report(); len(got) != 0 {
+		t.Errorf("short pause reported as boundaries: %+v", got)
+	}
+
+	a = newBoundaryAnalyzer(1000)
+	addSeconds(a, 10, func(int) float64 { return 0 })
+	if got := a.report(); len(got) != 0 {
+		t.Errorf("silence reported as boundaries: %+v", got)
+	}
+}
+
+func TestTrackBoundaries_RealFile(t *testing.T) {
+	f, err := os.Open("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	got, err := TrackBoundaries(f)
+	if err != nil {
+		t.Fatalf("TrackBoundaries failed: %v", err)
+	}
+	// A single piece of music has no track boundary.
+	if len(got) != 0 {
+		t.Errorf("TrackBoundaries = %+v, want none", got)
+	}
+}
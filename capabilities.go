@@ -0,0 +1,134 @@
+package mp3
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// modulePath is the module path of this package, used to find its version
+// in the build information.
+const modulePath = "github.com/llehouerou/go-mp3"
+
+// A CapabilityReport describes what this build of the package supports. It
+// is meant to let applications adapt their behavior and to be attached to
+// bug reports, for which it can be marshaled to JSON.
+type CapabilityReport struct {
+	// Version is the version of this module in the running binary, such as
+	// "v1.2.0", or "(devel)" or "" when it is not known.
+	Version string `json:"version"`
+
+	// Layers lists the MPEG audio layers that can be decoded.
+	Layers []int `json:"layers"`
+
+	// MPEGVersions lists the MPEG versions that can be decoded.
+	MPEGVersions []string `json:"mpegVersions"`
+
+	// SIMD lists the SIMD code paths active in this build. The decoder is
+	// written in pure Go, so it is empty.
+	SIMD []string `json:"simd"`
+
+	// Features lists the optional features available, by name.
+	Features []string `json:"features"`
+
+	// GoVersion, GOOS and GOARCH describe the Go toolchain and platform of
+	// the build.
+	GoVersion string `json:"goVersion"`
+	GOOS      string `json:"goos"`
+	GOARCH    string `json:"goarch"`
+}
+
+// features lists the optional features of the package.
+var features = []string{
+	"id3v2",
+	"id3v2-chapters",
+	"id3v2-lyrics",
+	"xing",
+	"lame-tag",
+	"reservoir-free",
+	"read-deadline",
+	"live-source",
+	"priming-silence",
+	"block-size",
+	"duration-estimate",
+	"set-source",
+	"frame-decode",
+	"transform",
+	"output-format",
+	"planar",
+	"karaoke",
+	"gain",
+	"dither",
+	"equalizer",
+	"half-rate",
+	"analysis",
+	"spectrum",
+	"meter",
+	"silence",
+	"peaks",
+	"copy-range",
+	"concat",
+	"split",
+	"audio-hash",
+	"music-crc",
+	"validate",
+	"batch",
+	"limits",
+	"mem-stats",
+	"clone",
+	"realtime",
+	"loop",
+	"range",
+	"fs",
+	"pcm-buffer",
+	"resample",
+	"speed",
+	"tag-func",
+	"tag-info",
+	"truncated-frame",
+	"padding-trim",
+	"music-length",
+	"bitrate",
+	"channels",
+	"mode-stats",
+	"scan-progress",
+	"deferred-first-frame",
+	"seek-fraction",
+	"unaligned-seek",
+	"sample-rate-change",
+	"mixed-frame-length",
+	"frame-offsets",
+	"input-position",
+}
+
+// Capabilities returns a report of what this build supports.
+func Capabilities() CapabilityReport {
+	return CapabilityReport{
+		Version:      moduleVersion(),
+		Layers:       []int{3},
+		MPEGVersions: []string{"1", "2"},
+		SIMD:         []string{},
+		Features:     append([]string(nil), features...),
+		GoVersion:    runtime.Version(),
+		GOOS:         runtime.GOOS,
+		GOARCH:       runtime.GOARCH,
+	}
+}
+
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package mp3
+
+import (
+	"encoding/json"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	c := Capabilities()
+	if !slices.Equal(c.Layers, []int{3}) {
+		t.Errorf("Layers = %v, want [3]", c.Layers)
+	}
+	if !slices.Equal(c.MPEGVersions, []string{"1", "2"}) {
+		t.Errorf("MPEGVersions = %v, want [1 2]", c.MPEGVersions)
+	}
+	if c.GOARCH != runtime.GOARCH || c.GOOS != runtime.GOOS || c.GoVersion != runtime.Version() {
+		t.Errorf("platform = %s %s/%s, want %s %s/%s", c.GoVersion, c.GOOS, c.GOARCH,
+			runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	}
+	if !slices.Contains(c.Features, "xing") {
+		t.Errorf("Features = %v, want xing", c.Features)
+	}
+
+	// The report must not share the feature list.
+	c.Features[0] = "changed"
+	if Capabilities().Features[0] == "changed" {
+		t.Error("Capabilities returned the internal feature list")
+	}
+}
+
+func TestCapabilities_JSON(t *testing.T) {
+	b, err := json.Marshal(Capabilities())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"version", "layers", "mpegVersions", "simd", "features", "goVersion", "goos", "goarch"} {
+		if _, ok := m[key]; !ok {
+			t.Errorf("JSON report %s has no %q", b, key)
+		}
+	}
+	if m["simd"] == nil {
+		t.Error("simd is null, want an empty list")
+	}
+}
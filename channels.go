@@ -0,0 +1,38 @@
+package mp3
+
+import "github.com/llehouerou/go-mp3/frameheader"
+
+// Channels returns the number of channels of the current frame, 1 or 2.
+// Read always returns stereo, with the channel of mono streams copied to
+// both sides, so this tells what the source really is. It returns 0 when
+// there is no current frame.
+func (d *Decoder) Channels() int {
+	h, ok := d.currentHeader()
+	if !ok {
+		return 0
+	}
+	return h.NumberOfChannels()
+}
+
+// Mode returns the channel mode of the current frame: stereo, joint stereo,
+// dual channel or mono. For joint stereo, ModeExtension tells which joint
+// stereo coding the frame uses. Mode returns ModeStereo when there is no
+// current frame.
+func (d *Decoder) Mode() frameheader.Mode {
+	h, ok := d.currentHeader()
+	if !ok {
+		return frameheader.ModeStereo
+	}
+	return frameheader.Mode(h.Mode())
+}
+
+// ModeExtension returns the joint stereo coding bits of the current frame:
+// bit 0 for intensity stereo and bit 1 for middle/side stereo. It is only
+// meaningful when Mode returns ModeJointStereo.
+func (d *Decoder) ModeExtension() int {
+	h, ok := d.currentHeader()
+	if !ok {
+		return 0
+	}
+	return h.ModeExtension()
+}
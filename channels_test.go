@@ -0,0 +1,36 @@
+package mp3
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/frameheader"
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+func TestChannelsAndMode(t *testing.T) {
+	for _, mono := range []bool{false, true} {
+		data, err := testsupport.Generate(testsupport.Options{Mono: mono})
+		if err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		d, err := NewDecoder(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("NewDecoder failed: %v", err)
+		}
+		info, _ := d.CurrentFrameInfo()
+		wantChannels, wantMode := 2, frameheader.ModeJointStereo
+		if mono {
+			wantChannels, wantMode = 1, frameheader.ModeSingleChannel
+		}
+		if d.Channels() != wantChannels {
+			t.Errorf("mono %v: Channels = %d, want %d", mono, d.Channels(), wantChannels)
+		}
+		if d.Mode() != wantMode {
+			t.Errorf("mono %v: Mode = %v, want %v", mono, d.Mode(), wantMode)
+		}
+		if d.ModeExtension() != info.Header.ModeExtension {
+			t.Errorf("mono %v: ModeExtension = %d, want %d", mono, d.ModeExtension(), info.Header.ModeExtension)
+		}
+	}
+}
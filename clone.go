@@ -0,0 +1,103 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// Clone returns a new Decoder of the same stream, positioned like d, so that
+// several goroutines can decode different parts of one file concurrently,
+// e.g. a waveform renderer and a player. The source must implement
+// io.ReaderAt and io.Seeker; the clone reads it with ReadAt only, and shares
+// the frame index and the metadata of d instead of scanning the stream
+// again.
+//
+// The clone has the settings of d, including the callbacks, which are
+// called from the goroutine using the clone. Measurements such as the peaks
+// and the stereo statistics start over.
+//
+// Clone must not be called concurrently with other methods of d, but the
+// decoders can be used concurrently afterwards. The clone does not own the
+// file of NewDecoderFromFile, which must stay open while it is used.
+func (d *Decoder) Clone() (*Decoder, error) {
+	ra, ok := d.source.reader.(io.ReaderAt)
+	if !ok || d.length == invalidLength {
+		return nil, errors.New("mp3: clone requires a source implementing io.ReaderAt and io.Seeker")
+	}
+	size, err := d.sourceSize()
+	if err != nil {
+		return nil, err
+	}
+
+	c := new(Decoder)
+	*c = *d
+	c.source = &source{
+		reader:     io.NewSectionReader(ra, 0, size),
+		maxTagSize: d.source.maxTagSize,
+	}
+	c.frame = nil
+	c.closer = nil
+	c.buf.Reset()
+	c.blockTail = nil
+	c.converted = nil
+	c.deadline = time.Time{}
+	c.stereo = stereoStats{}
+	if d.peaks != nil {
+		c.peaks = &peakMeter{}
+	}
+	if d.dither != nil {
+		c.dither = newDitherer(d.dither.method)
+	}
+	if d.spectrumHook != nil {
+		// The hook is bound to d; the clone reports its own frames.
+		c.spectrumHook = c.reportSpectrum
+	}
+	d.indexShared = true
+	c.indexShared = true
+
+	// Seeking past the end leaves the source where it is, which must then
+	// be at its end too.
+	if _, err := c.source.Seek(0, io.SeekEnd); err != nil {
+		return nil, err
+	}
+	if r := d.resampler; r != nil {
+		c.resampler = newResampler(int(r.in), int(r.out), r.speed)
+		if err := c.seekOutput(d.outPos() / 4); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	start, err := c.seek(d.pos, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	// The seek lands on the start of the sample holding the position, the
+	// bytes of the sample before it were already read from d.
+	for skip := make([]byte, d.pos-start); len(skip) > 0; {
+		n, err := c.read(skip)
+		if err != nil {
+			return nil, err
+		}
+		skip = skip[n:]
+	}
+	return c, nil
+}
+
+// sourceSize returns the size of the source, which must be io.Seeker,
+// leaving its position unchanged.
+func (d *Decoder) sourceSize() (int64, error) {
+	seeker := d.source.reader.(io.Seeker)
+	pos, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	size, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := seeker.Seek(pos, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
@@ -0,0 +1,156 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDecoder_Clone(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, want := decodeFresh(t, data)
+
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Seek(40000, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	c, err := d.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Length() != d.Length() {
+		t.Errorf("clone Length() = %d, want %d", c.Length(), d.Length())
+	}
+
+	// The decoders are used concurrently from the same position.
+	var got [2][]byte
+	var errs [2]error
+	var wg sync.WaitGroup
+	for i, dec := range []*Decoder{d, c} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i], errs[i] = io.ReadAll(dec)
+		}()
+	}
+	wg.Wait()
+	for i := range got {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if !bytes.Equal(got[i], want[40000:]) {
+			t.Errorf("decoder %d: PCM differs from a fresh decode", i)
+		}
+	}
+
+	// Reset does not overwrite the shared frame index.
+	mpeg2, err := os.ReadFile("example/mpeg2.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	if err := d.Reset(bytes.NewReader(mpeg2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SeekToTime(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	pcm, err := io.ReadAll(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start := 44100 * 4; !bytes.Equal(pcm, want[start:]) {
+		t.Error("clone PCM differs after Reset of the original")
+	}
+}
+
+func TestDecoder_Clone_WithinSample(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, want := decodeFresh(t, data)
+
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(d, make([]byte, 40003)); err != nil {
+		t.Fatal(err)
+	}
+	c, err := d.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want[40003:]) {
+		t.Error("clone PCM differs from a fresh decode")
+	}
+}
+
+func TestDecoder_Clone_SpectrumFunc(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromD, fromC []int64
+	d.SetSpectrumFunc(func(s Spectrum) { fromD = append(fromD, s.Sample) })
+	c, err := d.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetSpectrumFunc(func(s Spectrum) { fromC = append(fromC, s.Sample) })
+
+	// The frames decoded by the seeks are left out.
+	if err := d.SeekToSample(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SeekToSample(100000); err != nil {
+		t.Fatal(err)
+	}
+	fromD, fromC = nil, nil
+	for range 2 {
+		if _, _, err := d.DecodeFrame(); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := c.DecodeFrame(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(fromD) == 0 || slices.Max(fromD) >= 3*1152 {
+		t.Errorf("original saw the spectra of samples %v, want those of its frames", fromD)
+	}
+	if len(fromC) == 0 || slices.Min(fromC) < 100000 {
+		t.Errorf("clone saw the spectra of samples %v, want those of its frames", fromC)
+	}
+}
+
+func TestDecoder_Clone_Unsupported(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(&nonSeekableReader{r: bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Clone(); err == nil {
+		t.Error("Clone() of a non-seekable decoder succeeded")
+	}
+}
@@ -0,0 +1,275 @@
+// Command mp3probe prints a description of MP3 files: their format,
+// duration and bitrate, their Xing and LAME headers, a summary of their
+// tags, and the problems found by mp3.ValidateStream.
+//
+// Usage:
+//
+//	mp3probe [-q] file...
+//
+// With -q, only the files with problems and their problems are printed. The
+// exit status is 1 if a file could not be read and 2 if a file has
+// problems.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/llehouerou/go-mp3"
+	"github.com/llehouerou/go-mp3/frameheader"
+	"github.com/llehouerou/go-mp3/id3v2"
+	"github.com/llehouerou/go-mp3/lameinfo"
+)
+
+// Exit statuses.
+const (
+	exitOK      = 0
+	exitFailed  = 1
+	exitInvalid = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command with the given arguments and returns its exit
+// status.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("mp3probe", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	quiet := flags.Bool("q", false, "only print the files with problems")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: mp3probe [-q] file...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitFailed
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitFailed
+	}
+
+	status := exitOK
+	for i, name := range flags.Args() {
+		p, err := probeFile(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "mp3probe: %s: %v\n", name, err)
+			status = exitFailed
+			continue
+		}
+		if !p.report.OK() && status == exitOK {
+			status = exitInvalid
+		}
+		if *quiet {
+			if !p.report.OK() {
+				p.printIssues(stdout, name)
+			}
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		p.print(stdout, name)
+	}
+	return status
+}
+
+// A probe holds what mp3probe prints about a file.
+type probe struct {
+	header   mp3.Header
+	stats    mp3.StreamStats
+	dur      time.Duration
+	samples  int64
+	xing     *lameinfo.Info
+	tag      *id3v2.Tag
+	chapters int
+	report   *mp3.ValidationReport
+}
+
+// probeFile reads the description of the file name.
+func probeFile(name string) (*probe, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	report, err := mp3.ValidateStream(f)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	d, err := mp3.NewDecoder(f)
+	if err != nil {
+		return nil, err
+	}
+	p := &probe{
+		dur:      d.Duration(),
+		samples:  d.SampleCount(),
+		xing:     report.Xing,
+		tag:      d.Metadata(),
+		chapters: len(d.Chapters()),
+		report:   report,
+	}
+	first, ok := d.FrameInfo(0)
+	if !ok {
+		return nil, mp3.ErrNoAudioFrames
+	}
+	p.header = first.Header
+	if p.stats, err = d.StreamStats(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// print writes the description of the file name to w.
+func (p *probe) print(w io.Writer, name string) {
+	h := p.header
+	field := func(label, format string, args ...any) {
+		fmt.Fprintf(w, "%-12s %s\n", label+":", fmt.Sprintf(format, args...))
+	}
+	field("File", "%s", name)
+	field("Format", "MPEG-%d Layer III, %s", h.Version, h.Mode)
+	field("Sample rate", "%d Hz", h.SampleRate)
+	field("Channels", "%d", h.Channels)
+	field("Duration", "%s (%d samples)", p.dur.Round(time.Millisecond), p.samples)
+	s := p.stats
+	if s.MinBitrate == s.MaxBitrate {
+		field("Bitrate", "%s, %d kb/s", s.Mode, s.AverageBitrate/1000)
+	} else {
+		field("Bitrate", "%s, %d kb/s average (%d to %d)",
+			s.Mode, s.AverageBitrate/1000, s.MinBitrate/1000, s.MaxBitrate/1000)
+	}
+	field("Frames", "%d", s.FrameCount)
+	field("Modes", "%s", modeSummary(s))
+	if x := p.xing; x != nil {
+		field("Xing", "%s", xingSummary(x))
+		if x.HasLAMEInfo() {
+			field("LAME", "%s", lameSummary(x))
+		}
+	}
+	field("Tags", "%s", p.tagSummary())
+	if p.report.OK() {
+		field("Validation", "ok")
+		return
+	}
+	field("Validation", "%s", plural(len(p.report.Issues), "problem"))
+	for _, i := range p.report.Issues {
+		fmt.Fprintf(w, "  %s\n", i)
+	}
+}
+
+// printIssues writes the problems of the file name to w.
+func (p *probe) printIssues(w io.Writer, name string) {
+	for _, i := range p.report.Issues {
+		fmt.Fprintf(w, "%s: %s\n", name, i)
+	}
+}
+
+// modeSummary describes the channel modes of the frames of s.
+func modeSummary(s mp3.StreamStats) string {
+	var parts []string
+	for m := frameheader.ModeStereo; m <= frameheader.ModeSingleChannel; m++ {
+		n := s.ModeHistogram[m]
+		if n == 0 {
+			continue
+		}
+		part := fmt.Sprintf("%d %s", n, m)
+		if m == frameheader.ModeJointStereo {
+			part += fmt.Sprintf(" (%d M/S, %d intensity)", s.MSStereoFrames, s.IntensityStereoFrames)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// xingSummary describes the Xing header x.
+func xingSummary(x *lameinfo.Info) string {
+	parts := []string{"Info"}
+	if x.IsXing {
+		parts[0] = "Xing"
+	}
+	if x.HasFrameCount() {
+		parts = append(parts, fmt.Sprintf("%d frames", x.FrameCount))
+	}
+	if x.HasByteCount() {
+		parts = append(parts, fmt.Sprintf("%d bytes", x.ByteCount))
+	}
+	if x.HasTOC() {
+		parts = append(parts, "TOC")
+	}
+	if x.HasVBRScale() {
+		parts = append(parts, fmt.Sprintf("quality %d", x.VBRScale))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// lameSummary describes the LAME tag of x.
+func lameSummary(x *lameinfo.Info) string {
+	crc := "tag CRC ok"
+	if !x.Valid {
+		crc = "tag CRC mismatch"
+	}
+	return fmt.Sprintf("%s, %s, delay %d, padding %d, %s",
+		strings.TrimSpace(x.LAMEVersion), vbrMethod(x.VBRMethod), x.EncoderDelay, x.EncoderPadding, crc)
+}
+
+// vbrMethod returns the name of a VBR method of the LAME tag.
+func vbrMethod(m uint8) string {
+	switch m {
+	case lameinfo.VBRMethodCBR:
+		return "CBR"
+	case lameinfo.VBRMethodABR:
+		return "ABR"
+	case lameinfo.VBRMethodVBROld, lameinfo.VBRMethodVBRMTRH, lameinfo.VBRMethodVBRMT:
+		return "VBR"
+	case lameinfo.VBRMethodCBRTwoPass:
+		return "CBR two-pass"
+	case lameinfo.VBRMethodABRTwoPass:
+		return "ABR two-pass"
+	}
+	return "unknown method"
+}
+
+// tagSummary describes the tags of the file.
+func (p *probe) tagSummary() string {
+	var parts []string
+	if t := p.tag; t != nil {
+		s := fmt.Sprintf("ID3v2.%d (%s)", t.Version, plural(len(t.Frames), "frame"))
+		var fields []string
+		for _, v := range []string{t.Artist(), t.Title(), t.Album()} {
+			if v != "" {
+				fields = append(fields, v)
+			}
+		}
+		if len(fields) > 0 {
+			s += " " + strings.Join(fields, " / ")
+		}
+		parts = append(parts, s)
+	}
+	if p.chapters > 0 {
+		parts = append(parts, plural(p.chapters, "chapter"))
+	}
+	if n := p.report.TrailingTagBytes; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d bytes of trailing tags", n))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// plural returns n followed by noun, in the plural if n is not 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"../../example/classic_lame.mp3"}, &stdout, &stderr); status != exitOK {
+		t.Fatalf("run() = %d, want %d; stderr: %s", status, exitOK, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"Format:      MPEG-1 Layer III, joint stereo\n",
+		"Sample rate: 44100 Hz\n",
+		"Bitrate:     VBR, ",
+		"Modes:       384 joint stereo (12 M/S, 0 intensity)\n",
+		"Xing:        Xing, 384 frames, ",
+		"LAME:        LAME3.100, VBR, delay 576, padding 792, tag CRC ok\n",
+		"Validation:  ok\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRun_Problems(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{Frames: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(t.TempDir(), "truncated.mp3")
+	if err := os.WriteFile(name, data[:len(data)-100], 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"-q", name}, &stdout, &stderr); status != exitInvalid {
+		t.Errorf("run() = %d, want %d", status, exitInvalid)
+	}
+	if out := stdout.String(); !strings.HasPrefix(out, name+": truncated frame at offset ") {
+		t.Errorf("output %q, want the truncated frame", out)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if status := run(nil, &stdout, &stderr); status != exitFailed {
+		t.Errorf("run() without files = %d, want %d", status, exitFailed)
+	}
+	stderr.Reset()
+	if status := run([]string{"missing.mp3"}, &stdout, &stderr); status != exitFailed {
+		t.Errorf("run() on a missing file = %d, want %d", status, exitFailed)
+	}
+	if !strings.Contains(stderr.String(), "missing.mp3") {
+		t.Errorf("stderr %q, want the name of the missing file", stderr.String())
+	}
+}
@@ -0,0 +1,230 @@
+// Command mp3towav decodes MP3 files to WAV files.
+//
+// Usage:
+//
+//	mp3towav [flags] file...
+//
+// Each file is written next to its input, with the extension replaced by
+// .wav, or to the file given by -o when there is a single input. The flags
+// select the sample format of the output, a gain, dithering and the
+// removal of the encoder delay and padding recorded in the LAME tag:
+//
+//	-format s16|u8|alaw|mulaw  sample format (default s16)
+//	-gain dB                   gain applied before quantization
+//	-dither none|tpdf|shaped   dithering of 16-bit samples
+//	-gapless                   trim the encoder delay and padding
+//	-o file                    output file, for a single input
+//
+// The exit status is 1 if a file could not be converted.
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/llehouerou/go-mp3"
+	"github.com/llehouerou/go-mp3/lameinfo"
+)
+
+// Exit statuses.
+const (
+	exitOK     = 0
+	exitFailed = 1
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// options holds the conversion settings given on the command line.
+type options struct {
+	format  mp3.OutputFormat
+	gain    float64
+	dither  mp3.Dither
+	gapless bool
+}
+
+// run runs the command with the given arguments and returns its exit
+// status.
+func run(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("mp3towav", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "s16", "sample format: s16, u8, alaw or mulaw")
+	gain := flags.Float64("gain", 0, "gain in dB")
+	dither := flags.String("dither", "none", "dithering of 16-bit samples: none, tpdf or shaped")
+	gapless := flags.Bool("gapless", false, "trim the encoder delay and padding of the LAME tag")
+	output := flags.String("o", "", "output file, for a single input")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: mp3towav [flags] file...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitFailed
+	}
+	if flags.NArg() == 0 || *output != "" && flags.NArg() > 1 {
+		flags.Usage()
+		return exitFailed
+	}
+
+	opts := options{gain: *gain, gapless: *gapless}
+	var err error
+	if opts.format, err = parseFormat(*format); err == nil {
+		opts.dither, err = parseDither(*dither)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "mp3towav: %v\n", err)
+		return exitFailed
+	}
+
+	status := exitOK
+	for _, in := range flags.Args() {
+		out := *output
+		if out == "" {
+			out = strings.TrimSuffix(in, filepath.Ext(in)) + ".wav"
+		}
+		if err := convert(in, out, opts); err != nil {
+			fmt.Fprintf(stderr, "mp3towav: %s: %v\n", in, err)
+			status = exitFailed
+		}
+	}
+	return status
+}
+
+// parseFormat returns the output format named s.
+func parseFormat(s string) (mp3.OutputFormat, error) {
+	switch s {
+	case "s16":
+		return mp3.OutputS16LE, nil
+	case "u8":
+		return mp3.OutputU8, nil
+	case "alaw":
+		return mp3.OutputALaw, nil
+	case "mulaw":
+		return mp3.OutputMuLaw, nil
+	}
+	return 0, fmt.Errorf("unknown format %q", s)
+}
+
+// parseDither returns the dithering method named s.
+func parseDither(s string) (mp3.Dither, error) {
+	switch s {
+	case "none":
+		return mp3.DitherNone, nil
+	case "tpdf":
+		return mp3.DitherTPDF, nil
+	case "shaped":
+		return mp3.DitherShaped, nil
+	}
+	return 0, fmt.Errorf("unknown dither %q", s)
+}
+
+// convert decodes the MP3 file in to the WAV file out.
+func convert(in, out string, opts options) (err error) {
+	f, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	d, err := mp3.NewDecoder(f,
+		mp3.WithOutputFormat(opts.format), mp3.WithGain(opts.gain), mp3.WithDither(opts.dither))
+	if err != nil {
+		return err
+	}
+	// Interleaved stereo samples.
+	sampleSize := int64(2 * opts.format.BytesPerSample())
+	var skip, trim int64
+	if opts.gapless {
+		if skip, trim, err = gaplessTrim(f, d); err != nil {
+			return err
+		}
+		skip *= sampleSize
+		trim *= sampleSize
+	}
+	size := max(d.Length()-skip-trim, 0)
+	if size > 0xffffffff-wavHeaderSize {
+		return errors.New("decoded audio too long for WAV")
+	}
+
+	o, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := o.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	w := bufio.NewWriter(o)
+	if _, err := w.Write(wavHeader(opts.format, d.SampleRate(), uint32(size))); err != nil {
+		return err
+	}
+	// Seeking also puts the decoder back in place after gaplessTrim.
+	if _, err := d.Seek(skip, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(w, d, size); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// gaplessTrim returns the number of samples per channel to skip at the start
+// and to trim at the end of the output of d to remove the encoder delay and
+// padding recorded in the LAME tag of the file f. Both are 0 if the file
+// has no LAME tag. The position of f is left undefined.
+func gaplessTrim(f io.ReadSeeker, d *mp3.Decoder) (skip, trim int64, err error) {
+	first, ok := d.FrameInfo(0)
+	if !ok {
+		return 0, 0, nil
+	}
+	if _, err := f.Seek(first.Offset, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+	info, err := lameinfo.ParseFromReader(f)
+	if err != nil || !info.HasLAMEInfo() {
+		return 0, 0, nil
+	}
+	// The Xing header frame is decoded as a frame of silence.
+	skip = int64(first.Header.SamplesPerFrame + info.TotalDelay())
+	return skip, int64(info.TotalPadding()), nil
+}
+
+// wavHeaderSize is the size of the WAV header written before the samples.
+const wavHeaderSize = 46
+
+// wavHeader returns the header of a WAV file holding size bytes of stereo
+// samples in format f at the given sample rate.
+func wavHeader(f mp3.OutputFormat, sampleRate int, size uint32) []byte {
+	const channels = 2
+	tag := uint16(1) // PCM
+	switch f {
+	case mp3.OutputALaw:
+		tag = 6
+	case mp3.OutputMuLaw:
+		tag = 7
+	}
+	bytesPerSample := f.BytesPerSample()
+	b := make([]byte, wavHeaderSize)
+	copy(b[0:], "RIFF")
+	binary.LittleEndian.PutUint32(b[4:], wavHeaderSize-8+size)
+	copy(b[8:], "WAVEfmt ")
+	// The format chunk has the size field of its extension, as required for
+	// formats other than PCM, but no extension.
+	binary.LittleEndian.PutUint32(b[16:], 18)
+	binary.LittleEndian.PutUint16(b[20:], tag)
+	binary.LittleEndian.PutUint16(b[22:], channels)
+	binary.LittleEndian.PutUint32(b[24:], uint32(sampleRate))                         //nolint:gosec // MP3 sample rates are small
+	binary.LittleEndian.PutUint32(b[28:], uint32(sampleRate*channels*bytesPerSample)) //nolint:gosec // MP3 sample rates are small
+	binary.LittleEndian.PutUint16(b[32:], uint16(channels*bytesPerSample))            //nolint:gosec // At most 4 bytes
+	binary.LittleEndian.PutUint16(b[34:], uint16(8*bytesPerSample))                   //nolint:gosec // At most 16 bits
+	copy(b[38:], "data")
+	binary.LittleEndian.PutUint32(b[42:], size)
+	return b
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/llehouerou/go-mp3"
+)
+
+// convertFile runs the command on the sample file name with args and
+// returns the WAV file written.
+func convertFile(t *testing.T, name string, args ...string) []byte {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "out.wav")
+	var stderr bytes.Buffer
+	args = append(args, "-o", out, filepath.Join("..", "..", "example", name))
+	if status := run(args, &stderr); status != exitOK {
+		t.Fatalf("run(%q) = %d, want %d; stderr: %s", args, status, exitOK, stderr.String())
+	}
+	wav, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wav) < wavHeaderSize || string(wav[:4]) != "RIFF" || string(wav[38:42]) != "data" {
+		t.Fatalf("output is not a WAV file")
+	}
+	if size := binary.LittleEndian.Uint32(wav[42:]); int(size) != len(wav)-wavHeaderSize {
+		t.Errorf("data size %d, want %d", size, len(wav)-wavHeaderSize)
+	}
+	return wav
+}
+
+func TestRun_Gapless(t *testing.T) {
+	f, err := os.Open("../../example/classic_lame.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	d, err := mp3.NewDecoder(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wav := convertFile(t, "classic_lame.mp3")
+	if !bytes.Equal(wav[wavHeaderSize:], pcm) {
+		t.Error("output differs from the decoded PCM")
+	}
+	// The file was encoded from 10 seconds of audio. The Xing header frame,
+	// the encoder delay and the decoder delay are skipped.
+	wav = convertFile(t, "classic_lame.mp3", "-gapless")
+	if got, want := len(wav)-wavHeaderSize, 10*44100*4; got != want {
+		t.Fatalf("gapless output has %d bytes, want %d", got, want)
+	}
+	skip := (1152 + 576 + 529) * 4
+	if !bytes.Equal(wav[wavHeaderSize:], pcm[skip:skip+10*44100*4]) {
+		t.Error("gapless output differs from the decoded PCM")
+	}
+}
+
+func TestRun_Format(t *testing.T) {
+	wav := convertFile(t, "mpeg2.mp3", "-format", "alaw", "-gain", "-6")
+	if tag := binary.LittleEndian.Uint16(wav[20:]); tag != 6 {
+		t.Errorf("format tag %d, want 6 (A-law)", tag)
+	}
+	if rate := binary.LittleEndian.Uint32(wav[24:]); rate != 22050 {
+		t.Errorf("sample rate %d, want 22050", rate)
+	}
+	if bits := binary.LittleEndian.Uint16(wav[34:]); bits != 8 {
+		t.Errorf("bits per sample %d, want 8", bits)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"-o", "out.wav", "a.mp3", "b.mp3"},
+		{"-format", "s24", "a.mp3"},
+		{"-dither", "loud", "a.mp3"},
+		{filepath.Join(t.TempDir(), "missing.mp3")},
+	} {
+		var stderr bytes.Buffer
+		if status := run(args, &stderr); status != exitFailed {
+			t.Errorf("run(%q) = %d, want %d", args, status, exitFailed)
+		}
+	}
+}
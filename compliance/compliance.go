@@ -0,0 +1,178 @@
+// Package compliance compares the output of this library with a reference
+// decoder, following the accuracy criteria of ISO/IEC 11172-4.
+//
+// Compare measures the difference between two PCM streams. Harness runs the
+// comparison over a directory of MP3 files, decoding each with this library
+// and with a user-supplied reference command such as mpg123 or ffmpeg, and
+// produces a machine-readable report.
+package compliance
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// ISO/IEC 11172-4 compliance thresholds, in 16-bit sample units.
+const (
+	// FullComplianceRMS is 2^-15 / sqrt(12) of full scale.
+	FullComplianceRMS = 0.289
+
+	// LimitedComplianceRMS is 2^-11 / sqrt(12) of full scale.
+	LimitedComplianceRMS = 4.62
+
+	// FullComplianceMaxDiff is 2^-14 of full scale.
+	FullComplianceMaxDiff = 2
+
+	// LimitedComplianceMaxDiff is 2^-10 of full scale.
+	LimitedComplianceMaxDiff = 32
+)
+
+// DefaultMaxOffset is the default range, in stereo samples, searched to
+// align the two streams. It covers the usual encoder delay, which some
+// decoders remove and others do not.
+const DefaultMaxOffset = 3000
+
+// Result holds the comparison of a decoded stream with its reference.
+type Result struct {
+	// File is the path of the compared file, if any.
+	File string `json:"file,omitempty"`
+
+	// Error describes why the file could not be compared. The other fields
+	// are zero when it is set.
+	Error string `json:"error,omitempty"`
+
+	// Offset is the alignment of the tested stream relative to the
+	// reference, in stereo samples. It is positive when the tested stream
+	// starts later.
+	Offset int `json:"offset"`
+
+	// ReferenceBytes and TestBytes are the lengths of both streams.
+	ReferenceBytes int `json:"referenceBytes"`
+	TestBytes      int `json:"testBytes"`
+
+	// TotalSamples is the number of compared samples over both channels.
+	TotalSamples int64 `json:"totalSamples"`
+
+	// RMS is the root mean square of the sample differences.
+	RMS float64 `json:"rms"`
+
+	// MaxDiff is the largest absolute sample difference, found at sample
+	// MaxDiffAt.
+	MaxDiff   int   `json:"maxDiff"`
+	MaxDiffAt int64 `json:"maxDiffAt"`
+
+	// MeanDiff is the mean of the sample differences.
+	MeanDiff float64 `json:"meanDiff"`
+
+	// FullCompliance and LimitedCompliance report which compliance level
+	// the differences meet.
+	FullCompliance    bool `json:"fullCompliance"`
+	LimitedCompliance bool `json:"limitedCompliance"`
+}
+
+// Compare compares test with reference, both 16-bit little endian stereo
+// PCM. When the lengths differ, the streams are first aligned by searching
+// offsets up to maxOffset stereo samples.
+func Compare(reference, test []byte, maxOffset int) Result {
+	offset := 0
+	if len(reference) != len(test) && maxOffset > 0 {
+		offset = findBestAlignment(reference, test, maxOffset)
+	}
+	r := compareWithOffset(reference, test, offset)
+	r.Offset = offset
+	r.ReferenceBytes = len(reference)
+	r.TestBytes = len(test)
+	return r
+}
+
+func readSample(data []byte, byteOffset int) int32 {
+	return int32(int16(binary.LittleEndian.Uint16(data[byteOffset:]))) //nolint:gosec // intentional signed conversion
+}
+
+// overlap returns where the streams start and how many stereo samples they
+// share at the given offset.
+func overlap(reference, test []byte, offset int) (refStart, testStart, n int) {
+	refSamples := len(reference) / 4
+	testSamples := len(test) / 4
+	if offset >= 0 {
+		return 0, offset, min(refSamples, testSamples-offset)
+	}
+	return -offset, 0, min(refSamples+offset, testSamples)
+}
+
+// rmsAtOffset computes the RMS difference at offset, comparing every step-th
+// stereo sample.
+func rmsAtOffset(reference, test []byte, offset, step int) float64 {
+	refStart, testStart, n := overlap(reference, test, offset)
+	if n <= 0 {
+		return math.MaxFloat64
+	}
+	var sum float64
+	compared := 0
+	for i := 0; i < n; i += step {
+		refIdx := (refStart + i) * 4
+		testIdx := (testStart + i) * 4
+		for ch := 0; ch < 4; ch += 2 {
+			d := float64(readSample(test, testIdx+ch) - readSample(reference, refIdx+ch))
+			sum += d * d
+		}
+		compared++
+	}
+	return math.Sqrt(sum / float64(compared*2))
+}
+
+// findBestAlignment returns the offset that minimizes the RMS difference,
+// with a coarse search followed by a fine one.
+func findBestAlignment(reference, test []byte, maxOffset int) int {
+	const (
+		coarseStep       = 50
+		coarseSampleStep = 100
+		fineSampleStep   = 10
+	)
+	bestRMS := math.MaxFloat64
+	best := 0
+	for offset := -maxOffset; offset <= maxOffset; offset += coarseStep {
+		if rms := rmsAtOffset(reference, test, offset, coarseSampleStep); rms < bestRMS {
+			bestRMS = rms
+			best = offset
+		}
+	}
+	start := max(-maxOffset, best-coarseStep)
+	end := min(maxOffset, best+coarseStep)
+	for offset := start; offset <= end; offset++ {
+		if rms := rmsAtOffset(reference, test, offset, fineSampleStep); rms < bestRMS {
+			bestRMS = rms
+			best = offset
+		}
+	}
+	return best
+}
+
+func compareWithOffset(reference, test []byte, offset int) Result {
+	var r Result
+	refStart, testStart, n := overlap(reference, test, offset)
+	if n <= 0 {
+		return r
+	}
+	r.TotalSamples = int64(n * 2)
+
+	var sumSquared, sum float64
+	for i := range n {
+		refIdx := (refStart + i) * 4
+		testIdx := (testStart + i) * 4
+		for ch := range 2 {
+			d := readSample(test, testIdx+2*ch) - readSample(reference, refIdx+2*ch)
+			if abs := int(max(d, -d)); abs > r.MaxDiff {
+				r.MaxDiff = abs
+				r.MaxDiffAt = int64(i*2 + ch)
+			}
+			sumSquared += float64(d) * float64(d)
+			sum += float64(d)
+		}
+	}
+	r.RMS = math.Sqrt(sumSquared / float64(r.TotalSamples))
+	r.MeanDiff = sum / float64(r.TotalSamples)
+	r.FullCompliance = r.RMS < FullComplianceRMS && r.MaxDiff <= FullComplianceMaxDiff
+	r.LimitedCompliance = r.RMS < LimitedComplianceRMS && r.MaxDiff <= LimitedComplianceMaxDiff
+	return r
+}
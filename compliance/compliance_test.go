@@ -0,0 +1,136 @@
+package compliance
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/llehouerou/go-mp3"
+)
+
+// TestMain lets the test binary act as a reference decoder: with
+// FAKE_REFERENCE set, it decodes the file given as last argument with this
+// library and writes the PCM, delayed by 100 samples, to stdout.
+func TestMain(m *testing.M) {
+	if os.Getenv("FAKE_REFERENCE") == "1" {
+		f, err := os.Open(os.Args[len(os.Args)-1])
+		if err != nil {
+			os.Exit(2)
+		}
+		d, err := mp3.NewDecoder(f)
+		if err != nil {
+			os.Exit(3)
+		}
+		os.Stdout.Write(make([]byte, 100*4))
+		if _, err := io.Copy(os.Stdout, d); err != nil {
+			os.Exit(4)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// chirp returns n stereo samples of a slow chirp, which unlike a plain sine
+// matches itself at a single alignment.
+func chirp(n int, amplitude float64) []byte {
+	b := make([]byte, 0, n*4)
+	for i := range n {
+		x := float64(i)
+		v := int16(amplitude * math.Sin(0.003*x+2e-7*x*x))
+		b = binary.LittleEndian.AppendUint16(b, uint16(v))
+		b = binary.LittleEndian.AppendUint16(b, uint16(v))
+	}
+	return b
+}
+
+func TestCompare_Identical(t *testing.T) {
+	pcm := chirp(10000, 10000)
+	r := Compare(pcm, pcm, DefaultMaxOffset)
+	if !r.FullCompliance || r.RMS != 0 || r.MaxDiff != 0 || r.Offset != 0 {
+		t.Errorf("identical streams: %+v", r)
+	}
+}
+
+func TestCompare_Alignment(t *testing.T) {
+	ref := chirp(10000, 10000)
+	test := append(make([]byte, 529*4), ref...)
+	r := Compare(ref, test, DefaultMaxOffset)
+	if r.Offset != 529 {
+		t.Errorf("Offset = %d, want 529", r.Offset)
+	}
+	if !r.FullCompliance {
+		t.Errorf("aligned streams should be fully compliant: %+v", r)
+	}
+}
+
+func TestCompare_Levels(t *testing.T) {
+	ref := chirp(10000, 10000)
+	limited := bytes.Clone(ref)
+	broken := bytes.Clone(ref)
+	for i := 0; i < len(ref); i += 2 {
+		v := int16(binary.LittleEndian.Uint16(ref[i:]))
+		binary.LittleEndian.PutUint16(limited[i:], uint16(v+int16(i/2%5)))
+		binary.LittleEndian.PutUint16(broken[i:], uint16(v/2))
+	}
+	if r := Compare(ref, limited, 0); r.FullCompliance || !r.LimitedCompliance {
+		t.Errorf("small differences: %+v, want limited compliance only", r)
+	}
+	if r := Compare(ref, broken, 0); r.LimitedCompliance {
+		t.Errorf("large differences: %+v, want no compliance", r)
+	}
+}
+
+func TestHarness_Run(t *testing.T) {
+	dir := t.TempDir()
+	audio, err := os.ReadFile("../example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.mp3"), audio[:len(audio)/8], 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.MP3"), []byte("not an mp3"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("FAKE_REFERENCE", "1")
+	h := &Harness{Command: []string{os.Args[0], FilePlaceholder}}
+	report, err := h.Run(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(report.Results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(report.Results), report.Results)
+	}
+	a, b := report.Results[0], report.Results[1]
+	if !a.FullCompliance || a.Offset != -100 {
+		t.Errorf("a.mp3: %+v, want full compliance at offset -100", a)
+	}
+	if b.Error == "" {
+		t.Errorf("b.MP3: expected an error, got %+v", b)
+	}
+	if report.Full != 1 || report.Failed != 1 {
+		t.Errorf("Full = %d, Failed = %d, want 1 and 1", report.Full, report.Failed)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if decoded.Results[0].File != a.File {
+		t.Errorf("decoded file = %q, want %q", decoded.Results[0].File, a.File)
+	}
+}
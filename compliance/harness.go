@@ -0,0 +1,170 @@
+package compliance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/llehouerou/go-mp3"
+)
+
+// FilePlaceholder is replaced by the path of the decoded file in the
+// arguments of Harness.Command.
+const FilePlaceholder = "{}"
+
+// A Harness decodes a corpus of MP3 files with this library and with a
+// reference decoder, and compares the outputs.
+type Harness struct {
+	// Command is the reference decoder command line. Arguments equal to
+	// FilePlaceholder are replaced by the path of the file. The command
+	// must write 16-bit little endian stereo PCM to its standard output,
+	// for example:
+	//
+	//	[]string{"mpg123", "-q", "-e", "s16", "--stereo", "-s", "{}"}
+	Command []string
+
+	// Extensions lists the file name extensions included in the corpus.
+	// Defaults to ".mp3". The comparison is case insensitive.
+	Extensions []string
+
+	// MaxOffset is the alignment search range in stereo samples. Defaults
+	// to DefaultMaxOffset.
+	MaxOffset int
+
+	// Options are passed to mp3.NewDecoder.
+	Options []mp3.Option
+}
+
+// Report is the outcome of a Harness run.
+type Report struct {
+	// Results holds a result per file, sorted by path.
+	Results []Result `json:"results"`
+
+	// Full, Limited and Failed count the files meeting full compliance,
+	// only limited compliance, and neither or failing to decode.
+	Full    int `json:"full"`
+	Limited int `json:"limited"`
+	Failed  int `json:"failed"`
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// Run compares every file of the corpus found under dir, recursively. A
+// file that either decoder fails on is reported with an error in its
+// result; Run itself only fails if dir cannot be walked or ctx is done.
+func (h *Harness) Run(ctx context.Context, dir string) (*Report, error) {
+	if len(h.Command) == 0 {
+		return nil, errors.New("compliance: no reference command")
+	}
+	files, err := h.corpus(dir)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	for _, path := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		r := h.compareFile(ctx, path)
+		switch {
+		case r.FullCompliance:
+			report.Full++
+		case r.LimitedCompliance:
+			report.Limited++
+		default:
+			report.Failed++
+		}
+		report.Results = append(report.Results, r)
+	}
+	return report, nil
+}
+
+func (h *Harness) corpus(dir string) ([]string, error) {
+	exts := h.Extensions
+	if len(exts) == 0 {
+		exts = []string{".mp3"}
+	}
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.IsDir() {
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if slices.ContainsFunc(exts, func(e string) bool { return strings.EqualFold(e, ext) }) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func (h *Harness) compareFile(ctx context.Context, path string) Result {
+	reference, err := h.decodeReference(ctx, path)
+	if err != nil {
+		return Result{File: path, Error: err.Error()}
+	}
+	test, err := h.decode(path)
+	if err != nil {
+		return Result{File: path, Error: err.Error()}
+	}
+	maxOffset := h.MaxOffset
+	if maxOffset == 0 {
+		maxOffset = DefaultMaxOffset
+	}
+	r := Compare(reference, test, maxOffset)
+	r.File = path
+	return r
+}
+
+func (h *Harness) decodeReference(ctx context.Context, path string) ([]byte, error) {
+	args := make([]string, len(h.Command)-1)
+	for i, a := range h.Command[1:] {
+		if a == FilePlaceholder {
+			a = path
+		}
+		args[i] = a
+	}
+	cmd := exec.CommandContext(ctx, h.Command[0], args...) //nolint:gosec // the command is supplied by the caller
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("compliance: reference decoder failed: %w, stderr: %s", err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+func (h *Harness) decode(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	d, err := mp3.NewDecoder(f, h.Options...)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(d)
+}
@@ -0,0 +1,188 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"math"
+
+	"github.com/llehouerou/go-mp3/frameheader"
+	internalheader "github.com/llehouerou/go-mp3/internal/frameheader"
+	"github.com/llehouerou/go-mp3/lameinfo"
+)
+
+// ErrFormatMismatch is returned by Concat when the inputs do not have the
+// same sample rate.
+var ErrFormatMismatch = errors.New("mp3: inputs have different sample rates")
+
+// Concat writes the audio frames of the MP3 streams of inputs to w, one
+// after the other, without decoding them, for example to build a
+// continuous mix. The tags of the inputs, their Xing header frames and the
+// bytes that do not belong to a frame are left out, so that the output is
+// a plain stream of frames that players see as a single file.
+//
+// The inputs must have the same sample rate. The encoder delay and padding
+// of each input remain in the audio.
+func Concat(w io.Writer, inputs ...io.Reader) error {
+	c := &concatenator{w: w}
+	return c.copy(inputs)
+}
+
+// ConcatWithXing is like Concat but starts the output with a Xing header
+// frame holding the frame and byte counts and the seek table of the result,
+// which players use to show the duration of VBR streams and seek in them. The header is filled in once all the
+// inputs have been copied, so w must be an io.WriteSeeker; it is left
+// positioned at the end of the output.
+func ConcatWithXing(w io.WriteSeeker, inputs ...io.Reader) error {
+	start, err := w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	c := &concatenator{w: w, xing: true}
+	if err := c.copy(inputs); err != nil {
+		return err
+	}
+	if c.frames == 0 {
+		return nil
+	}
+	if _, err := w.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	b, err := c.xingFrame()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(b); err != nil {
+		return err
+	}
+	_, err = w.Seek(0, io.SeekEnd)
+	return err
+}
+
+// A concatenator writes the frames of streams one after the other.
+type concatenator struct {
+	w    io.Writer
+	xing bool
+
+	// first is the header of the first frame written, frames and bytes
+	// count the frames written, and vbr is set if their bitrates differ.
+	// With a Xing header frame, offsets holds the positions of the frames
+	// in the output after it.
+	first   internalheader.FrameHeader
+	frames  int
+	bytes   int64
+	vbr     bool
+	offsets []int64
+}
+
+// copy writes the frames of inputs.
+func (c *concatenator) copy(inputs []io.Reader) error {
+	for _, r := range inputs {
+		if err := c.copyStream(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyStream writes the frames of the stream of r.
+func (c *concatenator) copyStream(r io.Reader) error {
+	s := &source{reader: r}
+	if err := s.skipTags(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	}
+	f := NewFramer()
+	_, _ = f.Write(s.buf)
+	buf := make([]byte, 32*1024)
+	first := true
+	for {
+		n, err := s.reader.Read(buf)
+		_, _ = f.Write(buf[:n])
+		if errors.Is(err, io.EOF) {
+			_ = f.Close()
+		}
+		for {
+			raw, ok := f.Next()
+			if !ok {
+				break
+			}
+			if first {
+				first = false
+				if _, err := lameinfo.Parse(raw); err == nil {
+					continue
+				}
+			}
+			if err := c.writeFrame(raw); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeFrame writes the frame raw.
+func (c *concatenator) writeFrame(raw []byte) error {
+	h := headerFromBytes(raw)
+	if c.frames == 0 {
+		c.first = h
+		if c.xing {
+			// Reserve the room of the Xing header frame.
+			b, err := c.xingFrame()
+			if err != nil {
+				return err
+			}
+			if _, err := c.w.Write(make([]byte, len(b))); err != nil {
+				return err
+			}
+		}
+	} else {
+		if h.SamplingFrequency() != c.first.SamplingFrequency() || h.ID() != c.first.ID() {
+			return ErrFormatMismatch
+		}
+		c.vbr = c.vbr || h.BitrateIndex() != c.first.BitrateIndex()
+	}
+	if _, err := c.w.Write(raw); err != nil {
+		return err
+	}
+	if c.xing {
+		c.offsets = append(c.offsets, c.bytes)
+	}
+	c.frames++
+	c.bytes += int64(len(raw))
+	return nil
+}
+
+// xingFrame returns the Xing header frame of the frames written so far.
+func (c *concatenator) xingFrame() ([]byte, error) {
+	info := &lameinfo.Info{
+		IsXing:     c.vbr,
+		Flags:      lameinfo.FlagFrameCount | lameinfo.FlagByteCount | lameinfo.FlagTOC,
+		FrameCount: uint32(c.frames), //nolint:gosec // Xing frame counts are 32-bit
+	}
+	h := frameheader.Header(c.first)
+	// The size of the frame does not depend on the values of the fields.
+	b, err := lameinfo.Build(h, info)
+	if err != nil || c.frames == 0 {
+		return b, err
+	}
+	size := int64(len(b))
+	if size+c.bytes <= math.MaxUint32 {
+		info.ByteCount = uint32(size + c.bytes) //nolint:gosec // checked above
+	} else {
+		// The field cannot hold the size; readers fall back to the file size.
+		info.Flags &^= lameinfo.FlagByteCount
+	}
+	offsets := make([]int64, len(c.offsets))
+	for i, o := range c.offsets {
+		offsets[i] = size + o
+	}
+	info.TOC = lameinfo.BuildTOC(offsets, size+c.bytes)
+	return lameinfo.Build(h, info)
+}
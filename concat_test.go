@@ -0,0 +1,111 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/lameinfo"
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+func TestConcat(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, pcm := decodeFresh(t, data)
+	var out bytes.Buffer
+	if err := Concat(&out, bytes.NewReader(data), bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lameinfo.Parse(out.Bytes()); !errors.Is(err, lameinfo.ErrNoXingHeader) {
+		t.Errorf("output starts with a Xing header: %v", err)
+	}
+	cd, cpcm := decodeFresh(t, out.Bytes())
+	// The Xing header frames, decoded as a frame of silence, are left out.
+	if want := 2 * (d.FrameCount() - 1); cd.FrameCount() != want {
+		t.Fatalf("output has %d frames, want %d", cd.FrameCount(), want)
+	}
+	audio := pcm[1152*4:]
+	if !bytes.Equal(cpcm[:len(audio)], audio) {
+		t.Error("first input decodes to different audio")
+	}
+	if len(cpcm) != 2*len(audio) {
+		t.Errorf("output decodes to %d bytes, want %d", len(cpcm), 2*len(audio))
+	}
+}
+
+func TestConcat_Tags(t *testing.T) {
+	tagged, err := testsupport.Generate(testsupport.Options{
+		Frames: 5, Xing: true, ID3v1: true, Tags: map[string]string{"TIT2": "Title"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := testsupport.Generate(testsupport.Options{Frames: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := Concat(&out, bytes.NewReader(tagged), bytes.NewReader(plain), bytes.NewReader(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if want := slices.Concat(plain, plain); !bytes.Equal(out.Bytes(), want) {
+		t.Errorf("output of %d bytes, want the %d bytes of the frames", out.Len(), len(want))
+	}
+
+	other, err := testsupport.Generate(testsupport.Options{Frames: 5, SampleRate: 48000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Concat(io.Discard, bytes.NewReader(plain), bytes.NewReader(other)); !errors.Is(err, ErrFormatMismatch) {
+		t.Errorf("Concat of different sample rates: %v, want ErrFormatMismatch", err)
+	}
+}
+
+func TestConcatWithXing(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	low, err := testsupport.Generate(testsupport.Options{Frames: 20, Bitrate: 32})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(t.TempDir(), "mix.mp3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := ConcatWithXing(f, bytes.NewReader(data), bytes.NewReader(low)); err != nil {
+		t.Fatal(err)
+	}
+	out, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := lameinfo.Parse(out)
+	if err != nil {
+		t.Fatalf("output has no Xing header: %v", err)
+	}
+	d, _ := decodeFresh(t, data)
+	if want := d.FrameCount() - 1 + 20; !info.IsXing || int(info.FrameCount) != want || int(info.ByteCount) != len(out) {
+		t.Errorf("Xing header %+v, want VBR with %d frames and %d bytes", info, want, len(out))
+	}
+	if !info.HasTOC() || !slices.IsSorted(info.TOC[:]) || info.TOC[99] == 0 {
+		t.Errorf("TOC %v, want increasing entries", info.TOC)
+	}
+	nd, err := NewDecoder(nonSeekable{bytes.NewReader(out)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, _ := decodeFresh(t, out)
+	if nd.Duration() != md.Duration() {
+		t.Errorf("duration from the Xing header %v, want %v", nd.Duration(), md.Duration())
+	}
+}
@@ -0,0 +1,153 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/llehouerou/go-mp3/internal/consts"
+)
+
+// A RangeCopy describes the frames written by CopyRange.
+type RangeCopy struct {
+	// Start and End delimit the audio of the copied frames in the stream,
+	// not counting the reservoir frames. They are the frame boundaries
+	// around the requested range.
+	Start time.Duration
+	End   time.Duration
+
+	// Frames is the number of frames written, including ReservoirFrames
+	// frames written before the range because the first frame of the range
+	// takes part of its data from their bit reservoir.
+	Frames          int
+	ReservoirFrames int
+
+	// Bytes is the number of bytes written.
+	Bytes int64
+}
+
+// CopyRange writes the raw MP3 frames holding the audio from start to end
+// to w, without decoding and re-encoding them. Concatenating such copies
+// cuts parts out of a stream losslessly, such as the ads of a podcast.
+//
+// MP3 frames may take part of their data from the frames before them, so
+// CopyRange also writes the frames the first frame of the range depends on.
+// They decode to up to a few frames of audio before the range; the count is
+// reported in the result. The Xing header frame of the stream, whose counts
+// would not match the copy, is not copied. The position of the decoder is
+// left unchanged.
+//
+// CopyRange returns an error if the source is not io.Seeker or the range is
+// empty.
+func (d *Decoder) CopyRange(w io.Writer, start, end time.Duration) (RangeCopy, error) {
+	if d.length == invalidLength {
+		return RangeCopy{}, errors.New("mp3: CopyRange requires a seekable source")
+	}
+	spos := max(d.posAt(max(start, 0))-d.priming, 0)
+	epos := d.posAt(max(end, 0)) - d.priming
+	if epos <= spos {
+		return RangeCopy{}, errors.New("mp3: empty range")
+	}
+	pos := d.source.pos
+	defer func() {
+		_, _ = d.source.Seek(pos, io.SeekStart)
+	}()
+	lowest := d.firstAudioFrame()
+	first, last := max(d.frameAt(spos), lowest), d.frameAt(epos-1)+1
+	if first >= last {
+		return RangeCopy{}, errors.New("mp3: empty range")
+	}
+	return d.copyFrames(w, first, last, lowest)
+}
+
+// firstAudioFrame returns the index of the first frame holding audio: 1 if
+// frame 0 is a Xing header frame and 0 otherwise.
+func (d *Decoder) firstAudioFrame() int64 {
+	if d.xing != nil {
+		return 1
+	}
+	return 0
+}
+
+// copyFrames writes the frames from first to last, excluded, to w, with the
+// frames holding the bit reservoir of the first one, down to frame lowest.
+// The source position is left undefined.
+func (d *Decoder) copyFrames(w io.Writer, first, last, lowest int64) (RangeCopy, error) {
+	begin, err := d.mainDataBegin(first)
+	if err != nil {
+		return RangeCopy{}, err
+	}
+	from := first
+	for reservoir := 0; reservoir < begin; from-- {
+		if from == lowest {
+			d.logger.Warn("mp3: bit reservoir of the first copied frame reaches before the stream",
+				slog.Int64("frame", first))
+			break
+		}
+		reservoir += mainDataSize(d.frameHeaders[from-1])
+	}
+
+	c := RangeCopy{
+		Start:           d.timeAt(d.priming + d.frameOffsets[first]),
+		End:             d.timeAt(d.priming + d.frameOffsets[last-1] + d.pcmBytes(d.frameHeaders[last-1])),
+		ReservoirFrames: int(first - from),
+	}
+	c.Frames, c.Bytes, err = d.writeFrames(w, from, last)
+	return c, err
+}
+
+// writeFrames writes the frames from first to last, excluded, to w, and
+// returns the number of frames and bytes written. The source position is
+// left undefined.
+func (d *Decoder) writeFrames(w io.Writer, first, last int64) (int, int64, error) {
+	var (
+		buf    []byte
+		frames int
+		bytes  int64
+	)
+	for i := first; i < last; i++ {
+		size, err := d.frameHeaders[i].FrameSize()
+		if err != nil {
+			return frames, bytes, err
+		}
+		if _, err := d.source.Seek(d.frameStarts[i], io.SeekStart); err != nil {
+			return frames, bytes, err
+		}
+		if cap(buf) < size {
+			buf = make([]byte, size)
+		}
+		buf = buf[:size]
+		if _, err := d.source.ReadFull(buf); err != nil {
+			return frames, bytes, err
+		}
+		n, err := w.Write(buf)
+		bytes += int64(n)
+		if err != nil {
+			return frames, bytes, err
+		}
+		frames++
+	}
+	return frames, bytes, nil
+}
+
+// mainDataBegin reads the main_data_begin field of the side information of
+// frame f: how many bytes of its main data are in the frames before it.
+func (d *Decoder) mainDataBegin(f int64) (int, error) {
+	h := d.frameHeaders[f]
+	offset := int64(4)
+	if h.ProtectionBit() == 0 {
+		offset += 2 // CRC
+	}
+	if _, err := d.source.Seek(d.frameStarts[f]+offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	b := make([]byte, 2)
+	if _, err := d.source.ReadFull(b); err != nil {
+		return 0, err
+	}
+	if h.ID() == consts.Version1 {
+		return int(b[0])<<1 | int(b[1])>>7, nil
+	}
+	return int(b[0]), nil
+}
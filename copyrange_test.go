@@ -0,0 +1,85 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/llehouerou/go-mp3/lameinfo"
+)
+
+func TestCopyRange(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, pcm := decodeFresh(t, data)
+	const frameBytes = 1152 * 4
+
+	var out bytes.Buffer
+	c, err := d.CopyRange(&out, 3*time.Second, 6*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Start > 3*time.Second || c.Start < 3*time.Second-27*time.Millisecond ||
+		c.End < 6*time.Second || c.End > 6*time.Second+27*time.Millisecond {
+		t.Errorf("copied %v to %v, want the frames around 3s to 6s", c.Start, c.End)
+	}
+	if c.ReservoirFrames < 1 || c.Bytes != int64(out.Len()) {
+		t.Errorf("copy %+v of %d bytes, want reservoir frames", c, out.Len())
+	}
+	first := 0
+	for info, _ := d.FrameInfo(first); info.Time < c.Start; info, _ = d.FrameInfo(first) {
+		first++
+	}
+
+	cd, cpcm := decodeFresh(t, out.Bytes())
+	if cd.FrameCount() != c.Frames {
+		t.Fatalf("copy has %d frames, want %d", cd.FrameCount(), c.Frames)
+	}
+	// Past the first frame of the range, whose first granule depends on the
+	// filterbank state, the copy decodes to the same audio.
+	want := pcm[(first+1)*frameBytes : (first+c.Frames-c.ReservoirFrames)*frameBytes]
+	if got := cpcm[(c.ReservoirFrames+1)*frameBytes:]; !bytes.Equal(got, want) {
+		t.Error("copied frames decode to different audio")
+	}
+
+	// The Xing header frame is not copied.
+	out.Reset()
+	c, err = d.CopyRange(&out, 0, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Start != time.Duration(1152)*time.Second/44100 || c.ReservoirFrames != 0 {
+		t.Errorf("copy %+v, want the range from frame 1 without reservoir frames", c)
+	}
+	if _, err := lameinfo.ParseFromReader(bytes.NewReader(out.Bytes())); !errors.Is(err, lameinfo.ErrNoXingHeader) {
+		t.Errorf("copy starts with a Xing header: %v", err)
+	}
+
+	// The decoder position is not changed.
+	if _, err := d.Seek(4096, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.CopyRange(io.Discard, 3*time.Second, 6*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 2*frameBytes)
+	if _, err := io.ReadFull(d, buf); err != nil || !bytes.Equal(buf, pcm[4096:4096+len(buf)]) {
+		t.Errorf("Read after CopyRange returned other PCM: %v", err)
+	}
+
+	if _, err := d.CopyRange(&out, 2*time.Second, time.Second); err == nil {
+		t.Error("CopyRange of an empty range succeeded")
+	}
+	nd, err := NewDecoder(nonSeekable{bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := nd.CopyRange(&out, 0, time.Second); err == nil {
+		t.Error("CopyRange on a non-seekable source succeeded")
+	}
+}
@@ -0,0 +1,117 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// maxFrameBytes is the size of the largest MPEG audio frame (Layer III at
+// 320 kbps and 32 kHz, padded) plus a frame header, enough for the decoder
+// to find and decode the next frame.
+const maxFrameBytes = 1441 + 4
+
+// SetReadDeadline sets the deadline for future Read calls. When the source
+// has not delivered the data of the next frame by then, Read returns
+// os.ErrDeadlineExceeded instead of blocking. The decoder
+// state is left intact, so an audio callback can play silence and call
+// Read again later. A zero t disables the deadline.
+//
+// Once a deadline has been set, the source is read from a background
+// goroutine. Seek still blocks until a source read in progress completes.
+//
+// SetReadDeadline always returns nil.
+func (d *Decoder) SetReadDeadline(t time.Time) error {
+	if d.async == nil && !t.IsZero() {
+		d.async = &asyncReader{reader: d.source.reader}
+		d.source.reader = d.async
+	}
+	d.deadline = t
+	return nil
+}
+
+// awaitFrameData waits until the source holds the data of a whole frame, or
+// the read deadline passes.
+func (d *Decoder) awaitFrameData() error {
+	if d.async == nil || d.deadline.IsZero() {
+		return nil
+	}
+	return d.async.fill(maxFrameBytes-len(d.source.buf), d.deadline)
+}
+
+type readResult struct {
+	data []byte
+	err  error
+}
+
+// asyncReader reads its underlying reader from a background goroutine so
+// that waiting for data can be bounded by a deadline.
+type asyncReader struct {
+	reader  io.Reader
+	pending chan readResult
+	data    []byte
+	err     error
+}
+
+func (r *asyncReader) start() {
+	if r.pending != nil {
+		return
+	}
+	r.pending = make(chan readResult, 1)
+	go func(reader io.Reader, pending chan<- readResult) {
+		buf := make([]byte, 4096)
+		n, err := reader.Read(buf)
+		pending <- readResult{data: buf[:n], err: err}
+	}(r.reader, r.pending)
+}
+
+func (r *asyncReader) receive(res readResult) {
+	r.pending = nil
+	r.data = append(r.data, res.data...)
+	r.err = res.err
+}
+
+// fill waits until at least n bytes are buffered, the underlying reader
+// fails or the deadline passes.
+func (r *asyncReader) fill(n int, deadline time.Time) error {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	for len(r.data) < n && r.err == nil {
+		r.start()
+		select {
+		case res := <-r.pending:
+			r.receive(res)
+		case <-timer.C:
+			return os.ErrDeadlineExceeded
+		}
+	}
+	return nil
+}
+
+func (r *asyncReader) Read(buf []byte) (int, error) {
+	for len(r.data) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.start()
+		r.receive(<-r.pending)
+	}
+	n := copy(buf, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func (r *asyncReader) Seek(offset int64, whence int) (int64, error) {
+	seeker, ok := r.reader.(io.Seeker)
+	if !ok {
+		return 0, errors.New("mp3: source must be io.Seeker")
+	}
+	if r.pending != nil {
+		<-r.pending
+		r.pending = nil
+	}
+	r.data = nil
+	r.err = nil
+	return seeker.Seek(offset, whence)
+}
@@ -0,0 +1,86 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+// gatedReader serves the first open bytes of data right away and blocks on
+// the rest until release is closed.
+type gatedReader struct {
+	data    []byte
+	open    int
+	release chan struct{}
+}
+
+func (r *gatedReader) Read(buf []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	if r.open == 0 {
+		<-r.release
+		r.open = len(r.data)
+	}
+	n := copy(buf, r.data[:r.open])
+	r.data = r.data[n:]
+	r.open -= n
+	return n, nil
+}
+
+func TestSetReadDeadline(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	data = data[:len(data)/4]
+	ref, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	want, err := io.ReadAll(ref)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	r := &gatedReader{data: data, open: len(data) / 2, release: make(chan struct{})}
+	d, err := NewDecoder(r)
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	if err := d.SetReadDeadline(time.Now().Add(20 * time.Millisecond)); err != nil {
+		t.Fatalf("SetReadDeadline failed: %v", err)
+	}
+
+	var got []byte
+	buf := make([]byte, 4096)
+	for {
+		n, err := d.Read(buf)
+		got = append(got, buf[:n]...)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read failed before the deadline: %v", err)
+		}
+	}
+	if len(got) == 0 || len(got) >= len(want) {
+		t.Fatalf("decoded %d bytes before the stall, want a part of %d", len(got), len(want))
+	}
+
+	// After the source recovers, decoding resumes where it stopped.
+	close(r.release)
+	if err := d.SetReadDeadline(time.Time{}); err != nil {
+		t.Fatalf("SetReadDeadline failed: %v", err)
+	}
+	rest, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(append(got, rest...), want) {
+		t.Error("output with a stalled source differs from the plain decode")
+	}
+}
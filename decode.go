@@ -15,13 +15,18 @@
 package mp3
 
 import (
+	"context"
 	"errors"
 	"io"
+	"log/slog"
+	"slices"
 	"time"
 
+	"github.com/llehouerou/go-mp3/id3v2"
 	"github.com/llehouerou/go-mp3/internal/consts"
 	"github.com/llehouerou/go-mp3/internal/frame"
 	"github.com/llehouerou/go-mp3/internal/frameheader"
+	"github.com/llehouerou/go-mp3/lameinfo"
 )
 
 // A Decoder is a MP3-decoded stream.
@@ -36,45 +41,255 @@ type Decoder struct {
 	sampleRate    int
 	length        int64
 	frameStarts   []int64
-	buf           []byte
+	frameHeaders  []frameheader.FrameHeader
+	frameOffsets  []int64
+	frameTimes    []time.Duration
+	rateSegments  []rateSegment
+	skippedBytes  int64
+	buf           pcmRing
 	frame         *frame.Frame
 	pos           int64
 	bytesPerFrame int64
+	logger        *slog.Logger
+	metadata      *id3v2.Tag
+	reservoirFree bool
+	seekWarmup    int
+	seekMode      SeekMode
+	outFormat     OutputFormat
+	converted     []byte
+	pcm           frame.PCM
+	muted         [2]bool
+	karaoke       bool
+	eq            *frame.Equalizer
+	gain          float32
+	spectrum      SpectrumFunc
+	spectrumHook  func(gr, ch int, lines *[consts.SamplesPerGr]float32)
+	meter         MeterFunc
+	peaks         *peakMeter
+	halfRate      bool
+	dither        *ditherer
+	stereo        stereoStats
+
+	deadline    time.Time
+	async       *asyncReader
+	liveContext context.Context
+	transform   TransformFunc
+	limits      Limits
+
+	// indexShared is set when the frame index is shared with a clone, so
+	// that Reset does not overwrite it.
+	indexShared bool
+
+	// loopStart and loopEnd are the positions of the loop set with SetLoop,
+	// if loopEnd is not 0.
+	loopStart, loopEnd int64
+
+	// closer is the file opened by NewDecoderFromFile or NewDecoderFS.
+	closer io.Closer
+
+	// resampler, if set, resamples the output to the rate of
+	// WithOutputSampleRate.
+	resampler *resampler
+
+	// tagFunc receives the tags at the end of the stream while the frames
+	// are indexed.
+	tagFunc TagFunc
+
+	// truncatedFrame is the policy for a truncated last frame.
+	truncatedFrame TruncatedFrame
+
+	// trimEnd, if not 0, is the position at which the output ends before
+	// the encoder padding trimmed by WithPaddingTrim.
+	trimEnd int64
+
+	// audioStart and framesEnd are the offsets of the first frame and past
+	// the last one, and regions the tags and junk found in the stream.
+	audioStart, framesEnd int64
+	regions               []Region
+
+	// priming is the number of bytes of silence output before the audio.
+	priming int64
+
+	// audioEnd is the offset of an ID3v2 tag appended to the stream or the
+	// end of the frames given by the LAME tag, whichever comes first, or -1.
+	// musicEnd is the latter, or 0.
+	audioEnd int64
+	musicEnd int64
+
+	// xing is the Xing header of the first frame, or nil.
+	xing *lameinfo.Info
+
+	// lyrics are the synchronised lyrics used by CurrentLyric, parsed from
+	// the metadata once lyricsParsed is set.
+	lyrics       *id3v2.SyncedLyrics
+	lyricsParsed bool
+
+	// scanProgress receives the progress of the frame scan.
+	scanProgress ScanProgressFunc
+
+	// rateChangeError is set by WithSampleRateChangeError. currentRate is
+	// then the sample rate of the audio read so far.
+	rateChangeError bool
+	currentRate     int
+
+	// firstHeader is the header of the first frame while
+	// WithDeferredFirstFrame defers reading the frame, or 0.
+	firstHeader frameheader.FrameHeader
+
+	// blockBytes is the size of the blocks returned by Read, or 0. blockTail
+	// holds decoded PCM not yet returned because it is less than a block.
+	blockBytes int
+	blockTail  []byte
+
+	estimate durationEstimate
+
+	// frameOffset is the input offset of the current frame, and frameSample
+	// the index of its first sample in the output.
+	frameOffset int64
+	frameSample int64
+
+	// pending is set when the current frame has been read but not decoded
+	// yet.
+	pending bool
+
+	// warmup is set while Seek decodes warmup frames, which are not
+	// reported to the SpectrumFunc and the MeterFunc.
+	warmup bool
+}
+
+// isEndOfAudio reports whether err read at the source position pos marks the
+// end of the audio data rather than a decoding failure. Conditions that are
+// recovered from this way are reported to the logger.
+func (d *Decoder) isEndOfAudio(err error, pos int64) bool {
+	if errors.Is(err, io.EOF) {
+		return true
+	}
+	var unexpectedEOF *consts.UnexpectedEOFError
+	if errors.As(err, &unexpectedEOF) {
+		d.logger.Warn("mp3: truncated frame treated as end of stream",
+			slog.String("at", unexpectedEOF.At), slog.Int64("offset", pos))
+		return true
+	}
+	// If we can't find a valid frame header, we've likely hit
+	// trailing metadata (APE tags, ID3v1, etc.) - treat as end of audio
+	var syncLimitErr *frameheader.SyncSearchLimitError
+	if errors.As(err, &syncLimitErr) {
+		d.logger.Warn("mp3: no frame header found in trailing data, treated as end of stream",
+			slog.Int64("offset", pos), slog.Int64("bytesSearched", syncLimitErr.BytesSearched))
+		return true
+	}
+	return false
 }
 
 func (d *Decoder) readFrame() error {
-	var err error
-	d.frame, _, err = frame.Read(d.source, d.source.pos, d.frame)
+	pos := d.source.pos
+	if err := d.nextFrame(); err != nil {
+		return err
+	}
+	n := d.buf.Len()
+	d.decodeFrame()
+	if d.length == invalidLength {
+		d.estimate.addFrame(d.source.pos-pos, int64(d.buf.Len()-n))
+	}
+	return nil
+}
+
+// decodePending decodes the current frame if it has been read but not
+// decoded yet.
+func (d *Decoder) decodePending() {
+	if d.pending {
+		d.pending = false
+		d.decodeFrame()
+	}
+}
+
+// nextFrame reads the next frame into d.frame without decoding it.
+func (d *Decoder) nextFrame() error {
+	pos := d.source.pos
+	if d.audioEnd >= 0 && pos >= d.audioEnd {
+		return io.EOF
+	}
+	read := frame.Read
+	if d.reservoirFree {
+		read = frame.ReadSelfContained
+	}
+	d.source.record = d.source.record[:0]
+	d.source.recording = true
+	f, start, err := read(d.source, pos, d.frame)
+	d.source.recording = false
 	if err != nil {
-		if errors.Is(err, io.EOF) {
-			return io.EOF
-		}
-		var unexpectedEOF *consts.UnexpectedEOFError
-		if errors.As(err, &unexpectedEOF) {
-			// TODO: Log here?
-			return io.EOF
-		}
-		// If we can't find a valid frame header, we've likely hit
-		// trailing metadata (APE tags, ID3v1, etc.) - treat as end of audio
-		var syncLimitErr *frameheader.SyncSearchLimitError
-		if errors.As(err, &syncLimitErr) {
+		f, start, err = d.readTruncated(err, read, pos)
+	}
+	if err != nil {
+		// Put back the bytes of the partial frame and keep the previous
+		// frame, so that the frame can be read again once more data is
+		// available, e.g. after SetSource.
+		d.source.Unread(slices.Clone(d.source.record))
+		if d.isEndOfAudio(err, pos) {
 			return io.EOF
 		}
 		return err
 	}
-	d.buf = append(d.buf, d.frame.Decode()...)
+	d.frame = f
+	d.firstHeader = 0
+	d.frameOffset = start
+	if exceeds(f.MainDataSize(), d.limits.MaxReservoir) {
+		return &LimitError{Limit: "MaxReservoir", Max: d.limits.MaxReservoir}
+	}
+	d.frameSample = (max(d.pos, d.priming) + int64(d.buf.Len())) / 4
 	return nil
 }
 
 // Read is io.Reader's Read.
+//
+// With WithBlockSize, Read returns whole blocks only.
 func (d *Decoder) Read(buf []byte) (int, error) {
-	for len(d.buf) == 0 {
+	if d.outFormat != OutputS16LE {
+		return d.readConverted(buf)
+	}
+	return d.readS16(buf)
+}
+
+// readS16 reads 16-bit PCM into buf.
+func (d *Decoder) readS16(buf []byte) (int, error) {
+	if d.blockBytes > 0 {
+		return d.readBlocks(buf)
+	}
+	return d.readPCM(buf)
+}
+
+func (d *Decoder) read(buf []byte) (int, error) {
+	buf, err := d.loopBuf(buf)
+	if err != nil {
+		return 0, err
+	}
+	if d.pos < d.priming {
+		n := int(min(int64(len(buf)), d.priming-d.pos))
+		clear(buf[:n])
+		d.pos += int64(n)
+		return n, nil
+	}
+	if d.trimEnd > 0 {
+		if d.pos >= d.trimEnd {
+			return 0, io.EOF
+		}
+		buf = buf[:min(int64(len(buf)), d.trimEnd-d.pos)]
+	}
+	d.decodePending()
+	for d.buf.Len() == 0 {
+		if err := d.awaitFrameData(); err != nil {
+			return 0, err
+		}
 		if err := d.readFrame(); err != nil {
 			return 0, err
 		}
 	}
-	n := copy(buf, d.buf)
-	d.buf = d.buf[n:]
+	if d.rateChangeError {
+		if err := d.checkSampleRate(); err != nil {
+			return 0, err
+		}
+	}
+	n := d.buf.Read(buf)
 	d.pos += int64(n)
 	return n, nil
 }
@@ -83,14 +298,38 @@ func (d *Decoder) Read(buf []byte) (int, error) {
 //
 // Seek returns an error when the underlying source is not io.Seeker.
 //
-// Note that seek uses a byte offset but samples are aligned to 4 bytes (2
-// channels, 2 bytes each). Be careful to seek to an offset that is divisible by
-// 4 if you want to read at full sample boundaries.
+// Samples are 4 bytes long (2 channels, 2 bytes each) in 16-bit output.
+// Seek rounds an offset within a sample down to the start of the sample and
+// returns the rounded offset, so that Read returns whole samples and
+// Position and SamplePosition report the sample read next.
 func (d *Decoder) Seek(offset int64, whence int) (int64, error) {
+	if d.resampler != nil {
+		return d.seekOutputBytes(offset, whence)
+	}
+	if d.outFormat != OutputS16LE {
+		pos, err := d.seek(d.s16Bytes(offset), whence)
+		return d.outputBytes(pos), err
+	}
+	return d.seek(offset, whence)
+}
+
+// seek implements Seek with offsets in 16-bit PCM of the source.
+func (d *Decoder) seek(offset int64, whence int) (int64, error) {
+	if d.resampler != nil {
+		return d.seekResampled(offset, whence)
+	}
+	return d.seekSource(offset, whence)
+}
+
+// seekSource moves to offset in the source PCM.
+func (d *Decoder) seekSource(offset int64, whence int) (int64, error) {
 	if offset == 0 && whence == io.SeekCurrent {
 		// Handle the special case of asking for the current position specially.
 		return d.pos, nil
 	}
+	if d.length == invalidLength {
+		return 0, errors.New("mp3: seek not supported on non-seekable source")
+	}
 
 	npos := int64(0)
 	switch whence {
@@ -99,13 +338,18 @@ func (d *Decoder) Seek(offset int64, whence int) (int64, error) {
 	case io.SeekCurrent:
 		npos = d.pos + offset
 	case io.SeekEnd:
-		npos = d.Length() + offset
+		npos = d.length + offset
 	default:
 		return 0, errors.New("mp3: invalid whence")
 	}
+	// Land on the start of the sample holding npos.
+	npos &^= 3
 	d.pos = npos
-	d.buf = nil
+	d.buf.Reset()
+	d.blockTail = d.blockTail[:0]
 	d.frame = nil
+	d.firstHeader = 0
+	d.pending = false
 
 	// Clamp negative positions to 0
 	if d.pos < 0 {
@@ -117,37 +361,76 @@ func (d *Decoder) Seek(offset int64, whence int) (int64, error) {
 		return npos, nil
 	}
 
-	f := d.pos / d.bytesPerFrame
-	// If the frame is not first, read the previous ahead of reading that
-	// because the previous frame can affect the targeted frame.
-	if f > 0 {
-		f--
-		if _, err := d.source.Seek(d.frameStarts[f], 0); err != nil {
-			return 0, err
-		}
-		if err := d.readFrame(); err != nil {
-			return 0, err
-		}
-		if err := d.readFrame(); err != nil {
-			return 0, err
+	// Position in the audio, after the priming silence.
+	apos := max(d.pos-d.priming, 0)
+	f := d.frameAt(apos)
+
+	// Fast seeking lands on the nearest frame boundary after the priming
+	// silence.
+	if d.seekMode == SeekModeFast && d.pos > d.priming {
+		end := d.length - d.priming
+		if f+1 < int64(len(d.frameOffsets)) {
+			end = d.frameOffsets[f+1]
 		}
-		d.buf = d.buf[d.bytesPerFrame+(d.pos%d.bytesPerFrame):]
-	} else {
-		if _, err := d.source.Seek(d.frameStarts[f], 0); err != nil {
-			return 0, err
+		if apos-d.frameOffsets[f] >= end-apos {
+			f++
+			if f == int64(len(d.frameOffsets)) {
+				d.pos = d.length
+				return d.pos, nil
+			}
 		}
-		if err := d.readFrame(); err != nil {
+		apos = d.frameOffsets[f]
+		d.pos = d.priming + apos
+		npos = d.pos
+	}
+
+	// Decode warmup frames ahead of the targeted frame, because the bit
+	// reservoir and the filterbank state of previous frames affect it.
+	// Reservoir-free decoding does not depend on previous frames.
+	start := f
+	if !d.reservoirFree && d.seekMode != SeekModeFast {
+		start -= d.warmupFrames(f)
+	}
+	if _, err := d.source.Seek(d.frameStarts[start], 0); err != nil {
+		return 0, err
+	}
+	pos := d.pos
+	for i := start; i <= f; i++ {
+		d.pos = d.priming + d.frameOffsets[i]
+		d.buf.Reset()
+		d.warmup = i < f
+		err := d.readFrame()
+		d.warmup = false
+		if err != nil {
+			d.pos = pos
 			return 0, err
 		}
-		d.buf = d.buf[d.pos:]
 	}
+	d.pos = pos
+	d.buf.Discard(int(apos - d.frameOffsets[f]))
 	return npos, nil
 }
 
+// frameAt returns the index of the frame holding the PCM at apos, a position
+// in the audio after the priming silence. Frames can differ in PCM size, e.g.
+// when the sample rate changes, so the index is searched rather than
+// computed.
+func (d *Decoder) frameAt(apos int64) int64 {
+	f, found := slices.BinarySearch(d.frameOffsets, apos)
+	if !found {
+		f--
+	}
+	return int64(max(f, 0))
+}
+
 // SampleRate returns the sample rate like 44100.
 //
-// Note that the sample rate is retrieved from the first frame.
+// Note that the sample rate is retrieved from the first frame. See
+// WithSampleRateChangeError for streams whose rate changes.
 func (d *Decoder) SampleRate() int {
+	if d.resampler != nil {
+		return int(d.resampler.out)
+	}
 	return d.sampleRate
 }
 
@@ -165,6 +448,19 @@ func (d *Decoder) ensureFrameStartsAndLength() error {
 	if err != nil {
 		return err
 	}
+	d.audioEnd, err = d.source.appendedTagStart()
+	if err != nil {
+		return err
+	}
+	trailing, end, err := d.source.trailingTags(d.audioEnd)
+	if err != nil {
+		return err
+	}
+	if d.tagFunc != nil {
+		if err := d.source.reportTags(d.tagFunc, trailing); err != nil {
+			return err
+		}
+	}
 	if err := d.source.rewind(); err != nil {
 		return err
 	}
@@ -174,32 +470,44 @@ func (d *Decoder) ensureFrameStartsAndLength() error {
 	}
 	l := int64(0)
 	for {
+		if d.audioEnd >= 0 && d.source.pos >= d.audioEnd {
+			break
+		}
+		expected := d.source.pos
 		h, pos, err := frameheader.Read(d.source, d.source.pos)
 		if err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			var unexpectedEOF *consts.UnexpectedEOFError
-			if errors.As(err, &unexpectedEOF) {
-				// TODO: Log here?
-				break
-			}
-			// If we can't find a valid frame header, we've likely hit
-			// trailing metadata (APE tags, ID3v1, etc.) - treat as end of audio
-			var syncLimitErr *frameheader.SyncSearchLimitError
-			if errors.As(err, &syncLimitErr) {
+			if d.isEndOfAudio(err, d.source.pos) {
 				break
 			}
 			return err
 		}
+		d.skippedBytes += pos - expected
+		d.addRegion(RegionJunk, expected, pos-expected)
+		if exceeds(len(d.frameStarts)+1, d.limits.MaxFrames) {
+			return &LimitError{Limit: "MaxFrames", Max: d.limits.MaxFrames}
+		}
 		d.frameStarts = append(d.frameStarts, pos)
-		d.bytesPerFrame = int64(h.BytesPerFrame())
-		l += d.bytesPerFrame
+		d.frameHeaders = append(d.frameHeaders, h)
+		d.frameOffsets = append(d.frameOffsets, l)
+		d.addFrameTime(h, l)
+		// Each frame counts with its own PCM size, which differs between
+		// MPEG versions and with WithHalfRate.
+		l += d.pcmBytes(h)
 
 		framesize, err := h.FrameSize()
 		if err != nil {
 			return err
 		}
+		d.framesEnd = min(pos+int64(framesize), end)
+		if len(d.frameStarts) == 1 {
+			if err := d.scanXingHeader(pos, framesize); err != nil {
+				return err
+			}
+			d.boundByMusicLength(pos)
+		}
+		if d.scanProgress != nil && len(d.frameStarts)%scanProgressInterval == 0 {
+			d.scanProgress(d.framesEnd, end)
+		}
 		if _, err := d.source.Seek(int64(framesize-4), io.SeekCurrent); err != nil {
 			if errors.Is(err, io.EOF) {
 				break
@@ -207,7 +515,19 @@ func (d *Decoder) ensureFrameStartsAndLength() error {
 			return err
 		}
 	}
-	d.length = l
+	d.length = l + d.priming
+	if d.scanProgress != nil {
+		d.scanProgress(end, end)
+	}
+	d.checkMusicLength()
+
+	tagsStart := end
+	for _, t := range trailing {
+		d.addRegion(RegionKind(t.kind), t.start, t.end-t.start)
+		tagsStart = min(tagsStart, t.start)
+	}
+	d.addRegion(RegionJunk, d.framesEnd, tagsStart-d.framesEnd)
+	d.sortRegions()
 
 	if _, err := d.source.Seek(pos, io.SeekStart); err != nil {
 		return err
@@ -217,32 +537,66 @@ func (d *Decoder) ensureFrameStartsAndLength() error {
 
 const invalidLength = -1
 
+// ErrNoAudioFrames matches, with errors.Is, the error returned by NewDecoder
+// for input without audio frames.
+var ErrNoAudioFrames = errors.New("mp3: no audio frames")
+
+// NoAudioFramesError is returned by NewDecoder when the input holds no audio
+// frames, as with empty files and files consisting only of tags. It lets
+// upload validators tell such files from corrupted ones while still reading
+// their tags.
+type NoAudioFramesError struct {
+	// Metadata is the ID3v2 tag of the input, or nil.
+	Metadata *id3v2.Tag
+}
+
+func (e *NoAudioFramesError) Error() string {
+	return ErrNoAudioFrames.Error()
+}
+
+func (e *NoAudioFramesError) Unwrap() error {
+	return ErrNoAudioFrames
+}
+
 // Length returns the total size in bytes.
 //
 // Length returns -1 when the total size is not available
 // e.g. when the given source is not io.Seeker.
 func (d *Decoder) Length() int64 {
-	return d.length
+	if d.length == invalidLength {
+		return invalidLength
+	}
+	return d.outputBytes(d.length)
 }
 
 // BytesPerFrame returns the number of decoded bytes per MP3 frame.
 // This is useful for calculating frame timing or positions.
+//
+// It is the size of the first frame. Streams mixing MPEG versions have
+// frames of different sizes, which Length sums one by one.
 func (d *Decoder) BytesPerFrame() int64 {
-	return d.bytesPerFrame
+	return d.outputBytes(d.bytesPerFrame)
 }
 
 // Duration returns the total duration of the audio stream.
-// Returns -1 if the duration cannot be determined (e.g., non-seekable source).
+//
+// For sources that are not io.Seeker, Duration returns an estimate: from the
+// frame count of the Xing header if there is one, or else from the content
+// length given by WithContentLength and the average size of the frames read
+// so far, so that the estimate improves as the stream is decoded. It returns
+// -1 if no estimate is possible.
 func (d *Decoder) Duration() time.Duration {
 	if d.length == invalidLength {
-		return -1
+		return d.estimatedDuration()
 	}
-	return d.bytesToDuration(d.length)
+	return d.timeAt(d.length)
 }
 
 // Position returns the current playback position as a time.Duration.
 func (d *Decoder) Position() time.Duration {
-	return d.bytesToDuration(d.pos)
+	// After a Read ending within a sample, the position is that of the
+	// sample.
+	return d.timeAt(d.playedPos() &^ 3)
 }
 
 // Remaining returns the remaining duration from the current position.
@@ -252,7 +606,8 @@ func (d *Decoder) Remaining() time.Duration {
 	if dur < 0 {
 		return -1
 	}
-	return dur - d.Position()
+	// An estimated duration can be shorter than the position.
+	return max(dur-d.Position(), 0)
 }
 
 // Progress returns the playback progress as a value between 0.0 and 1.0.
@@ -264,12 +619,15 @@ func (d *Decoder) Progress() float64 {
 	if d.length == 0 {
 		return 0
 	}
-	return float64(d.pos) / float64(d.length)
+	return float64(d.playedPos()) / float64(d.length)
 }
 
 // SamplePosition returns the current position in samples (per channel).
 // Each sample is 4 bytes (stereo 16-bit).
 func (d *Decoder) SamplePosition() int64 {
+	if d.resampler != nil {
+		return d.outPos() / 4
+	}
 	return d.pos / 4
 }
 
@@ -279,6 +637,9 @@ func (d *Decoder) SampleCount() int64 {
 	if d.length == invalidLength {
 		return -1
 	}
+	if d.resampler != nil {
+		return d.resampler.outSamples(d.length / 4)
+	}
 	return d.length / 4
 }
 
@@ -300,9 +661,13 @@ func (d *Decoder) SeekToSample(sample int64) error {
 		sample = maxSamples
 	}
 
+	if d.resampler != nil {
+		return d.seekOutput(sample)
+	}
+
 	// Convert to bytes (4 bytes per sample)
 	bytes := sample * 4
-	_, err := d.Seek(bytes, io.SeekStart)
+	_, err := d.seek(bytes, io.SeekStart)
 	return err
 }
 
@@ -332,11 +697,7 @@ func (d *Decoder) SeekToTime(t time.Duration) error {
 		t = maxDur
 	}
 
-	// Convert to bytes and align to 4-byte sample boundary
-	bytes := d.durationToBytes(t)
-	bytes &^= 3 // Align to 4-byte boundary
-
-	_, err := d.Seek(bytes, io.SeekStart)
+	_, err := d.seek(d.posAt(t), io.SeekStart)
 	return err
 }
 
@@ -344,13 +705,17 @@ func (d *Decoder) SeekToTime(t time.Duration) error {
 func (d *Decoder) bytesToDuration(bytes int64) time.Duration {
 	// bytes = samples * 4 (stereo 16-bit)
 	// duration = samples / sampleRate = bytes / (sampleRate * 4)
-	return time.Duration(int64(time.Second) * bytes / int64(d.sampleRate*4))
+	// Split the seconds off to avoid overflowing on long streams.
+	rate := int64(d.sampleRate * 4)
+	return time.Duration(bytes/rate)*time.Second +
+		time.Duration(bytes%rate)*time.Second/time.Duration(rate)
 }
 
 // durationToBytes converts a time.Duration to a byte position.
 func (d *Decoder) durationToBytes(dur time.Duration) int64 {
 	// Formula: bytes = duration_seconds * sampleRate * 4 (stereo 16-bit)
-	return int64(dur) * int64(d.sampleRate*4) / int64(time.Second)
+	rate := int64(d.sampleRate * 4)
+	return int64(dur/time.Second)*rate + int64(dur%time.Second)*rate/int64(time.Second)
 }
 
 // NewDecoder decodes the given io.Reader and returns a decoded stream.
@@ -358,31 +723,153 @@ func (d *Decoder) durationToBytes(dur time.Duration) int64 {
 // The stream is always formatted as 16bit (little endian) 2 channels
 // even if the source is single channel MP3.
 // Thus, a sample always consists of 4 bytes.
-func NewDecoder(r io.Reader) (*Decoder, error) {
+//
+// Optional behavior can be configured with opts.
+//
+// If the input holds no audio frames, such as an empty file or a file made
+// of tags only, NewDecoder returns a *NoAudioFramesError.
+func NewDecoder(r io.Reader, opts ...Option) (*Decoder, error) {
+	d := &Decoder{}
+	if err := d.init(r, newConfig(opts)); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Reset makes d decode r as if d had been returned by NewDecoder(r, opts...),
+// so that a pool of decoders can be reused across files. All state of the
+// previous file is dropped, including its sample rate, frame index, metadata,
+// filterbank memory and channel settings, while buffers are reused.
+//
+// A file opened by NewDecoderFromFile or NewDecoderFS is closed.
+//
+// If Reset returns an error, d must not be used until a later Reset succeeds.
+func (d *Decoder) Reset(r io.Reader, opts ...Option) error {
+	_ = d.Close()
+	return d.init(r, newConfig(opts))
+}
+
+// init prepares d to decode r, reusing the buffers d already holds.
+func (d *Decoder) init(r io.Reader, cfg config) error {
+	if cfg.transform != nil {
+		r = newTransformReader(r, cfg.transform, 0)
+	}
+	if cfg.liveContext != nil {
+		r = &liveReader{ctx: cfg.liveContext, reader: r}
+	}
 	s := &source{
-		reader: r,
+		reader:     r,
+		maxTagSize: cfg.limits.MaxTagSize,
+	}
+	if d.indexShared {
+		d.frameStarts, d.frameHeaders, d.frameOffsets = nil, nil, nil
+		d.frameTimes, d.rateSegments = nil, nil
+	}
+	*d = Decoder{
+		source:         s,
+		length:         invalidLength,
+		frameStarts:    d.frameStarts[:0],
+		frameHeaders:   d.frameHeaders[:0],
+		frameOffsets:   d.frameOffsets[:0],
+		frameTimes:     d.frameTimes[:0],
+		rateSegments:   d.rateSegments[:0],
+		audioEnd:       -1,
+		priming:        4 * int64(cfg.primingSamples),
+		blockBytes:     4 * cfg.blockSamples,
+		blockTail:      d.blockTail[:0],
+		liveContext:    cfg.liveContext,
+		transform:      cfg.transform,
+		logger:         cfg.logger,
+		reservoirFree:  cfg.reservoirFree,
+		seekWarmup:     cfg.seekWarmup,
+		seekMode:       cfg.seekMode,
+		outFormat:      cfg.outFormat,
+		gain:           gainFactor(cfg.gain),
+		halfRate:       cfg.halfRate,
+		converted:      d.converted[:0],
+		limits:         cfg.limits,
+		tagFunc:        cfg.tagFunc,
+		truncatedFrame: cfg.truncatedFrame,
+		scanProgress:   cfg.scanProgress,
+
+		rateChangeError: cfg.rateChangeError,
 	}
-	d := &Decoder{
-		source: s,
-		length: invalidLength,
+	if exceeds(d.blockBytes, d.limits.MaxBufferedPCM) {
+		return &LimitError{Limit: "MaxBufferedPCM", Max: d.limits.MaxBufferedPCM}
 	}
 
+	if cfg.dither != DitherNone {
+		d.dither = newDitherer(cfg.dither)
+	}
+
+	s.onID3v2 = d.parseMetadata
+	s.onTag = d.onLeadingTag
 	if err := s.skipTags(); err != nil {
-		return nil, err
+		if errors.Is(err, io.EOF) {
+			return &NoAudioFramesError{Metadata: d.metadata}
+		}
+		return err
 	}
-	// TODO: Is readFrame here really needed?
-	if err := d.readFrame(); err != nil {
-		return nil, err
+	s.onID3v2 = nil
+	s.onTag = nil
+	d.estimate.contentLength = cfg.contentLength
+	d.estimate.audioStart = s.pos
+	if _, ok := r.(io.Seeker); !ok {
+		d.peekXingHeader()
+	}
+	// The first frame is only decoded by the first read, so that
+	// AnalyzeFrame can analyze it instead. With WithDeferredFirstFrame, only
+	// its header is read here.
+	pos := s.pos
+	readFirst := d.nextFrame
+	if cfg.deferFirst {
+		readFirst = d.peekFirstHeader
+	}
+	if err := readFirst(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return &NoAudioFramesError{Metadata: d.metadata}
+		}
+		return err
+	}
+	h, _ := d.currentHeader()
+	d.bytesPerFrame = d.pcmBytes(h)
+	if cfg.deferFirst {
+		// The frame is counted once read.
+		d.estimate.pcmPerFrame = d.pcmBytes(h)
+	} else {
+		d.pending = true
+		d.estimate.addFrame(s.pos-pos, d.pcmBytes(h))
 	}
-	freq, err := d.frame.SamplingFrequency()
+	d.audioStart = d.frameOffset
+	if _, ok := r.(io.Seeker); !ok {
+		// Indexing the frames finds the junk of io.Seeker sources.
+		d.addRegion(RegionJunk, pos, d.frameOffset-pos)
+	}
+	freq, err := h.SamplingFrequencyValue()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	d.sampleRate = freq
+	if d.halfRate {
+		d.sampleRate /= 2
+	}
+	d.currentRate = d.sampleRate
 
 	if err := d.ensureFrameStartsAndLength(); err != nil {
-		return nil, err
+		return err
 	}
-
-	return d, nil
+	if cfg.paddingTrim {
+		d.trimPadding()
+	}
+	if cfg.skipSilence {
+		if err := d.skipLeadingSilence(cfg.silenceLevel); err != nil {
+			return err
+		}
+	}
+	if cfg.outRate > 0 && cfg.outRate != d.sampleRate {
+		r := newResampler(d.sampleRate, cfg.outRate, speedScale)
+		r.reset(r.timeOf(r.outSamples(d.pos/4)), d.pos/4)
+		d.resampler = r
+	}
+	return nil
 }
@@ -0,0 +1,171 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/llehouerou/go-mp3/internal/frameheader"
+)
+
+// FrameInfo describes an MP3 frame of a stream.
+type FrameInfo struct {
+	// Offset is the offset of the frame header in the input.
+	Offset int64
+
+	// Size is the size of the frame in bytes, including its header.
+	Size int
+
+	// Header holds the fields of the frame header.
+	Header Header
+
+	// Sample is the index of the first sample of the frame in the decoded
+	// stream, counted per channel.
+	Sample int64
+
+	// Time is the time of the first sample of the frame.
+	Time time.Duration
+}
+
+// DecodeFrame decodes the next frame and returns its description and its
+// PCM, in the same format as Read. It lets audio engines that schedule work
+// in frame-sized quanta pull exactly one frame at a time.
+//
+// DecodeFrame and Read can be mixed: when PCM of the current frame is left
+// over from Read or Seek, DecodeFrame returns that remainder with the
+// description of the current frame. Likewise, the frame holding the loop
+// end of SetLoop is cut there, and the next call continues from the loop
+// start. The priming silence of
+// WithPrimingSilence is returned in chunks of up to a frame with a zero
+// FrameInfo whose Offset is -1.
+//
+// The returned PCM is only valid until the next call to a method of the
+// Decoder. At the end of the stream, DecodeFrame returns io.EOF.
+//
+// DecodeFrame returns an error with WithOutputSampleRate or SetSpeed, whose
+// resampled output is not made of frames.
+func (d *Decoder) DecodeFrame() (FrameInfo, []byte, error) {
+	if d.resampler != nil {
+		return FrameInfo{}, nil, errors.New("mp3: DecodeFrame not supported with resampling")
+	}
+	if d.loopEnd > 0 && d.pos >= d.loopEnd {
+		if _, err := d.seek(d.loopStart, io.SeekStart); err != nil {
+			return FrameInfo{}, nil, err
+		}
+	}
+	end := d.outputEnd()
+	if end >= 0 && d.pos >= end {
+		return FrameInfo{}, nil, io.EOF
+	}
+	if d.pos < d.priming {
+		h, _ := d.currentHeader()
+		n := min(d.priming-d.pos, d.pcmBytes(h))
+		if end >= 0 {
+			n = min(n, end-d.pos)
+		}
+		pcm := make([]byte, n)
+		info := FrameInfo{Offset: -1, Sample: d.pos / 4, Time: d.bytesToDuration(d.pos)}
+		d.pos += n
+		return info, d.convertPCM(pcm), nil
+	}
+	d.decodePending()
+	if d.buf.Len() == 0 && len(d.blockTail) == 0 {
+		if err := d.awaitFrameData(); err != nil {
+			return FrameInfo{}, nil, err
+		}
+		if err := d.readFrame(); err != nil {
+			return FrameInfo{}, nil, err
+		}
+	}
+	pcm := d.buf.Bytes()
+	if len(d.blockTail) > 0 {
+		pcm = append(d.blockTail, pcm...)
+		d.blockTail = d.blockTail[:0]
+	}
+	d.buf.Discard(d.buf.Len())
+	if end >= 0 {
+		pcm = pcm[:min(int64(len(pcm)), end-d.pos)]
+	}
+	d.pos += int64(len(pcm))
+	return d.frameInfo(), d.convertPCM(pcm), nil
+}
+
+// outputEnd returns the position at which DecodeFrame stops the PCM it
+// returns: the loop end of SetLoop, after which it jumps back to the loop
+// start, or the end trimmed by WithPaddingTrim, or -1.
+func (d *Decoder) outputEnd() int64 {
+	switch {
+	case d.loopEnd > 0:
+		return d.loopEnd
+	case d.trimEnd > 0:
+		return d.trimEnd
+	}
+	return -1
+}
+
+// CurrentFrameInfo returns the description of the frame currently being
+// decoded: the frame whose PCM Read returns next, or has just returned when
+// the PCM of the frame is used up. Players can show its header fields, such
+// as the bitrate and channel mode, as live information on VBR streams. It
+// reports false when there is no current frame, such as after seeking to the
+// end of the stream.
+func (d *Decoder) CurrentFrameInfo() (FrameInfo, bool) {
+	if _, ok := d.currentHeader(); !ok {
+		return FrameInfo{}, false
+	}
+	return d.frameInfo(), true
+}
+
+// frameInfo returns the description of the current frame.
+func (d *Decoder) frameInfo() FrameInfo {
+	h, _ := d.currentHeader()
+	return d.infoOf(h, d.frameOffset, d.frameSample)
+}
+
+// infoOf returns the description of the frame with header h found at offset
+// in the input, whose first sample is sample.
+func (d *Decoder) infoOf(h frameheader.FrameHeader, offset, sample int64) FrameInfo {
+	info := FrameInfo{
+		Offset: offset,
+		Sample: sample,
+		Time:   d.timeAt(sample * 4),
+	}
+	info.Header, _ = headerOf(h)
+	info.Size, _ = h.FrameSize()
+	return info
+}
+
+// FrameCount returns the number of frames of the stream, or -1 when the
+// source is not io.Seeker.
+func (d *Decoder) FrameCount() int {
+	if d.length == invalidLength {
+		return -1
+	}
+	return len(d.frameStarts)
+}
+
+// FrameInfo returns the description of the frame n, counted from 0, as
+// recorded by the scan of the stream. Cut editors and diagnostic tools can
+// use it to inspect the physical layout of the stream, such as changes of
+// bitrate or channel mode. It reports false if n is out of range or the
+// source is not io.Seeker.
+func (d *Decoder) FrameInfo(n int) (FrameInfo, bool) {
+	if d.length == invalidLength || n < 0 || n >= len(d.frameStarts) {
+		return FrameInfo{}, false
+	}
+	sample := (d.priming + d.frameOffsets[n]) / 4
+	return d.infoOf(d.frameHeaders[n], d.frameStarts[n], sample), true
+}
+
+// FrameOffsets returns the input offsets of the frame headers, in order,
+// from the index built by the scan of the stream, so that cutters and cue
+// generators can map times to file offsets without scanning the file again.
+// Frame n starts at the time of FrameInfo(n). FrameOffsets returns nil when
+// the source is not io.Seeker.
+func (d *Decoder) FrameOffsets() []int64 {
+	if d.length == invalidLength {
+		return nil
+	}
+	return slices.Clone(d.frameStarts)
+}
@@ -0,0 +1,302 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/frameheader"
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+func TestDecodeFrame(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	ref, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := io.ReadAll(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []byte
+	for i := 0; ; i++ {
+		info, pcm, err := d.DecodeFrame()
+		if errors.Is(err, io.EOF) {
+			if i != len(d.frameStarts) {
+				t.Errorf("decoded %d frames, want %d", i, len(d.frameStarts))
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Offset != d.frameStarts[i] {
+			t.Fatalf("frame %d: Offset = %d, want %d", i, info.Offset, d.frameStarts[i])
+		}
+		if info.Sample != int64(len(got)/4) {
+			t.Fatalf("frame %d: Sample = %d, want %d", i, info.Sample, len(got)/4)
+		}
+		if len(pcm) != info.Header.SamplesPerFrame*4 {
+			t.Fatalf("frame %d: %d bytes of PCM, want %d", i, len(pcm), info.Header.SamplesPerFrame*4)
+		}
+		if info.Header.SampleRate != 44100 || info.Size != FrameSizeOf(info.Header) {
+			t.Fatalf("frame %d: unexpected info %+v", i, info)
+		}
+		got = append(got, pcm...)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("PCM from DecodeFrame differs from Read")
+	}
+}
+
+func TestDecodeFrame_AfterReadAndSeek(t *testing.T) {
+	f, err := os.Open("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	d, err := NewDecoder(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(d, make([]byte, 1000)); err != nil {
+		t.Fatal(err)
+	}
+	info, pcm, err := d.DecodeFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Sample != 0 || len(pcm) != int(d.BytesPerFrame())-1000 {
+		t.Errorf("remainder of the first frame: Sample %d, %d bytes", info.Sample, len(pcm))
+	}
+
+	frame := int64(100)
+	if _, err := d.Seek(frame*d.BytesPerFrame()+400, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	info, pcm, err = d.DecodeFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Offset != d.frameStarts[frame] || info.Sample != frame*d.BytesPerFrame()/4 {
+		t.Errorf("after Seek: Offset %d, Sample %d, want %d and %d",
+			info.Offset, info.Sample, d.frameStarts[frame], frame*d.BytesPerFrame()/4)
+	}
+	if len(pcm) != int(d.BytesPerFrame())-400 {
+		t.Errorf("after Seek: %d bytes of PCM, want %d", len(pcm), d.BytesPerFrame()-400)
+	}
+	info, _, err = d.DecodeFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Offset != d.frameStarts[frame+1] {
+		t.Errorf("next frame Offset = %d, want %d", info.Offset, d.frameStarts[frame+1])
+	}
+}
+
+func TestDecodeFrame_PrimingSilence(t *testing.T) {
+	f, err := os.Open("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	d, err := NewDecoder(f, WithPrimingSilence(2000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var silence int
+	for {
+		info, pcm, err := d.DecodeFrame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Offset != -1 {
+			if info.Sample != 2000 {
+				t.Errorf("first frame Sample = %d, want 2000", info.Sample)
+			}
+			break
+		}
+		if !bytes.Equal(pcm, make([]byte, len(pcm))) {
+			t.Fatal("priming PCM is not silent")
+		}
+		silence += len(pcm)
+	}
+	if silence != 2000*4 {
+		t.Errorf("got %d bytes of priming silence, want %d", silence, 2000*4)
+	}
+}
+
+func TestFrameInfo_MatchesDecodeFrame(t *testing.T) {
+	f, err := os.Open("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	d, err := NewDecoder(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := d.FrameCount()
+	if n != len(d.frameStarts) {
+		t.Fatalf("FrameCount() = %d, want %d", n, len(d.frameStarts))
+	}
+	for i := range n {
+		want, _, err := d.DecodeFrame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := d.FrameInfo(i)
+		if !ok {
+			t.Fatalf("FrameInfo(%d) not available", i)
+		}
+		if got != want {
+			t.Fatalf("FrameInfo(%d) = %+v, want %+v", i, got, want)
+		}
+	}
+	if _, ok := d.FrameInfo(n); ok {
+		t.Errorf("FrameInfo(%d) past the end reported ok", n)
+	}
+	if _, ok := d.FrameInfo(-1); ok {
+		t.Error("FrameInfo(-1) reported ok")
+	}
+}
+
+func TestFrameOffsets(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	offsets := d.FrameOffsets()
+	if len(offsets) != d.FrameCount() {
+		t.Fatalf("%d offsets, want %d", len(offsets), d.FrameCount())
+	}
+	for i, off := range offsets {
+		info, _ := d.FrameInfo(i)
+		if off != info.Offset || data[off] != 0xff {
+			t.Fatalf("offset %d = %d, want the frame header at %d", i, off, info.Offset)
+		}
+	}
+	// The offsets are a copy of the index.
+	offsets[0] = -1
+	if d.FrameOffsets()[0] == -1 {
+		t.Error("changing the returned offsets changed the index")
+	}
+
+	d, err = NewDecoder(&nonSeekableReader{bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offsets := d.FrameOffsets(); offsets != nil {
+		t.Errorf("non-seekable FrameOffsets() = %d offsets, want nil", len(offsets))
+	}
+}
+
+func TestFrameInfo_MixedFrames(t *testing.T) {
+	var data []byte
+	for _, f := range []struct {
+		bitrate       int
+		mono, padding bool
+	}{
+		{128, false, false},
+		{128, false, true},
+		{320, true, false},
+		{64, false, false},
+	} {
+		b, err := testsupport.Frame(44100, f.bitrate, f.mono, f.padding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = append(data, b...)
+	}
+
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, ok := d.FrameInfo(2)
+	if !ok {
+		t.Fatal("FrameInfo(2) not available")
+	}
+	if info.Header.Bitrate != 320000 || info.Header.Mode != frameheader.ModeSingleChannel ||
+		info.Size != 1044 || info.Offset != 417+418 || info.Sample != 2*1152 {
+		t.Errorf("FrameInfo(2) = %+v", info)
+	}
+	if info, _ := d.FrameInfo(1); !info.Header.Padding || info.Size != 418 {
+		t.Errorf("FrameInfo(1) = %+v, want a padded 418-byte frame", info)
+	}
+
+	ns, err := NewDecoder(nonSeekable{bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ns.FrameInfo(0); ok || ns.FrameCount() != -1 {
+		t.Errorf("non-seekable source: FrameInfo available, FrameCount %d", ns.FrameCount())
+	}
+}
+
+func TestCurrentFrameInfo(t *testing.T) {
+	var data []byte
+	for _, f := range []struct {
+		bitrate int
+		mono    bool
+	}{
+		{128, false},
+		{320, true},
+		{64, false},
+	} {
+		b, err := testsupport.Frame(44100, f.bitrate, f.mono, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = append(data, b...)
+	}
+	// Mark the last frame as a copyrighted copy with 50/15 µs emphasis and
+	// middle/side stereo.
+	data[417+1044+3] = 0x40 | 0x20 | 0x08 | 0x01
+
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1152*4)
+	for i, want := range []int{128000, 320000, 64000} {
+		if _, err := io.ReadFull(d, buf); err != nil {
+			t.Fatal(err)
+		}
+		info, ok := d.CurrentFrameInfo()
+		if !ok || info.Header.Bitrate != want || info.Sample != int64(i*1152) {
+			t.Errorf("frame %d: CurrentFrameInfo() = %+v, %v, want %d bps", i, info, ok, want)
+		}
+	}
+	info, _ := d.CurrentFrameInfo()
+	h := info.Header
+	if h.Mode != frameheader.ModeJointStereo || h.ModeExtension != 2 || !h.Copyright || h.Original ||
+		h.Emphasis != frameheader.Emphasis50_15 {
+		t.Errorf("last frame header = %+v", h)
+	}
+
+	if _, err := d.Seek(0, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := d.CurrentFrameInfo(); ok {
+		t.Error("CurrentFrameInfo available at the end of the stream")
+	}
+}
@@ -0,0 +1,61 @@
+package mp3
+
+import (
+	"io"
+	"slices"
+
+	"github.com/llehouerou/go-mp3/internal/frameheader"
+)
+
+// WithDeferredFirstFrame makes NewDecoder parse only the header of the first
+// frame, which gives the format of the stream, instead of reading the whole
+// frame. The first Read, DecodeFrame or AnalyzeFrame reads it instead. This
+// lowers the latency of opening a stream for metadata-only use, such as
+// reading its tags, sample rate or duration.
+//
+// Errors in the first frame past its header are then returned by the first
+// read rather than by NewDecoder. For sources that are not io.Seeker, a
+// duration estimated from WithContentLength is only available once the
+// first frame is read.
+func WithDeferredFirstFrame() Option {
+	return func(c *config) {
+		c.deferFirst = true
+	}
+}
+
+// peekFirstHeader parses the header of the first frame into d.firstHeader
+// without consuming the frame.
+func (d *Decoder) peekFirstHeader() error {
+	pos := d.source.pos
+	d.source.record = d.source.record[:0]
+	d.source.recording = true
+	h, start, err := frameheader.Read(d.source, pos)
+	d.source.recording = false
+	if _, ok := d.source.reader.(io.Seeker); ok {
+		if _, err := d.source.Seek(pos, io.SeekStart); err != nil {
+			return err
+		}
+	} else {
+		d.source.Unread(slices.Clone(d.source.record))
+	}
+	if err != nil {
+		if d.isEndOfAudio(err, pos) {
+			return io.EOF
+		}
+		return err
+	}
+	d.firstHeader = h
+	d.frameOffset = start
+	d.frameSample = d.priming / 4
+	return nil
+}
+
+// currentHeader returns the header of the current frame, or of the first
+// frame while WithDeferredFirstFrame defers reading it. It reports false
+// when there is no current frame.
+func (d *Decoder) currentHeader() (frameheader.FrameHeader, bool) {
+	if d.frame != nil {
+		return d.frame.Header(), true
+	}
+	return d.firstHeader, d.firstHeader != 0
+}
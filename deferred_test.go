@@ -0,0 +1,63 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestWithDeferredFirstFrame(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, want := decodeFresh(t, data)
+
+	sources := map[string]func() io.Reader{
+		"seekable":     func() io.Reader { return bytes.NewReader(data) },
+		"non-seekable": func() io.Reader { return &nonSeekableReader{bytes.NewReader(data)} },
+	}
+	for name, source := range sources {
+		d, err := NewDecoder(source(), WithDeferredFirstFrame())
+		if err != nil {
+			t.Fatalf("%s: NewDecoder failed: %v", name, err)
+		}
+		if d.frame != nil {
+			t.Errorf("%s: the first frame was read by NewDecoder", name)
+		}
+		if d.SampleRate() != 44100 || d.Channels() != 2 {
+			t.Errorf("%s: SampleRate %d, Channels %d, want 44100 and 2", name, d.SampleRate(), d.Channels())
+		}
+		if info, ok := d.CurrentFrameInfo(); !ok || info.Offset != 0 || info.Header.SampleRate != 44100 {
+			t.Errorf("%s: CurrentFrameInfo = %+v, %v, want the first frame", name, info, ok)
+		}
+		got, err := io.ReadAll(d)
+		if err != nil {
+			t.Fatalf("%s: ReadAll failed: %v", name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: got %d bytes, want the %d bytes decoded without the option", name, len(got), len(want))
+		}
+	}
+}
+
+func TestWithDeferredFirstFrame_TruncatedFirstFrame(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	data = data[:20]
+
+	if _, err := NewDecoder(bytes.NewReader(data)); !errors.Is(err, ErrNoAudioFrames) {
+		t.Errorf("NewDecoder returned %v, want ErrNoAudioFrames", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data), WithDeferredFirstFrame())
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if n, err := d.Read(make([]byte, 4096)); n != 0 || !errors.Is(err, io.EOF) {
+		t.Errorf("Read = %d, %v, want io.EOF", n, err)
+	}
+}
@@ -0,0 +1,80 @@
+package mp3
+
+import (
+	"math/rand/v2"
+
+	"github.com/llehouerou/go-mp3/internal/frame"
+)
+
+// A Dither is a dithering method applied when the decoded samples are
+// quantized to 16 bits.
+type Dither int
+
+const (
+	// DitherNone truncates the samples. It is the default.
+	DitherNone Dither = iota
+
+	// DitherTPDF adds triangular noise of ±1 LSB before rounding, which
+	// turns the quantization distortion of quiet passages into a constant,
+	// signal-independent noise floor.
+	DitherTPDF
+
+	// DitherShaped is DitherTPDF with first-order noise shaping, which moves
+	// the noise toward high frequencies where hearing is less sensitive.
+	DitherShaped
+)
+
+// WithDither sets the dithering method used when the decoded samples are
+// quantized to 16 bits, for archival transcodes where low-level detail
+// matters. The noise is pseudo-random with a fixed seed, so the output is
+// reproducible.
+func WithDither(m Dither) Option {
+	return func(c *config) {
+		c.dither = m
+	}
+}
+
+// ditherer quantizes samples with dither.
+type ditherer struct {
+	method Dither
+	rng    *rand.Rand
+
+	// err holds the quantization error of the last sample of each channel,
+	// fed back for noise shaping.
+	err [2]float32
+}
+
+func newDitherer(m Dither) *ditherer {
+	return &ditherer{method: m, rng: rand.New(rand.NewPCG(0x6d7033, 0x646974))}
+}
+
+// appendS16 is like frame.AppendS16 but dithers the samples.
+func (q *ditherer) appendS16(buf []byte, pcm *frame.PCM, n int) []byte {
+	for i := range n {
+		l := q.quantize(0, pcm[0][i])
+		r := q.quantize(1, pcm[1][i])
+		buf = append(buf, byte(l), byte(l>>8), byte(r), byte(r>>8))
+	}
+	return buf
+}
+
+func (q *ditherer) quantize(ch int, sample float32) int16 {
+	x := sample * 32767
+	if q.method == DitherShaped {
+		x -= q.err[ch]
+	}
+	noise := q.rng.Float32() - q.rng.Float32()
+	v := x + noise
+	// Round to nearest.
+	if v >= 0 {
+		v += 0.5
+	} else {
+		v -= 0.5
+	}
+	s := int(v)
+	s = max(min(s, 32767), -32767)
+	// Without clipping the error stays within ±1.5 LSB; bound it so that a
+	// clipped sample does not feed back a large error.
+	q.err[ch] = max(min(float32(s)-x, 1.5), -1.5)
+	return int16(s) //nolint:gosec // s is clamped to [-32767, 32767] above
+}
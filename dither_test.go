@@ -0,0 +1,77 @@
+package mp3
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/internal/frame"
+)
+
+// quietSine returns n samples of a sine with an amplitude of 0.4 LSB, which
+// truncation quantizes to silence.
+func quietSine(n int) *frame.PCM {
+	var pcm frame.PCM
+	for i := range n {
+		v := float32(0.4 * math.Sin(float64(i)*0.05) / 32767)
+		pcm[0][i] = v
+		pcm[1][i] = v
+	}
+	return &pcm
+}
+
+func TestDitherer_PreservesLowLevelSignal(t *testing.T) {
+	const n = 1152
+	pcm := quietSine(n)
+	if plain := frame.AppendS16(nil, pcm, n); bytes.Count(plain, []byte{0}) != len(plain) {
+		t.Fatal("truncation did not silence the quiet sine")
+	}
+	for _, m := range []Dither{DitherTPDF, DitherShaped} {
+		q := newDitherer(m)
+		// Average many dithered runs: the mean follows the signal.
+		var mean [n]float64
+		const runs = 200
+		for range runs {
+			b := q.appendS16(nil, pcm, n)
+			for i := range n {
+				mean[i] += float64(int16(uint16(b[4*i])|uint16(b[4*i+1])<<8)) / runs
+			}
+		}
+		var corr, energy float64
+		for i := range n {
+			s := 0.4 * math.Sin(float64(i)*0.05)
+			corr += mean[i] * s
+			energy += s * s
+		}
+		if corr/energy < 0.8 || corr/energy > 1.2 {
+			t.Errorf("%d: dithered output follows the signal with gain %.2f, want about 1", m, corr/energy)
+		}
+	}
+}
+
+func TestWithDither(t *testing.T) {
+	full := decodeAll(t, "example/classic_lame.mp3", nil)
+	for _, m := range []Dither{DitherTPDF, DitherShaped} {
+		got := decodeAll(t, "example/classic_lame.mp3", nil, WithDither(m))
+		if len(got) != len(full) {
+			t.Fatalf("output length = %d, want %d", len(got), len(full))
+		}
+		differ := 0
+		for i := 0; i < len(full); i += 2 {
+			s := int(int16(uint16(full[i]) | uint16(full[i+1])<<8))
+			d := int(int16(uint16(got[i]) | uint16(got[i+1])<<8))
+			if abs(d-s) > 4 {
+				t.Fatalf("%d: sample at byte %d = %d, want about %d", m, i, d, s)
+			}
+			if d != s {
+				differ++
+			}
+		}
+		if differ == 0 {
+			t.Errorf("%d: dithered output equals the truncated output", m)
+		}
+		if again := decodeAll(t, "example/classic_lame.mp3", nil, WithDither(m)); !bytes.Equal(again, got) {
+			t.Errorf("%d: dithered output is not reproducible", m)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package mp3
+
+import (
+	"math"
+
+	"github.com/llehouerou/go-mp3/internal/frame"
+)
+
+// An Equalizer holds linear gains for the 32 subbands of each channel of the
+// synthesis filterbank, indexed by channel (0 for left, 1 for right) and
+// band. Band i covers the frequencies from i*f/64 to (i+1)*f/64, where f is
+// the sample rate: about 689 Hz per band at 44100 Hz.
+type Equalizer [2][32]float64
+
+// NewEqualizer returns a flat Equalizer, with all gains at 1.
+func NewEqualizer() *Equalizer {
+	var e Equalizer
+	for ch := range e {
+		for b := range e[ch] {
+			e[ch][b] = 1
+		}
+	}
+	return &e
+}
+
+// SetBand sets the gain of band in both channels, in dB.
+func (e *Equalizer) SetBand(band int, dB float64) {
+	g := math.Pow(10, dB/20)
+	e[0][band] = g
+	e[1][band] = g
+}
+
+// SetEqualizer sets the gains applied to the subbands in the synthesis
+// filterbank, an essentially free frequency-domain equalizer like the one of
+// mpg123. Mono frames use the gains of channel 0. A nil e disables the
+// equalizer.
+//
+// The gains are copied. Like SetChannelEnabled, the setting applies from the
+// next decoded frame.
+func (d *Decoder) SetEqualizer(e *Equalizer) {
+	if e == nil {
+		d.eq = nil
+		return
+	}
+	var eq frame.Equalizer
+	for ch := range e {
+		for b, g := range e[ch] {
+			eq[ch][b] = float32(g)
+		}
+	}
+	d.eq = &eq
+}
@@ -0,0 +1,57 @@
+package mp3
+
+import (
+	"bytes"
+	"testing"
+)
+
+// brightness returns the energy of the first difference of the left channel
+// relative to its energy, which grows with the spectral centroid.
+func brightness(pcm []byte) float64 {
+	var energy, diff, prev float64
+	for i := 0; i+1 < len(pcm); i += 4 {
+		s := float64(int16(uint16(pcm[i]) | uint16(pcm[i+1])<<8))
+		energy += s * s
+		diff += (s - prev) * (s - prev)
+		prev = s
+	}
+	return diff / energy
+}
+
+func TestSetEqualizer(t *testing.T) {
+	full := decodeAll(t, "example/classic_lame.mp3", nil)
+
+	flat := decodeAll(t, "example/classic_lame.mp3", func(d *Decoder) {
+		d.SetEqualizer(NewEqualizer())
+	})
+	if !bytes.Equal(flat, full) {
+		t.Error("output with a flat equalizer differs from default output")
+	}
+
+	muted := decodeAll(t, "example/classic_lame.mp3", func(d *Decoder) {
+		d.SetEqualizer(&Equalizer{})
+	})
+	if bytes.Count(muted, []byte{0}) != len(muted) {
+		t.Error("output with all bands at 0 is not silent")
+	}
+
+	// Cut everything above about 1.4 kHz.
+	low := decodeAll(t, "example/classic_lame.mp3", func(d *Decoder) {
+		eq := NewEqualizer()
+		for b := 2; b < 32; b++ {
+			eq.SetBand(b, -60)
+		}
+		d.SetEqualizer(eq)
+	})
+	if got, want := brightness(low), brightness(full); got >= want/4 {
+		t.Errorf("brightness with treble cut = %g, want well below %g", got, want)
+	}
+
+	off := decodeAll(t, "example/classic_lame.mp3", func(d *Decoder) {
+		d.SetEqualizer(&Equalizer{})
+		d.SetEqualizer(nil)
+	})
+	if !bytes.Equal(off, full) {
+		t.Error("output with the equalizer disabled differs from default output")
+	}
+}
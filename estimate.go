@@ -0,0 +1,94 @@
+package mp3
+
+import (
+	"time"
+
+	"github.com/llehouerou/go-mp3/lameinfo"
+)
+
+// WithContentLength gives the size in bytes of the whole input, such as the
+// Content-Length of an HTTP response. For sources that are not io.Seeker it
+// lets Duration estimate the duration from the average frame size read so
+// far. It is ignored for seekable sources, whose length is known exactly.
+func WithContentLength(n int64) Option {
+	return func(c *config) {
+		c.contentLength = max(n, 0)
+	}
+}
+
+// durationEstimate estimates the duration of a stream whose length is not
+// known because the source is not io.Seeker.
+type durationEstimate struct {
+	// contentLength is the size of the input given by WithContentLength, or 0.
+	contentLength int64
+
+	// audioStart is the offset of the first frame.
+	audioStart int64
+
+	// xingFrames is the frame count of the Xing header, or 0.
+	xingFrames int64
+
+	// frames and frameBytes count the frames read so far and their size,
+	// and pcm the decoded bytes of these frames.
+	frames     int64
+	frameBytes int64
+	pcm        int64
+
+	// pcmPerFrame is the number of decoded bytes of the first frame.
+	pcmPerFrame int64
+
+	// padding is the number of bytes trimmed from the end by
+	// WithPaddingTrim.
+	padding int64
+}
+
+// addFrame records a frame of size bytes that decodes to pcm bytes.
+func (e *durationEstimate) addFrame(size, pcm int64) {
+	if e.frames == 0 {
+		e.pcmPerFrame = pcm
+	}
+	e.frames++
+	e.frameBytes += size
+	e.pcm += pcm
+}
+
+// length returns the estimated number of decoded bytes, or invalidLength.
+func (e *durationEstimate) length() int64 {
+	switch {
+	case e.xingFrames > 0:
+		// The Xing header frame itself is decoded as a frame of silence.
+		return (e.xingFrames+1)*e.pcmPerFrame - e.padding
+	case e.contentLength > e.audioStart && e.frameBytes > 0:
+		// Frames can differ in PCM size, e.g. when MPEG versions are mixed,
+		// so the frames read give the average.
+		frames := (e.contentLength - e.audioStart) * e.frames / e.frameBytes
+		return frames * e.pcm / e.frames
+	}
+	return invalidLength
+}
+
+// peekXingHeader reads the Xing header of the first frame, if any, into
+// d.xing without consuming the source.
+func (d *Decoder) peekXingHeader() {
+	buf := make([]byte, maxFrameBytes)
+	n, _ := d.source.ReadFull(buf)
+	d.source.Unread(buf[:n])
+	info, err := lameinfo.Parse(buf[:n])
+	if err != nil {
+		return
+	}
+	d.xing = info
+	if info.HasFrameCount() {
+		d.estimate.xingFrames = int64(info.FrameCount)
+	}
+}
+
+// estimatedDuration returns the duration estimated for a source that is not
+// io.Seeker, or -1.
+func (d *Decoder) estimatedDuration() time.Duration {
+	l := d.estimate.length()
+	if l == invalidLength {
+		return -1
+	}
+	return d.bytesToDuration(l + d.priming)
+}
@@ -0,0 +1,133 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+// nonSeekable hides the io.Seeker implementation of a reader.
+type nonSeekable struct {
+	io.Reader
+}
+
+func TestDuration_EstimateFromXingHeader(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{Frames: 200, Xing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seekable, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDecoder(nonSeekable{bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d.Duration(), seekable.Duration(); got != want {
+		t.Errorf("Duration() = %v, want %v", got, want)
+	}
+	if d.Length() != -1 {
+		t.Errorf("Length() = %d, want -1", d.Length())
+	}
+}
+
+func TestDuration_EstimateFromContentLength(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{
+		Frames: 200,
+		Tags:   map[string]string{"TIT2": "Title"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := 200 * 1152 * time.Second / 44100
+
+	d, err := NewDecoder(nonSeekable{bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.Duration(); got != -1 {
+		t.Errorf("Duration() without content length = %v, want -1", got)
+	}
+
+	d, err = NewDecoder(nonSeekable{bytes.NewReader(data)}, WithContentLength(int64(len(data))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The estimate is refined as frames are read, since frame sizes vary
+	// with padding.
+	first := d.Duration()
+	if diff := (first - want).Abs(); diff > want/100 {
+		t.Errorf("Duration() after the first frame = %v, want about %v", first, want)
+	}
+	if _, err := io.Copy(io.Discard, d); err != nil {
+		t.Fatal(err)
+	}
+	if diff := (d.Duration() - want).Abs(); diff > 30*time.Millisecond {
+		t.Errorf("Duration() after decoding = %v, want about %v", d.Duration(), want)
+	}
+	if d.Remaining() < 0 {
+		t.Errorf("Remaining() = %v, want non-negative", d.Remaining())
+	}
+}
+
+func TestDuration_EstimateRealFile(t *testing.T) {
+	data, err := os.ReadFile("example/classic.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	seekable, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDecoder(nonSeekable{bytes.NewReader(data)}, WithContentLength(int64(len(data))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := seekable.Duration()
+	if diff := (d.Duration() - want).Abs(); diff > want/50 {
+		t.Errorf("Duration() = %v, want about %v", d.Duration(), want)
+	}
+}
+
+func TestLength_MixedMPEGVersions(t *testing.T) {
+	mpeg1, err := testsupport.Generate(testsupport.Options{Frames: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mpeg2, err := testsupport.Generate(testsupport.Options{Frames: 30, SampleRate: 22050, Bitrate: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := slices.Concat(mpeg1, mpeg2)
+	want := int64(10*1152*4 + 30*576*4)
+
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if d.Length() != want || d.BytesPerFrame() != 1152*4 {
+		t.Errorf("Length = %d, BytesPerFrame = %d, want %d and %d", d.Length(), d.BytesPerFrame(), want, 1152*4)
+	}
+	pcm, err := io.ReadAll(d)
+	if err != nil || int64(len(pcm)) != want {
+		t.Errorf("ReadAll = %d bytes, %v, want %d", len(pcm), err, want)
+	}
+
+	// The estimate of a non-seekable source sums the PCM of the frames read.
+	d, err = NewDecoder(&nonSeekableReader{bytes.NewReader(data)}, WithContentLength(int64(len(data))))
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if _, err := io.Copy(io.Discard, d); err != nil {
+		t.Fatalf("decoding failed: %v", err)
+	}
+	if got := d.estimate.length(); got != want {
+		t.Errorf("estimated length = %d, want %d", got, want)
+	}
+}
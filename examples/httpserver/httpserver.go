@@ -0,0 +1,270 @@
+// Package httpserver serves MP3 files decoded to WAV or raw PCM over HTTP.
+//
+// It is both a reference integration of the mp3 package with net/http and a
+// reusable component. A Handler decodes files on the fly and honours Range
+// requests by seeking the decoder through its frame index, so that clients
+// can seek in the decoded audio without the server decoding everything before
+// the requested range:
+//
+//	http.Handle("/audio/", http.StripPrefix("/audio/", httpserver.New(os.DirFS("music"))))
+//
+// A request for /audio/song.mp3 then returns the song as WAV, and
+// /audio/song.mp3?format=pcm as raw PCM.
+package httpserver
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	"math"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/llehouerou/go-mp3"
+)
+
+// A Format is the format of the decoded audio served by a Handler.
+type Format int
+
+const (
+	// FormatWAV is a WAV file of 16-bit little-endian stereo PCM.
+	FormatWAV Format = iota
+
+	// FormatPCM is raw 16-bit little-endian stereo PCM, as returned by
+	// mp3.Decoder.Read.
+	FormatPCM
+)
+
+// wavHeaderSize is the size of the WAV header written before the PCM.
+const wavHeaderSize = 44
+
+// ErrTooLong is returned by NewStream when the decoded audio does not fit in a
+// WAV file.
+var ErrTooLong = errors.New("httpserver: decoded audio too long for WAV")
+
+// ErrNotSeekable is returned by NewStream when the length of the decoded
+// audio is not known because the decoder source is not an io.Seeker.
+var ErrNotSeekable = errors.New("httpserver: decoder source is not seekable")
+
+// A Stream is the decoded audio of a Decoder in a Format. It implements
+// io.ReadSeeker, and its Seek is backed by the frame index of the decoder,
+// which makes it suitable for http.ServeContent.
+//
+// A Stream is not safe for concurrent use.
+type Stream struct {
+	d      *mp3.Decoder
+	header []byte
+	size   int64
+
+	// pos is the position in the stream and dpos the position of the
+	// decoder in the PCM, or -1 when the decoder must be seeked before the
+	// next read.
+	pos  int64
+	dpos int64
+}
+
+// NewStream returns a Stream over the decoded audio of d, which must have
+// been created from an io.Seeker.
+func NewStream(d *mp3.Decoder, format Format) (*Stream, error) {
+	length := d.Length()
+	if length < 0 {
+		return nil, ErrNotSeekable
+	}
+	s := &Stream{d: d, dpos: -1}
+	if format == FormatWAV {
+		if length > math.MaxUint32-(wavHeaderSize-8) {
+			return nil, ErrTooLong
+		}
+		s.header = wavHeader(d.SampleRate(), uint32(length))
+	}
+	s.size = int64(len(s.header)) + length
+	return s, nil
+}
+
+// wavHeader returns the header of a WAV file of 16-bit stereo PCM.
+func wavHeader(sampleRate int, dataSize uint32) []byte {
+	const channels, bytesPerSample = 2, 2
+	b := make([]byte, wavHeaderSize)
+	copy(b[0:], "RIFF")
+	binary.LittleEndian.PutUint32(b[4:], wavHeaderSize-8+dataSize)
+	copy(b[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(b[16:], 16)
+	binary.LittleEndian.PutUint16(b[20:], 1) // PCM
+	binary.LittleEndian.PutUint16(b[22:], channels)
+	binary.LittleEndian.PutUint32(b[24:], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(b[28:], uint32(sampleRate*channels*bytesPerSample))
+	binary.LittleEndian.PutUint16(b[32:], channels*bytesPerSample)
+	binary.LittleEndian.PutUint16(b[34:], 8*bytesPerSample)
+	copy(b[36:], "data")
+	binary.LittleEndian.PutUint32(b[40:], dataSize)
+	return b
+}
+
+// Size returns the size of the stream in bytes.
+func (s *Stream) Size() int64 {
+	return s.size
+}
+
+// Read implements io.Reader.
+func (s *Stream) Read(p []byte) (int, error) {
+	if s.pos >= s.size {
+		return 0, io.EOF
+	}
+	if h := int64(len(s.header)); s.pos < h {
+		n := copy(p, s.header[s.pos:])
+		s.pos += int64(n)
+		return n, nil
+	}
+	pcmPos := s.pos - int64(len(s.header))
+	if s.dpos != pcmPos {
+		// The decoder seeks to the start of the sample holding pcmPos, so
+		// the bytes of the sample before it are skipped.
+		start, err := s.d.Seek(pcmPos, io.SeekStart)
+		if err == nil {
+			_, err = io.CopyN(io.Discard, s.d, pcmPos-start)
+		}
+		if err != nil {
+			s.dpos = -1
+			return 0, err
+		}
+		s.dpos = pcmPos
+	}
+	p = p[:min(int64(len(p)), s.size-s.pos)]
+	n, err := s.d.Read(p)
+	s.pos += int64(n)
+	s.dpos += int64(n)
+	if errors.Is(err, io.EOF) && s.pos < s.size {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// Seek implements io.Seeker. The decoder is only seeked by the next Read.
+func (s *Stream) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = s.pos + offset
+	case io.SeekEnd:
+		pos = s.size + offset
+	default:
+		return 0, errors.New("httpserver: invalid whence")
+	}
+	if pos < 0 {
+		return 0, errors.New("httpserver: negative position")
+	}
+	s.pos = pos
+	return pos, nil
+}
+
+// An Option configures a Handler created by New.
+type Option func(*Handler)
+
+// WithFormat sets the format served when the request does not choose one.
+// The default is FormatWAV.
+func WithFormat(f Format) Option {
+	return func(h *Handler) {
+		h.format = f
+	}
+}
+
+// WithDecoderOptions sets the options of the decoders created for the
+// requests.
+func WithDecoderOptions(opts ...mp3.Option) Option {
+	return func(h *Handler) {
+		h.decoderOpts = opts
+	}
+}
+
+// A Handler serves the MP3 files of a file system decoded to WAV or raw PCM.
+//
+// The request path names the file, which must have the .mp3 extension. The
+// format query parameter chooses the format: "wav" or "pcm". GET and HEAD
+// requests are supported, with Range, If-Modified-Since and the other
+// conditional headers handled by http.ServeContent. The files must implement
+// io.Seeker, as those of os.DirFS and embed.FS do.
+type Handler struct {
+	fsys        fs.FS
+	format      Format
+	decoderOpts []mp3.Option
+}
+
+// New returns a Handler serving the files of fsys.
+func New(fsys fs.FS, opts ...Option) *Handler {
+	h := &Handler{fsys: fsys}
+	for _, o := range opts {
+		o(h)
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(path.Clean("/"+req.URL.Path), "/")
+	if !fs.ValidPath(name) || !strings.EqualFold(path.Ext(name), ".mp3") {
+		http.NotFound(w, req)
+		return
+	}
+	format := h.format
+	switch req.URL.Query().Get("format") {
+	case "":
+	case "wav":
+		format = FormatWAV
+	case "pcm":
+		format = FormatPCM
+	default:
+		http.Error(w, "unknown format", http.StatusBadRequest)
+		return
+	}
+
+	f, err := h.fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		http.NotFound(w, req)
+		return
+	}
+	if err != nil {
+		http.Error(w, "cannot open file", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+	var modTime time.Time
+	if fi, err := f.Stat(); err == nil {
+		if fi.IsDir() {
+			http.NotFound(w, req)
+			return
+		}
+		modTime = fi.ModTime()
+	}
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		http.Error(w, "file is not seekable", http.StatusInternalServerError)
+		return
+	}
+	d, err := mp3.NewDecoder(rs, h.decoderOpts...)
+	if err != nil {
+		http.Error(w, "cannot decode file", http.StatusUnprocessableEntity)
+		return
+	}
+	s, err := NewStream(d, format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	if format == FormatWAV {
+		w.Header().Set("Content-Type", "audio/wav")
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+	http.ServeContent(w, req, name, modTime, s)
+}
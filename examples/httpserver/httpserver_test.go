@@ -0,0 +1,179 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/llehouerou/go-mp3"
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+// newServer serves the sample files of the example directory.
+func newServer(t *testing.T, opts ...Option) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(New(os.DirFS("../../example"), opts...))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// decodeAll decodes the sample file name.
+func decodeAll(t *testing.T, name string) []byte {
+	t.Helper()
+	f, err := os.Open("../../example/" + name)
+	if err != nil {
+		t.Fatalf("failed to open test file: %v", err)
+	}
+	defer f.Close()
+	d, err := mp3.NewDecoder(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pcm
+}
+
+func get(t *testing.T, url, rangeHeader string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, body
+}
+
+func TestHandler_WAV(t *testing.T) {
+	srv := newServer(t)
+	pcm := decodeAll(t, "classic_lame.mp3")
+
+	resp, body := get(t, srv.URL+"/classic_lame.mp3", "")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "audio/wav" {
+		t.Fatalf("status %d, Content-Type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if len(body) != wavHeaderSize+len(pcm) || string(body[:4]) != "RIFF" || string(body[8:12]) != "WAVE" {
+		t.Fatalf("served %d bytes, want a WAV file of %d", len(body), wavHeaderSize+len(pcm))
+	}
+	if rate := binary.LittleEndian.Uint32(body[24:]); rate != 44100 {
+		t.Errorf("sample rate = %d, want 44100", rate)
+	}
+	if size := binary.LittleEndian.Uint32(body[40:]); int(size) != len(pcm) {
+		t.Errorf("data size = %d, want %d", size, len(pcm))
+	}
+	if !bytes.Equal(body[wavHeaderSize:], pcm) {
+		t.Error("served PCM differs from the decoded PCM")
+	}
+
+	// A range across the header and the PCM.
+	resp, body = get(t, srv.URL+"/classic_lame.mp3", "bytes=40-99")
+	if resp.StatusCode != http.StatusPartialContent {
+		t.Fatalf("status %d, want 206", resp.StatusCode)
+	}
+	if !bytes.Equal(body[:4], binary.LittleEndian.AppendUint32(nil, uint32(len(pcm)))) ||
+		!bytes.Equal(body[4:], pcm[:56]) {
+		t.Error("range across the header differs from the WAV file")
+	}
+}
+
+func TestHandler_PCMRanges(t *testing.T) {
+	srv := newServer(t)
+	pcm := decodeAll(t, "classic_lame.mp3")
+
+	for _, r := range []struct {
+		header     string
+		start, end int
+	}{
+		{"bytes=0-3", 0, 4},
+		{"bytes=500000-600003", 500000, 600004},
+		{"bytes=1000001-1000100", 1000001, 1000101},
+		{"bytes=-4608", len(pcm) - 4608, len(pcm)},
+	} {
+		resp, body := get(t, srv.URL+"/classic_lame.mp3?format=pcm", r.header)
+		if resp.StatusCode != http.StatusPartialContent {
+			t.Fatalf("%s: status %d, want 206", r.header, resp.StatusCode)
+		}
+		if !bytes.Equal(body, pcm[r.start:r.end]) {
+			t.Errorf("%s: served bytes differ from the decoded PCM", r.header)
+		}
+	}
+}
+
+func TestHandler_Errors(t *testing.T) {
+	srv := newServer(t)
+	for _, tt := range []struct {
+		method, path string
+		status       int
+	}{
+		{http.MethodGet, "/missing.mp3", http.StatusNotFound},
+		{http.MethodGet, "/license.md", http.StatusNotFound},
+		{http.MethodGet, "/../decode.go", http.StatusNotFound},
+		{http.MethodGet, "/classic_lame.mp3?format=flac", http.StatusBadRequest},
+		{http.MethodPost, "/classic_lame.mp3", http.StatusMethodNotAllowed},
+	} {
+		req, err := http.NewRequest(tt.method, srv.URL+tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s %s: status %d, want %d", tt.method, tt.path, resp.StatusCode, tt.status)
+		}
+	}
+}
+
+func TestHandler_DefaultFormat(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{Frames: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{"silence.mp3": {Data: data}}
+	srv := httptest.NewServer(New(fsys, WithFormat(FormatPCM)))
+	defer srv.Close()
+
+	resp, body := get(t, srv.URL+"/silence.mp3", "")
+	if resp.StatusCode != http.StatusOK || len(body) != 4*1152*4 {
+		t.Errorf("status %d, %d bytes, want 200 and %d bytes of PCM", resp.StatusCode, len(body), 4*1152*4)
+	}
+	resp, body = get(t, srv.URL+"/silence.mp3?format=wav", "")
+	if resp.StatusCode != http.StatusOK || len(body) != wavHeaderSize+4*1152*4 {
+		t.Errorf("status %d, %d bytes, want a WAV file", resp.StatusCode, len(body))
+	}
+}
+
+func TestNewStream_NotSeekable(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{Frames: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := mp3.NewDecoder(struct{ io.Reader }{bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewStream(d, FormatWAV); !errors.Is(err, ErrNotSeekable) {
+		t.Errorf("NewStream error = %v, want ErrNotSeekable", err)
+	}
+}
@@ -0,0 +1,75 @@
+package mp3
+
+import (
+	"io"
+	"io/fs"
+	"os"
+)
+
+// NewDecoderFromFile opens the file path and returns a decoder of it. The
+// file is closed by Close, or when NewDecoderFromFile fails.
+func NewDecoderFromFile(path string, opts ...Option) (*Decoder, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return newFileDecoder(f, f, opts)
+}
+
+// NewDecoderFS opens the file name of fsys and returns a decoder of it. The
+// file is closed by Close, or when NewDecoderFS fails.
+//
+// The decoder can seek if the file implements io.Seeker, or io.ReaderAt
+// along with a size reported by Stat, such as the files of an embed.FS.
+func NewDecoderFS(fsys fs.FS, name string, opts ...Option) (*Decoder, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	r, err := seekableFile(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return newFileDecoder(r, f, opts)
+}
+
+// seekableFile returns a reader of f implementing io.Seeker if possible.
+func seekableFile(f fs.File) (io.Reader, error) {
+	if _, ok := f.(io.Seeker); ok {
+		return f, nil
+	}
+	ra, ok := f.(io.ReaderAt)
+	if !ok {
+		return f, nil
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return io.NewSectionReader(ra, 0, info.Size()), nil
+}
+
+// newFileDecoder returns a decoder of r, which reads the file c. The file is
+// closed if the decoder cannot be created.
+func newFileDecoder(r io.Reader, c io.Closer, opts []Option) (*Decoder, error) {
+	d, err := NewDecoder(r, opts...)
+	if err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	d.closer = c
+	return d, nil
+}
+
+// Close closes the file opened by NewDecoderFromFile or NewDecoderFS. It
+// does nothing for decoders of other sources, whose readers are closed by
+// their owners.
+func (d *Decoder) Close() error {
+	c := d.closer
+	if c == nil {
+		return nil
+	}
+	d.closer = nil
+	return c.Close()
+}
@@ -0,0 +1,123 @@
+package mp3
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+//go:embed example/classic_lame.mp3
+var embedded embed.FS
+
+func TestNewDecoderFromFile(t *testing.T) {
+	d, err := NewDecoderFromFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Length() != 1774080 {
+		t.Errorf("Length() = %d, want 1774080", d.Length())
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.source.reader.(*os.File).Stat(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("file not closed: Stat() = %v", err)
+	}
+	if err := d.Close(); err != nil {
+		t.Errorf("second Close() = %v", err)
+	}
+
+	if _, err := NewDecoderFromFile("example/missing.mp3"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("NewDecoderFromFile() of a missing file = %v", err)
+	}
+}
+
+// readerAtFile hides the Seek method of a file.
+type readerAtFile struct {
+	f interface {
+		fs.File
+		io.ReaderAt
+	}
+	closed bool
+}
+
+func (f *readerAtFile) Stat() (fs.FileInfo, error)              { return f.f.Stat() }
+func (f *readerAtFile) Read(p []byte) (int, error)              { return f.f.Read(p) }
+func (f *readerAtFile) ReadAt(p []byte, off int64) (int, error) { return f.f.ReadAt(p, off) }
+func (f *readerAtFile) Close() error {
+	f.closed = true
+	return f.f.Close()
+}
+
+// readerAtFS opens the files of an embed.FS as readerAtFiles.
+type readerAtFS struct {
+	fsys  embed.FS
+	files []*readerAtFile
+}
+
+func (fsys *readerAtFS) Open(name string) (fs.File, error) {
+	f, err := fsys.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	rf := &readerAtFile{f: f.(interface {
+		fs.File
+		io.ReaderAt
+	})}
+	fsys.files = append(fsys.files, rf)
+	return rf, nil
+}
+
+func TestNewDecoderFS(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, want := decodeFresh(t, data)
+
+	ra := &readerAtFS{fsys: embedded}
+	for _, fsys := range []fs.FS{
+		embedded,
+		ra,
+		fstest.MapFS{"example/classic_lame.mp3": {Data: data}},
+	} {
+		d, err := NewDecoderFS(fsys, "example/classic_lame.mp3")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.SeekToSample(44100); err != nil {
+			t.Errorf("%T: %v", fsys, err)
+			continue
+		}
+		got, err := io.ReadAll(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want[44100*4:]) {
+			t.Errorf("%T: PCM differs", fsys)
+		}
+		if err := d.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(ra.files) != 1 || !ra.files[0].closed {
+		t.Error("Close() did not close the file")
+	}
+
+	// The file is closed when the decoder cannot be created.
+	ra = &readerAtFS{fsys: embedded}
+	if _, err := NewDecoderFS(ra, "example/classic_lame.mp3", WithLimits(Limits{MaxFrames: 1})); err == nil {
+		t.Error("NewDecoderFS() exceeding MaxFrames succeeded")
+	}
+	if len(ra.files) != 1 || !ra.files[0].closed {
+		t.Error("file not closed after NewDecoderFS() failed")
+	}
+	if _, err := NewDecoderFS(ra, "missing.mp3"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("NewDecoderFS() of a missing file = %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package mp3
+
+import (
+	"errors"
+	"math"
+)
+
+// SeekToFraction seeks to the fraction f of the stream, from 0 for its
+// start to 1 for its end, as set by a scrub bar. It is the inverse of
+// Progress. The position comes from the frame index, so it is exact even on
+// VBR streams, unlike the approximation that the Xing seek table gives.
+//
+// f is clamped to [0, 1]. SeekToFraction returns an error if seeking is not
+// supported or f is NaN.
+func (d *Decoder) SeekToFraction(f float64) error {
+	if d.length == invalidLength {
+		return errors.New("mp3: seek not supported on non-seekable source")
+	}
+	if math.IsNaN(f) {
+		return errors.New("mp3: seek fraction is NaN")
+	}
+	f = min(max(f, 0), 1)
+	return d.SeekToSample(int64(math.Round(f * float64(d.SampleCount()))))
+}
@@ -0,0 +1,50 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"os"
+	"testing"
+)
+
+func TestSeekToFraction(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	for _, f := range []float64{0, 0.25, 0.5, 0.999, 1} {
+		if err := d.SeekToFraction(f); err != nil {
+			t.Fatalf("SeekToFraction(%v) failed: %v", f, err)
+		}
+		if got := d.Progress(); math.Abs(got-f) > 1e-5 {
+			t.Errorf("SeekToFraction(%v): Progress = %v", f, got)
+		}
+	}
+
+	// Out of range fractions are clamped.
+	if err := d.SeekToFraction(-1); err != nil || d.SamplePosition() != 0 {
+		t.Errorf("SeekToFraction(-1) = %v at sample %d, want the start", err, d.SamplePosition())
+	}
+	if err := d.SeekToFraction(2); err != nil || d.SamplePosition() != d.SampleCount() {
+		t.Errorf("SeekToFraction(2) = %v at sample %d, want the end", err, d.SamplePosition())
+	}
+	if err := d.SeekToFraction(math.NaN()); err == nil {
+		t.Error("SeekToFraction(NaN) succeeded")
+	}
+
+	d, err = NewDecoder(&nonSeekableReader{bytes.NewReader(data)})
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if err := d.SeekToFraction(0.5); err == nil {
+		t.Error("SeekToFraction succeeded on a non-seekable source")
+	}
+	if _, err := io.Copy(io.Discard, d); err != nil {
+		t.Errorf("decoding failed after the refused seek: %v", err)
+	}
+}
@@ -0,0 +1,286 @@
+// Package frameheader parses MPEG audio frame headers, the 4 bytes that
+// start every frame of an MP3 stream.
+//
+// All MPEG versions (1, 2 and 2.5) and layers (I, II and III) are
+// described, so that tools can inspect any MPEG audio stream without a
+// decoder, although the mp3 package only decodes MPEG-1 and MPEG-2 Layer III.
+package frameheader
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// Size is the size of a frame header in bytes.
+const Size = 4
+
+// ErrInvalidHeader is returned by Parse for bytes that are not a valid
+// frame header.
+var ErrInvalidHeader = errors.New("frameheader: invalid frame header")
+
+// Version is the MPEG version of a frame, with the value of its header bits.
+type Version int
+
+const (
+	Version2_5      Version = 0
+	VersionReserved Version = 1
+	Version2        Version = 2
+	Version1        Version = 3
+)
+
+// String returns the version as "1", "2" or "2.5".
+func (v Version) String() string {
+	switch v {
+	case Version1:
+		return "1"
+	case Version2:
+		return "2"
+	case Version2_5:
+		return "2.5"
+	}
+	return "reserved"
+}
+
+// Mode is the channel mode of a frame.
+type Mode int
+
+const (
+	ModeStereo        Mode = 0
+	ModeJointStereo   Mode = 1
+	ModeDualChannel   Mode = 2
+	ModeSingleChannel Mode = 3
+)
+
+// String returns the name of the mode, such as "joint stereo".
+func (m Mode) String() string {
+	switch m {
+	case ModeStereo:
+		return "stereo"
+	case ModeJointStereo:
+		return "joint stereo"
+	case ModeDualChannel:
+		return "dual channel"
+	}
+	return "single channel"
+}
+
+// Emphasis is the de-emphasis the decoded audio needs, as signalled by the
+// encoder.
+type Emphasis int
+
+const (
+	EmphasisNone     Emphasis = 0
+	Emphasis50_15    Emphasis = 1 // 50/15 µs
+	EmphasisReserved Emphasis = 2
+	EmphasisCCITT    Emphasis = 3 // CCITT J.17
+)
+
+// String returns the name of the emphasis, such as "50/15 µs".
+func (e Emphasis) String() string {
+	switch e {
+	case EmphasisNone:
+		return "none"
+	case Emphasis50_15:
+		return "50/15 µs"
+	case EmphasisCCITT:
+		return "CCITT J.17"
+	}
+	return "reserved"
+}
+
+// A Header is a frame header, with the first byte in the most significant
+// bits.
+type Header uint32
+
+// Parse returns the header at the start of b. It returns ErrInvalidHeader if
+// b is shorter than Size or the header is not valid.
+func Parse(b []byte) (Header, error) {
+	if len(b) < Size {
+		return 0, ErrInvalidHeader
+	}
+	h := Header(binary.BigEndian.Uint32(b))
+	if !h.Valid() {
+		return 0, ErrInvalidHeader
+	}
+	return h, nil
+}
+
+// Valid reports whether h has the sync word and no reserved or forbidden
+// field values.
+func (h Header) Valid() bool {
+	const sync = 0xffe00000
+	return h&sync == sync &&
+		h.Version() != VersionReserved &&
+		h.Layer() != 0 &&
+		h.bitrateIndex() != 15 &&
+		h.sampleRateIndex() != 3 &&
+		h.Emphasis() != EmphasisReserved
+}
+
+// Version returns the MPEG version.
+func (h Header) Version() Version {
+	return Version(h >> 19 & 0x3)
+}
+
+// Layer returns the layer: 1, 2 or 3, or 0 if reserved.
+func (h Header) Layer() int {
+	return int(4-(h>>17&0x3)) % 4
+}
+
+// Protected reports whether the header is followed by a 16-bit CRC.
+func (h Header) Protected() bool {
+	return h>>16&0x1 == 0
+}
+
+func (h Header) bitrateIndex() int {
+	return int(h >> 12 & 0xf)
+}
+
+func (h Header) sampleRateIndex() int {
+	return int(h >> 10 & 0x3)
+}
+
+// Bitrates in kbps by MPEG-1 or later version, layer and index.
+var bitrates = [2][3][15]int{
+	{ // MPEG-1
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	},
+	{ // MPEG-2 and 2.5
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	},
+}
+
+// Bitrate returns the bitrate in bits per second, or 0 for free format
+// frames and invalid headers.
+func (h Header) Bitrate() int {
+	if !h.Valid() {
+		return 0
+	}
+	v := 0
+	if h.Version() != Version1 {
+		v = 1
+	}
+	return bitrates[v][h.Layer()-1][h.bitrateIndex()] * 1000
+}
+
+// Sample rates in Hz by version and index.
+var sampleRates = [4][3]int{
+	Version2_5: {11025, 12000, 8000},
+	Version2:   {22050, 24000, 16000},
+	Version1:   {44100, 48000, 32000},
+}
+
+// SampleRate returns the sample rate in Hz, or 0 for invalid headers.
+func (h Header) SampleRate() int {
+	if !h.Valid() {
+		return 0
+	}
+	return sampleRates[h.Version()][h.sampleRateIndex()]
+}
+
+// Padding reports whether the frame has a padding slot.
+func (h Header) Padding() bool {
+	return h>>9&0x1 == 1
+}
+
+// Mode returns the channel mode.
+func (h Header) Mode() Mode {
+	return Mode(h >> 6 & 0x3)
+}
+
+// ModeExtension returns the mode extension bits. In Layer III joint stereo
+// frames, bit 0 enables intensity stereo and bit 1 middle/side stereo.
+func (h Header) ModeExtension() int {
+	return int(h >> 4 & 0x3)
+}
+
+// Copyright reports whether the copyright bit is set.
+func (h Header) Copyright() bool {
+	return h>>3&0x1 == 1
+}
+
+// Original reports whether the original bit is set, marking the original
+// media rather than a copy.
+func (h Header) Original() bool {
+	return h>>2&0x1 == 1
+}
+
+// Emphasis returns the emphasis.
+func (h Header) Emphasis() Emphasis {
+	return Emphasis(h & 0x3)
+}
+
+// Channels returns the number of channels: 1 or 2.
+func (h Header) Channels() int {
+	if h.Mode() == ModeSingleChannel {
+		return 1
+	}
+	return 2
+}
+
+// SamplesPerFrame returns the number of samples per channel in the frame:
+// 384 for Layer I, 1152 for Layer II and MPEG-1 Layer III, and 576 for
+// MPEG-2 and 2.5 Layer III.
+func (h Header) SamplesPerFrame() int {
+	switch {
+	case h.Layer() == 1:
+		return 384
+	case h.Layer() == 3 && h.Version() != Version1:
+		return 576
+	}
+	return 1152
+}
+
+// FrameSize returns the size of the frame in bytes, including the header.
+// It returns 0 for free format frames, whose size is not given by the
+// header, and for invalid headers.
+func (h Header) FrameSize() int {
+	bitrate := h.Bitrate()
+	if bitrate == 0 {
+		return 0
+	}
+	rate := h.SampleRate()
+	if h.Layer() == 1 {
+		// Layer I slots are 4 bytes long.
+		size := 12 * bitrate / rate
+		if h.Padding() {
+			size++
+		}
+		return size * 4
+	}
+	size := h.SamplesPerFrame() / 8 * bitrate / rate
+	if h.Padding() {
+		size++
+	}
+	return size
+}
+
+// Duration returns the duration of the frame, or 0 for invalid headers.
+func (h Header) Duration() time.Duration {
+	rate := h.SampleRate()
+	if rate == 0 {
+		return 0
+	}
+	return time.Duration(h.SamplesPerFrame()) * time.Second / time.Duration(rate)
+}
+
+// SideInfoSize returns the size in bytes of the Layer III side information
+// that follows the header (and CRC) of a frame of this version and mode.
+// Xing and Info headers are stored right after it.
+func (h Header) SideInfoSize() int {
+	mono := h.Mode() == ModeSingleChannel
+	switch {
+	case h.Version() == Version1 && mono:
+		return 17
+	case h.Version() == Version1:
+		return 32
+	case mono:
+		return 9
+	}
+	return 17
+}
@@ -0,0 +1,157 @@
+package frameheader_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/llehouerou/go-mp3/frameheader"
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+func TestParse_LayerIII(t *testing.T) {
+	tests := []struct {
+		sampleRate, bitrate int
+		mono, padding       bool
+		version             frameheader.Version
+		samples             int
+	}{
+		{44100, 128, false, true, frameheader.Version1, 1152},
+		{32000, 320, true, false, frameheader.Version1, 1152},
+		{24000, 160, false, false, frameheader.Version2, 576},
+		{8000, 8, true, true, frameheader.Version2_5, 576},
+		{11025, 64, false, false, frameheader.Version2_5, 576},
+	}
+	for _, tt := range tests {
+		frame, err := testsupport.Frame(tt.sampleRate, tt.bitrate, tt.mono, tt.padding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := frameheader.Parse(frame)
+		if err != nil {
+			t.Fatalf("Parse(%d Hz, %d kbps) failed: %v", tt.sampleRate, tt.bitrate, err)
+		}
+		if h.Version() != tt.version || h.Layer() != 3 {
+			t.Errorf("%d Hz: MPEG-%v Layer %d, want MPEG-%v Layer 3", tt.sampleRate, h.Version(), h.Layer(), tt.version)
+		}
+		if h.SampleRate() != tt.sampleRate || h.Bitrate() != tt.bitrate*1000 {
+			t.Errorf("%d Hz: SampleRate %d, Bitrate %d", tt.sampleRate, h.SampleRate(), h.Bitrate())
+		}
+		if h.FrameSize() != len(frame) {
+			t.Errorf("%d Hz, %d kbps: FrameSize() = %d, want %d", tt.sampleRate, tt.bitrate, h.FrameSize(), len(frame))
+		}
+		if h.SamplesPerFrame() != tt.samples {
+			t.Errorf("%d Hz: SamplesPerFrame() = %d, want %d", tt.sampleRate, h.SamplesPerFrame(), tt.samples)
+		}
+		wantChannels := 2
+		if tt.mono {
+			wantChannels = 1
+		}
+		if h.Channels() != wantChannels || h.Padding() != tt.padding || h.Protected() {
+			t.Errorf("%d Hz: Channels %d, Padding %v, Protected %v", tt.sampleRate, h.Channels(), h.Padding(), h.Protected())
+		}
+		want := time.Duration(tt.samples) * time.Second / time.Duration(tt.sampleRate)
+		if h.Duration() != want {
+			t.Errorf("%d Hz: Duration() = %v, want %v", tt.sampleRate, h.Duration(), want)
+		}
+	}
+}
+
+func TestParse_OtherLayers(t *testing.T) {
+	tests := []struct {
+		header  frameheader.Header
+		layer   int
+		bitrate int
+		size    int
+		samples int
+	}{
+		// MPEG-1 Layer II, 128 kbps, 44100 Hz, unpadded.
+		{0xfffd8000, 2, 128000, 417, 1152},
+		// MPEG-1 Layer I, 448 kbps, 48000 Hz, padded.
+		{0xffffe600, 1, 448000, 452, 384},
+		// MPEG-2 Layer I, 32 kbps, 22050 Hz.
+		{0xfff71000, 1, 32000, 68, 384},
+	}
+	for _, tt := range tests {
+		h := tt.header
+		if !h.Valid() {
+			t.Fatalf("%08x is not valid", uint32(h))
+		}
+		if h.Layer() != tt.layer || h.Bitrate() != tt.bitrate || h.FrameSize() != tt.size || h.SamplesPerFrame() != tt.samples {
+			t.Errorf("%08x: Layer %d, Bitrate %d, FrameSize %d, SamplesPerFrame %d, want %d, %d, %d, %d",
+				uint32(h), h.Layer(), h.Bitrate(), h.FrameSize(), h.SamplesPerFrame(),
+				tt.layer, tt.bitrate, tt.size, tt.samples)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, b := range [][]byte{
+		nil,
+		{0xff, 0xfb, 0x90},
+		{'I', 'D', '3', 4},
+		{0xff, 0xeb, 0x90, 0x44}, // Reserved version
+		{0xff, 0xf9, 0x90, 0x44}, // Reserved layer
+		{0xff, 0xfb, 0xf0, 0x44}, // Bitrate index 15
+		{0xff, 0xfb, 0x9c, 0x44}, // Reserved sample rate
+		{0xff, 0xfb, 0x90, 0x46}, // Reserved emphasis
+	} {
+		if _, err := frameheader.Parse(b); !errors.Is(err, frameheader.ErrInvalidHeader) {
+			t.Errorf("Parse(%x) error = %v, want ErrInvalidHeader", b, err)
+		}
+	}
+}
+
+func TestHeader_FreeFormat(t *testing.T) {
+	h := frameheader.Header(0xfffb0044)
+	if !h.Valid() {
+		t.Fatal("free format header is not valid")
+	}
+	if h.Bitrate() != 0 || h.FrameSize() != 0 {
+		t.Errorf("free format: Bitrate %d, FrameSize %d, want 0 and 0", h.Bitrate(), h.FrameSize())
+	}
+}
+
+func TestHeader_SideInfoSize(t *testing.T) {
+	tests := []struct {
+		sampleRate int
+		mono       bool
+		want       int
+	}{
+		{44100, false, 32},
+		{44100, true, 17},
+		{22050, false, 17},
+		{22050, true, 9},
+	}
+	for _, tt := range tests {
+		frame, err := testsupport.Frame(tt.sampleRate, 64, tt.mono, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := frameheader.Parse(frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := h.SideInfoSize(); got != tt.want {
+			t.Errorf("%d Hz mono=%v: SideInfoSize() = %d, want %d", tt.sampleRate, tt.mono, got, tt.want)
+		}
+	}
+}
+
+func TestHeader_Flags(t *testing.T) {
+	// MPEG-1 Layer III joint stereo with intensity and middle/side stereo,
+	// copyright, copy and CCITT J.17 emphasis.
+	h := frameheader.Header(0xfffb907b)
+	if !h.Valid() {
+		t.Fatal("header is not valid")
+	}
+	if h.Mode() != frameheader.ModeJointStereo || h.ModeExtension() != 3 {
+		t.Errorf("Mode %v, ModeExtension %d, want joint stereo and 3", h.Mode(), h.ModeExtension())
+	}
+	if !h.Copyright() || h.Original() || h.Emphasis() != frameheader.EmphasisCCITT {
+		t.Errorf("Copyright %v, Original %v, Emphasis %v", h.Copyright(), h.Original(), h.Emphasis())
+	}
+	if got := h.Emphasis().String(); got != "CCITT J.17" {
+		t.Errorf("Emphasis().String() = %q", got)
+	}
+}
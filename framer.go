@@ -0,0 +1,161 @@
+package mp3
+
+import (
+	"github.com/llehouerou/go-mp3/internal/frameheader"
+)
+
+// A Framer splits a raw MP3 byte stream into complete frames without
+// decoding them.
+//
+// Data is fed with Write in chunks of any size, and complete frames are
+// retrieved with Next. Frames split across chunk boundaries are held back
+// until the rest of the frame arrives. The first frame, and the first frame
+// after skipped bytes, are returned only once the header of the next frame,
+// a tag, or the end of the data marked by Close follows them, so that false
+// syncs in tags or garbage are not taken for frames. Bytes that do not
+// belong to a frame, such as ID3v2 tags or garbage between frames, are
+// skipped and counted in Skipped.
+//
+// A Framer is useful for proxies and recorders that forward or store MP3
+// frames but never need PCM.
+type Framer struct {
+	buf     []byte
+	skipped int64
+
+	// tag is the number of bytes of an ID3v2 tag left to skip.
+	tag int64
+
+	// synced is set when the data starts where the last frame returned
+	// ends, so that a frame header found there needs no confirmation.
+	synced bool
+
+	// closed is set by Close.
+	closed bool
+}
+
+// NewFramer returns a new, empty Framer.
+func NewFramer() *Framer {
+	return &Framer{}
+}
+
+// Write appends p to the data waiting to be split into frames.
+// Write always consumes all of p and never returns an error.
+func (f *Framer) Write(p []byte) (int, error) {
+	f.buf = append(f.buf, p...)
+	return len(p), nil
+}
+
+// Close marks the end of the data, so that Next can return a last frame
+// that no other frame header confirms. Close always returns nil.
+func (f *Framer) Close() error {
+	f.closed = true
+	return nil
+}
+
+// Next returns the next complete frame, including its 4-byte header.
+// It returns false when more data must be written, or Close called, before
+// another frame is available.
+//
+// The returned slice is only valid until the next call to Write or Next.
+func (f *Framer) Next() ([]byte, bool) {
+	for len(f.buf) > 0 {
+		if f.tag > 0 {
+			n := int(min(f.tag, int64(len(f.buf))))
+			f.skip(n)
+			f.tag -= int64(n)
+			continue
+		}
+		if len(f.buf) < 10 && !f.closed {
+			// Wait for a whole frame or tag header.
+			return nil, false
+		}
+		if len(f.buf) >= 10 && string(f.buf[:3]) == "ID3" && f.buf[3] >= 2 && f.buf[3] <= 4 {
+			f.tag = 10 + int64(id3v2TagSize(f.buf, -1))
+			continue
+		}
+		size := 0
+		if len(f.buf) >= 4 {
+			size = framerFrameSize(headerFromBytes(f.buf))
+		}
+		if size == 0 {
+			f.skip(1)
+			continue
+		}
+		if !f.synced {
+			// Out of sync, the header may be a false sync in a tag or
+			// garbage: take it only if another frame header, a tag or the
+			// end of the data follows the frame.
+			if len(f.buf) < size+4 && !f.closed {
+				return nil, false
+			}
+			if len(f.buf) < size || len(f.buf) >= size+4 && !framerFollows(f.buf[size:]) {
+				f.skip(1)
+				continue
+			}
+		}
+		if len(f.buf) < size {
+			return nil, false
+		}
+		frame := f.buf[:size]
+		f.buf = f.buf[size:]
+		f.synced = true
+		return frame, true
+	}
+	if len(f.buf) == 0 {
+		// Drop the backing array once everything has been consumed so a
+		// long-running Framer does not keep growing it.
+		f.buf = nil
+	}
+	return nil, false
+}
+
+// skip discards the first n bytes of the data.
+func (f *Framer) skip(n int) {
+	f.buf = f.buf[n:]
+	f.skipped += int64(n)
+	f.synced = false
+}
+
+// Buffered returns the number of bytes written but not yet returned as part
+// of a frame or skipped.
+func (f *Framer) Buffered() int {
+	return len(f.buf)
+}
+
+// Skipped returns the total number of bytes discarded so far because they
+// were not part of a valid frame.
+func (f *Framer) Skipped() int64 {
+	return f.skipped
+}
+
+// headerFromBytes returns the frame header stored in the first 4 bytes of b.
+func headerFromBytes(b []byte) frameheader.FrameHeader {
+	return frameheader.FrameHeader(uint32(b[0])<<24 | uint32(b[1])<<16 |
+		uint32(b[2])<<8 | uint32(b[3]))
+}
+
+// framerFollows reports whether b, the data after a frame, starts with
+// another frame header or a tag.
+func framerFollows(b []byte) bool {
+	if framerFrameSize(headerFromBytes(b)) > 0 {
+		return true
+	}
+	switch string(b[:3]) {
+	case "ID3", "TAG", "APE":
+		return true
+	}
+	return false
+}
+
+// framerFrameSize returns the size in bytes of the frame starting with h,
+// or 0 if h is not a usable frame header.
+func framerFrameSize(h frameheader.FrameHeader) int {
+	if !h.IsValid() || h.BitrateIndex() == 0 {
+		return 0
+	}
+	size, err := h.FrameSize()
+	if err != nil || size < 4 {
+		return 0
+	}
+	return size
+}
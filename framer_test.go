@@ -0,0 +1,113 @@
+package mp3
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestFramer_MatchesDecoderFrameCount(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+
+	for _, chunkSize := range []int{1, 7, 417, 4096} {
+		f := NewFramer()
+		frames := 0
+		var out []byte
+		for i := 0; i < len(data); i += chunkSize {
+			end := min(i+chunkSize, len(data))
+			if _, err := f.Write(data[i:end]); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			for {
+				frame, ok := f.Next()
+				if !ok {
+					break
+				}
+				frames++
+				out = append(out, frame...)
+			}
+		}
+		if frames != len(d.frameStarts) {
+			t.Errorf("chunk size %d: got %d frames, want %d", chunkSize, frames, len(d.frameStarts))
+		}
+		if !bytes.Equal(out, data[:len(out)]) {
+			t.Errorf("chunk size %d: frames do not reproduce the input stream", chunkSize)
+		}
+	}
+}
+
+func TestFramer_HoldsBackPartialFrame(t *testing.T) {
+	frame := createMinimalMP3Frame()
+	f := NewFramer()
+	_, _ = f.Write(frame[:200])
+	if _, ok := f.Next(); ok {
+		t.Fatal("Next returned a frame before it was complete")
+	}
+	if f.Buffered() != 200 {
+		t.Errorf("Buffered() = %d, want 200", f.Buffered())
+	}
+	_, _ = f.Write(frame[200:])
+	_ = f.Close()
+	got, ok := f.Next()
+	if !ok {
+		t.Fatal("Next did not return the completed frame")
+	}
+	if !bytes.Equal(got, frame) {
+		t.Error("returned frame differs from input")
+	}
+}
+
+func TestFramer_SkipsGarbage(t *testing.T) {
+	f := NewFramer()
+	_, _ = f.Write([]byte("garbage"))
+	_, _ = f.Write(createMinimalMP3Frame())
+	_ = f.Close()
+	if _, ok := f.Next(); !ok {
+		t.Fatal("expected a frame after garbage")
+	}
+	if f.Skipped() != int64(len("garbage")) {
+		t.Errorf("Skipped() = %d, want %d", f.Skipped(), len("garbage"))
+	}
+}
+
+func TestFramer_ConfirmsFrames(t *testing.T) {
+	frame := createMinimalMP3Frame()
+	// A false sync in garbage, and a tag whose payload holds a whole frame.
+	garbage := append([]byte{0xff, 0xfb, 0x90, 0x00}, "garbage"...)
+	tag := createID3v2Tag(3, len(frame))
+	copy(tag[10:], frame)
+
+	var data []byte
+	data = append(data, garbage...)
+	data = append(data, frame...)
+	data = append(data, tag...)
+	data = append(data, frame...)
+
+	f := NewFramer()
+	_, _ = f.Write(data)
+	_ = f.Close()
+	frames := 0
+	for {
+		got, ok := f.Next()
+		if !ok {
+			break
+		}
+		if !bytes.Equal(got, frame) {
+			t.Errorf("frame %d differs from input", frames)
+		}
+		frames++
+	}
+	if frames != 2 {
+		t.Errorf("got %d frames, want 2", frames)
+	}
+	if want := int64(len(garbage) + len(tag)); f.Skipped() != want {
+		t.Errorf("Skipped() = %d, want %d", f.Skipped(), want)
+	}
+}
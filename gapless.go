@@ -0,0 +1,130 @@
+package mp3
+
+import (
+	"io"
+)
+
+// seamWindow is the number of samples on each side of a seam whose first
+// differences make up the reference slope of the signal.
+const seamWindow = 64
+
+// Thresholds of CheckSeam. A step at the seam is a discontinuity when it is
+// both larger than seamFactor times the steepest slope around the seam and
+// larger than seamMinJump, so that noise near silence is not reported.
+const (
+	seamFactor  = 3
+	seamMinJump = 256
+)
+
+// A SeamReport describes the join between the end of one track and the
+// start of the next.
+type SeamReport struct {
+	// Jump is the largest absolute difference, over both channels, between
+	// the last sample of the first track and the first sample of the second.
+	Jump int
+
+	// LocalMax is the largest absolute difference between consecutive
+	// samples, over both channels, within the samples on each side of the
+	// seam, excluding the seam itself.
+	LocalMax int
+
+	// Discontinuous reports whether Jump is a spike compared to LocalMax,
+	// which is heard as a click between the tracks.
+	Discontinuous bool
+}
+
+// CheckSeam reports whether the 16-bit little-endian stereo PCM in head
+// continues tail without a discontinuity, by comparing the first derivative
+// of the signal at the seam with the derivative around it.
+func CheckSeam(tail, head []byte) *SeamReport {
+	tail = tail[:len(tail)/4*4]
+	head = head[:len(head)/4*4]
+	rep := &SeamReport{}
+	if len(tail) == 0 || len(head) == 0 {
+		return rep
+	}
+	tail = tail[max(len(tail)-(seamWindow+1)*4, 0):]
+	head = head[:min(len(head), (seamWindow+1)*4)]
+
+	rep.LocalMax = max(maxStep(tail), maxStep(head))
+	for ch := range 2 {
+		d := sampleAt(head, 0, ch) - sampleAt(tail, len(tail)/4-1, ch)
+		rep.Jump = max(rep.Jump, abs(d))
+	}
+	rep.Discontinuous = rep.Jump > seamFactor*rep.LocalMax && rep.Jump > seamMinJump
+	return rep
+}
+
+// maxStep returns the largest absolute difference between consecutive
+// samples of the same channel in b.
+func maxStep(b []byte) int {
+	m := 0
+	for i := 1; i < len(b)/4; i++ {
+		for ch := range 2 {
+			m = max(m, abs(sampleAt(b, i, ch)-sampleAt(b, i-1, ch)))
+		}
+	}
+	return m
+}
+
+func sampleAt(b []byte, i, ch int) int {
+	j := i*4 + ch*2
+	return int(int16(uint16(b[j]) | uint16(b[j+1])<<8))
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// VerifyGaplessJoin decodes two consecutive tracks, removes the encoder
+// delay and padding given by their LAME tags as a gapless player does, and
+// checks the join between them with CheckSeam. Tracks without a Xing header
+// are joined as decoded.
+//
+// It is meant to validate that an album encoded for gapless playback plays
+// without clicks between tracks.
+func VerifyGaplessJoin(first, second io.ReadSeeker) (*SeamReport, error) {
+	tail, err := decodeGapless(first, false)
+	if err != nil {
+		return nil, err
+	}
+	head, err := decodeGapless(second, true)
+	if err != nil {
+		return nil, err
+	}
+	return CheckSeam(tail, head), nil
+}
+
+// decodeGapless decodes r and trims the encoder delay and padding. If
+// headOnly is set, only the first samples needed by CheckSeam are decoded.
+func decodeGapless(r io.ReadSeeker, headOnly bool) ([]byte, error) {
+	d, err := NewDecoder(r)
+	if err != nil {
+		return nil, err
+	}
+	var skip, trim int64
+	if info := d.xing; info != nil {
+		// The Xing frame is decoded as a frame of silence.
+		skip = d.bytesPerFrame + int64(info.TotalDelay())*4
+		trim = int64(info.TotalPadding()) * 4
+	}
+
+	var src io.Reader = d
+	if headOnly {
+		src = io.LimitReader(d, skip+(seamWindow+1)*4)
+	}
+	pcm, err := io.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+	if headOnly {
+		trim = 0
+	}
+	if skip+trim >= int64(len(pcm)) {
+		return nil, nil
+	}
+	return pcm[skip : int64(len(pcm))-trim], nil
+}
@@ -0,0 +1,104 @@
+package mp3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+// sinePCM returns n stereo samples of a sine starting at sample start.
+func sinePCM(start, n int) []byte {
+	b := make([]byte, n*4)
+	for i := range n {
+		v := int16(8000 * math.Sin(float64(start+i)*0.03))
+		binary.LittleEndian.PutUint16(b[i*4:], uint16(v))
+		binary.LittleEndian.PutUint16(b[i*4+2:], uint16(-v))
+	}
+	return b
+}
+
+func TestCheckSeam_Continuous(t *testing.T) {
+	rep := CheckSeam(sinePCM(0, 1000), sinePCM(1000, 1000))
+	if rep.Discontinuous {
+		t.Errorf("continuous signal reported as discontinuous: %+v", rep)
+	}
+	if rep.Jump == 0 || rep.Jump > rep.LocalMax {
+		t.Errorf("Jump = %d, want a step like its neighbours (LocalMax %d)", rep.Jump, rep.LocalMax)
+	}
+}
+
+func TestCheckSeam_Discontinuous(t *testing.T) {
+	// Dropping 40 samples between the tracks cuts the sine.
+	rep := CheckSeam(sinePCM(0, 1000), sinePCM(1040, 1000))
+	if !rep.Discontinuous {
+		t.Errorf("cut signal not reported as discontinuous: %+v", rep)
+	}
+
+	// So does a track that starts with leftover silence while the first one
+	// ends near a peak.
+	head := append(make([]byte, 100*4), sinePCM(1000, 1000)...)
+	rep = CheckSeam(sinePCM(0, 1100), head)
+	if !rep.Discontinuous {
+		t.Errorf("gap not reported as discontinuous: %+v", rep)
+	}
+}
+
+func TestCheckSeam_Silence(t *testing.T) {
+	rep := CheckSeam(make([]byte, 400), make([]byte, 400))
+	if *rep != (SeamReport{}) {
+		t.Errorf("CheckSeam on silence = %+v, want zero report", rep)
+	}
+	rep = CheckSeam(nil, sinePCM(0, 100))
+	if rep.Discontinuous {
+		t.Errorf("empty tail reported as discontinuous: %+v", rep)
+	}
+}
+
+func TestDecodeGapless_TrimsDelayAndPadding(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{
+		Frames:         10,
+		EncoderDelay:   576,
+		EncoderPadding: 1200,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm, err := decodeGapless(bytes.NewReader(data), false)
+	if err != nil {
+		t.Fatalf("decodeGapless failed: %v", err)
+	}
+	want := (10*1152 - (576 + 529) - (1200 - 529)) * 4
+	if len(pcm) != want {
+		t.Errorf("decoded %d bytes, want %d", len(pcm), want)
+	}
+
+	head, err := decodeGapless(bytes.NewReader(data), true)
+	if err != nil {
+		t.Fatalf("decodeGapless failed: %v", err)
+	}
+	if len(head) != (seamWindow+1)*4 {
+		t.Errorf("decoded %d head bytes, want %d", len(head), (seamWindow+1)*4)
+	}
+}
+
+func TestVerifyGaplessJoin(t *testing.T) {
+	first, err := testsupport.Generate(testsupport.Options{Frames: 8, EncoderDelay: 576, EncoderPadding: 900})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A track without Xing header is joined as decoded.
+	second, err := testsupport.Generate(testsupport.Options{Frames: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep, err := VerifyGaplessJoin(bytes.NewReader(first), bytes.NewReader(second))
+	if err != nil {
+		t.Fatalf("VerifyGaplessJoin failed: %v", err)
+	}
+	if rep.Discontinuous || rep.Jump != 0 {
+		t.Errorf("silent tracks reported as %+v, want a clean join", rep)
+	}
+}
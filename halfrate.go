@@ -0,0 +1,27 @@
+package mp3
+
+import "github.com/llehouerou/go-mp3/internal/frameheader"
+
+// WithHalfRate makes the decoder synthesize only the lower 16 of the 32
+// subbands and output PCM at half the sample rate of the stream, such as
+// 22050 Hz for a 44100 Hz stream. Content above a quarter of the stream rate
+// is dropped, and the synthesis costs about half as much CPU, which suits
+// previews, waveform generation and low-power devices.
+//
+// SampleRate, Length, BytesPerFrame and the time and sample positions all
+// refer to the half-rate output.
+func WithHalfRate() Option {
+	return func(c *config) {
+		c.halfRate = true
+	}
+}
+
+// pcmBytes returns the number of bytes of 16-bit PCM decoded from a frame
+// with header h.
+func (d *Decoder) pcmBytes(h frameheader.FrameHeader) int64 {
+	n := int64(h.BytesPerFrame())
+	if d.halfRate {
+		n /= 2
+	}
+	return n
+}
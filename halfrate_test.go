@@ -0,0 +1,62 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestWithHalfRate(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	ref, full := decodeFresh(t, data)
+	d, err := NewDecoder(bytes.NewReader(data), WithHalfRate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.SampleRate() != ref.SampleRate()/2 || d.Length() != ref.Length()/2 ||
+		d.BytesPerFrame() != ref.BytesPerFrame()/2 {
+		t.Errorf("SampleRate %d, Length %d, BytesPerFrame %d, want half of %d, %d, %d",
+			d.SampleRate(), d.Length(), d.BytesPerFrame(),
+			ref.SampleRate(), ref.Length(), ref.BytesPerFrame())
+	}
+	if d.Duration() != ref.Duration() {
+		t.Errorf("Duration = %v, want %v", d.Duration(), ref.Duration())
+	}
+	half, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(half)) != d.Length() {
+		t.Fatalf("decoded %d bytes, want %d", len(half), d.Length())
+	}
+
+	// The output is the full-rate output without its upper half spectrum,
+	// decimated: most of the energy of music is in the lower subbands.
+	var energy, diff float64
+	for i := 0; i < len(half); i += 2 {
+		s := float64(int16(uint16(full[2*i-i%4]) | uint16(full[2*i-i%4+1])<<8))
+		h := float64(int16(uint16(half[i]) | uint16(half[i+1])<<8))
+		energy += s * s
+		diff += (s - h) * (s - h)
+	}
+	if diff > energy/100 {
+		t.Errorf("half-rate output differs from the decimated output by %g of its energy", diff/energy)
+	}
+
+	// Seeking lands on the same samples as linear decoding.
+	pos := 100*d.BytesPerFrame() + 400
+	if _, err := d.Seek(pos, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 2*d.BytesPerFrame())
+	if _, err := io.ReadFull(d, buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, half[pos:pos+int64(len(buf))]) {
+		t.Error("PCM after Seek differs from linear decoding")
+	}
+}
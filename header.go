@@ -0,0 +1,120 @@
+package mp3
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/llehouerou/go-mp3/frameheader"
+	"github.com/llehouerou/go-mp3/internal/consts"
+	internalheader "github.com/llehouerou/go-mp3/internal/frameheader"
+)
+
+// ErrInvalidHeader is returned by ParseHeader when the bytes are not a valid
+// MPEG-1 or MPEG-2 Layer III frame header.
+var ErrInvalidHeader = errors.New("mp3: invalid frame header")
+
+// A Header describes an MPEG audio frame header. It is returned by
+// ParseHeader and used by FrameSizeOf and DurationOf, which need no Decoder.
+type Header struct {
+	// Version is the MPEG version: 1 or 2.
+	Version int
+
+	// Bitrate is the bitrate in bits per second, or 0 for free format.
+	Bitrate int
+
+	// SampleRate is the sample rate in Hz.
+	SampleRate int
+
+	// Channels is 1 for single channel frames and 2 otherwise.
+	Channels int
+
+	// Mode is the channel mode.
+	Mode frameheader.Mode
+
+	// ModeExtension holds the joint stereo coding bits: bit 0 for intensity
+	// stereo and bit 1 for middle/side stereo.
+	ModeExtension int
+
+	// SamplesPerFrame is the number of samples per channel in the frame:
+	// 1152 for MPEG-1 and 576 for MPEG-2.
+	SamplesPerFrame int
+
+	// Padding reports whether the frame has an extra padding byte.
+	Padding bool
+
+	// Protected reports whether the header is followed by a 16-bit CRC.
+	Protected bool
+
+	// Copyright and Original are the copyright and original flags.
+	Copyright bool
+	Original  bool
+
+	// Emphasis is the de-emphasis the audio needs.
+	Emphasis frameheader.Emphasis
+}
+
+// ParseHeader parses the 4-byte frame header at the start of b. MPEG-2.5
+// headers are rejected, as the decoder does not support them.
+func ParseHeader(b []byte) (Header, error) {
+	if len(b) < 4 {
+		return Header{}, ErrInvalidHeader
+	}
+	h := internalheader.FrameHeader(binary.BigEndian.Uint32(b))
+	if !h.IsValid() || h.ID() == consts.Version2_5 {
+		return Header{}, ErrInvalidHeader
+	}
+	return headerOf(h)
+}
+
+// headerOf converts a valid internal frame header.
+func headerOf(h internalheader.FrameHeader) (Header, error) {
+	sampleRate, err := h.SamplingFrequencyValue()
+	if err != nil {
+		return Header{}, ErrInvalidHeader
+	}
+	version := 1
+	if h.ID() == consts.Version2 {
+		version = 2
+	}
+	return Header{
+		Version:         version,
+		Bitrate:         h.Bitrate(),
+		SampleRate:      sampleRate,
+		Channels:        h.NumberOfChannels(),
+		Mode:            frameheader.Mode(h.Mode()),
+		SamplesPerFrame: h.SamplesPerFrame(),
+		Padding:         h.PaddingBit() == 1,
+		Protected:       h.ProtectionBit() == 0,
+		ModeExtension:   h.ModeExtension(),
+		Copyright:       h.Copyright() == 1,
+		Original:        h.OriginalOrCopy() == 1,
+		Emphasis:        frameheader.Emphasis(h.Emphasis()),
+	}, nil
+}
+
+// FrameSizeOf returns the size in bytes of a frame with header h, including
+// the header. It returns 0 for free format frames, whose size is not given
+// by the header, and for headers without a sample rate.
+func FrameSizeOf(h Header) int {
+	if h.SampleRate == 0 || h.Bitrate == 0 {
+		return 0
+	}
+	size := h.SamplesPerFrame / 8 * h.Bitrate / h.SampleRate
+	if h.Padding {
+		size++
+	}
+	return size
+}
+
+// DurationOf returns the duration of frameCount frames with header h.
+func DurationOf(frameCount int64, h Header) time.Duration {
+	if h.SampleRate == 0 {
+		return 0
+	}
+	samples := frameCount * int64(h.SamplesPerFrame)
+	rate := int64(h.SampleRate)
+	// Split the seconds off to avoid overflowing on long streams.
+	return time.Duration(samples/rate)*time.Second +
+		time.Duration(samples%rate)*time.Second/time.Duration(rate)
+}
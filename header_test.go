@@ -0,0 +1,75 @@
+package mp3
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/llehouerou/go-mp3/frameheader"
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		sampleRate, bitrate int
+		mono, padding       bool
+		want                Header
+	}{
+		{44100, 128, false, true, Header{Version: 1, Bitrate: 128000, SampleRate: 44100, Channels: 2, Mode: frameheader.ModeJointStereo, SamplesPerFrame: 1152, Padding: true, Original: true}},
+		{48000, 320, true, false, Header{Version: 1, Bitrate: 320000, SampleRate: 48000, Channels: 1, Mode: frameheader.ModeSingleChannel, SamplesPerFrame: 1152, Original: true}},
+		{22050, 64, false, false, Header{Version: 2, Bitrate: 64000, SampleRate: 22050, Channels: 2, Mode: frameheader.ModeJointStereo, SamplesPerFrame: 576, Original: true}},
+	}
+	for _, tt := range tests {
+		frame, err := testsupport.Frame(tt.sampleRate, tt.bitrate, tt.mono, tt.padding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := ParseHeader(frame)
+		if err != nil {
+			t.Fatalf("ParseHeader(%d Hz, %d kbps) failed: %v", tt.sampleRate, tt.bitrate, err)
+		}
+		if h != tt.want {
+			t.Errorf("ParseHeader(%d Hz, %d kbps) = %+v, want %+v", tt.sampleRate, tt.bitrate, h, tt.want)
+		}
+		if got := FrameSizeOf(h); got != len(frame) {
+			t.Errorf("FrameSizeOf(%+v) = %d, want %d", h, got, len(frame))
+		}
+	}
+}
+
+func TestParseHeader_Invalid(t *testing.T) {
+	mpeg25, err := testsupport.Frame(11025, 32, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range [][]byte{
+		nil,
+		{0xff, 0xfb},
+		{'I', 'D', '3', 4},
+		{0xff, 0xfb, 0xf0, 0x44}, // Bitrate index 15
+		{0xff, 0xfd, 0x90, 0x44}, // Layer II
+		mpeg25[:4],
+	} {
+		if _, err := ParseHeader(b); !errors.Is(err, ErrInvalidHeader) {
+			t.Errorf("ParseHeader(%x) error = %v, want ErrInvalidHeader", b, err)
+		}
+	}
+}
+
+func TestDurationOf(t *testing.T) {
+	h := Header{Version: 1, SampleRate: 44100, SamplesPerFrame: 1152}
+	if got, want := DurationOf(44100, h), 1152*time.Second; got != want {
+		t.Errorf("DurationOf(44100 frames) = %v, want %v", got, want)
+	}
+	if got, want := DurationOf(1, h), 26122448*time.Nanosecond; got != want {
+		t.Errorf("DurationOf(1 frame) = %v, want %v", got, want)
+	}
+	// 100 hours of frames must not overflow.
+	frames := int64(100*3600*44100) / 1152
+	if got := DurationOf(frames, h); got < 99*time.Hour || got > 100*time.Hour {
+		t.Errorf("DurationOf(%d frames) = %v, want about 100h", frames, got)
+	}
+	if got := DurationOf(10, Header{}); got != 0 {
+		t.Errorf("DurationOf(zero header) = %v, want 0", got)
+	}
+}
@@ -0,0 +1,224 @@
+// Package httprange provides an io.ReadSeeker over a remote file served by
+// an HTTP server that supports Range requests.
+//
+// A Reader can be given to mp3.NewDecoder so that remote files get Duration
+// and SeekToTime without being downloaded first: the file is fetched in
+// chunks as they are needed, and recently used chunks are cached.
+package httprange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Default settings of a Reader.
+const (
+	DefaultChunkSize   = 256 * 1024
+	DefaultCacheChunks = 16
+)
+
+// ErrRangeNotSupported is returned by Open when the server ignores Range
+// requests.
+var ErrRangeNotSupported = errors.New("httprange: server does not support range requests")
+
+// An Option configures a Reader created by Open.
+type Option func(*Reader)
+
+// WithClient sets the HTTP client used for requests. By default
+// http.DefaultClient is used.
+func WithClient(c *http.Client) Option {
+	return func(r *Reader) {
+		r.client = c
+	}
+}
+
+// WithChunkSize sets the number of bytes fetched per request.
+func WithChunkSize(n int) Option {
+	return func(r *Reader) {
+		if n > 0 {
+			r.chunkSize = int64(n)
+		}
+	}
+}
+
+// WithCacheChunks sets how many chunks are kept in memory. The least
+// recently used chunk is evicted first.
+func WithCacheChunks(n int) Option {
+	return func(r *Reader) {
+		if n > 0 {
+			r.cacheChunks = n
+		}
+	}
+}
+
+// A Reader reads a remote file with HTTP Range requests. It implements
+// io.ReadSeeker.
+//
+// A Reader is not safe for concurrent use.
+type Reader struct {
+	ctx         context.Context
+	client      *http.Client
+	url         string
+	size        int64
+	pos         int64
+	chunkSize   int64
+	cacheChunks int
+
+	// chunks holds the cached chunks by index; lru lists their indices
+	// from the least to the most recently used.
+	chunks map[int64][]byte
+	lru    []int64
+}
+
+// Open returns a Reader for the file at url. The first chunk is fetched to
+// learn the file size and check that the server supports Range requests.
+// Requests made by the Reader are bound to ctx.
+func Open(ctx context.Context, url string, opts ...Option) (*Reader, error) {
+	r := &Reader{
+		ctx:         ctx,
+		client:      http.DefaultClient,
+		url:         url,
+		chunkSize:   DefaultChunkSize,
+		cacheChunks: DefaultCacheChunks,
+		chunks:      map[int64][]byte{},
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	data, size, err := r.fetch(0)
+	if err != nil {
+		return nil, err
+	}
+	r.size = size
+	r.store(0, data)
+	return r, nil
+}
+
+// Size returns the size of the remote file in bytes.
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+// Read implements io.Reader.
+func (r *Reader) Read(buf []byte) (int, error) {
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+	idx := r.pos / r.chunkSize
+	chunk, err := r.chunk(idx)
+	if err != nil {
+		return 0, err
+	}
+	off := r.pos - idx*r.chunkSize
+	if off >= int64(len(chunk)) {
+		// The server sent less than the file size it announced.
+		return 0, io.ErrUnexpectedEOF
+	}
+	n := copy(buf, chunk[off:])
+	r.pos += int64(n)
+	return n, nil
+}
+
+// Seek implements io.Seeker. Seeking does not make any request.
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = r.pos + offset
+	case io.SeekEnd:
+		pos = r.size + offset
+	default:
+		return 0, errors.New("httprange: invalid whence")
+	}
+	if pos < 0 {
+		return 0, errors.New("httprange: negative position")
+	}
+	r.pos = pos
+	return pos, nil
+}
+
+func (r *Reader) chunk(idx int64) ([]byte, error) {
+	if data, ok := r.chunks[idx]; ok {
+		r.touch(idx)
+		return data, nil
+	}
+	data, _, err := r.fetch(idx)
+	if err != nil {
+		return nil, err
+	}
+	r.store(idx, data)
+	return data, nil
+}
+
+func (r *Reader) touch(idx int64) {
+	for i, c := range r.lru {
+		if c == idx {
+			r.lru = append(r.lru[:i], r.lru[i+1:]...)
+			break
+		}
+	}
+	r.lru = append(r.lru, idx)
+}
+
+func (r *Reader) store(idx int64, data []byte) {
+	if len(r.lru) >= r.cacheChunks {
+		delete(r.chunks, r.lru[0])
+		r.lru = r.lru[1:]
+	}
+	r.chunks[idx] = data
+	r.lru = append(r.lru, idx)
+}
+
+// fetch requests the chunk idx and returns its content and the total size
+// of the file.
+func (r *Reader) fetch(idx int64) ([]byte, int64, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	start := idx * r.chunkSize
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, start+r.chunkSize-1))
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		return nil, 0, ErrRangeNotSupported
+	default:
+		return nil, 0, fmt.Errorf("httprange: unexpected status %s", resp.Status)
+	}
+	size, err := parseContentRangeSize(resp.Header.Get("Content-Range"))
+	if err != nil {
+		return nil, 0, err
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, r.chunkSize))
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, size, nil
+}
+
+// parseContentRangeSize returns the complete length from a Content-Range
+// header such as "bytes 0-1023/146515".
+func parseContentRangeSize(h string) (int64, error) {
+	i := strings.LastIndexByte(h, '/')
+	if !strings.HasPrefix(h, "bytes ") || i < 0 {
+		return 0, fmt.Errorf("httprange: invalid Content-Range %q", h)
+	}
+	size, err := strconv.ParseInt(h[i+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("httprange: unknown file size in Content-Range %q", h)
+	}
+	return size, nil
+}
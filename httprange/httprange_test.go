@@ -0,0 +1,123 @@
+package httprange
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/llehouerou/go-mp3"
+)
+
+// newServer serves data with Range support and counts the requests.
+func newServer(t *testing.T, data []byte) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		http.ServeContent(w, req, "audio.mp3", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestReader_ReadAndSeek(t *testing.T) {
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	srv, requests := newServer(t, data)
+
+	r, err := Open(context.Background(), srv.URL, WithChunkSize(1000), WithCacheChunks(2))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if r.Size() != int64(len(data)) {
+		t.Errorf("Size() = %d, want %d", r.Size(), len(data))
+	}
+
+	if _, err := r.Seek(-1500, io.SeekEnd); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(got, data[8500:]) {
+		t.Error("data read after Seek differs")
+	}
+	// The first chunk and the last two.
+	if n := requests.Load(); n != 3 {
+		t.Errorf("made %d requests, want 3", n)
+	}
+
+	// Cached chunks are not requested again.
+	if _, err := r.Seek(9000, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	if _, err := io.ReadAll(r); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("made %d requests, want 3", n)
+	}
+}
+
+func TestReader_Decoder(t *testing.T) {
+	data, err := os.ReadFile("../example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	srv, _ := newServer(t, data)
+
+	r, err := Open(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	d, err := mp3.NewDecoder(r)
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	local, err := mp3.NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if d.Duration() <= 0 || d.Duration() != local.Duration() {
+		t.Errorf("Duration() = %v, want %v", d.Duration(), local.Duration())
+	}
+
+	if err := d.SeekToTime(5 * time.Second); err != nil {
+		t.Fatalf("SeekToTime failed: %v", err)
+	}
+	if err := local.SeekToTime(5 * time.Second); err != nil {
+		t.Fatalf("SeekToTime failed: %v", err)
+	}
+	got, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	want, err := io.ReadAll(local)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("remote decoding differs from local decoding")
+	}
+}
+
+func TestOpen_RangeNotSupported(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("no ranges here"))
+	}))
+	defer srv.Close()
+
+	if _, err := Open(context.Background(), srv.URL); !errors.Is(err, ErrRangeNotSupported) {
+		t.Errorf("Open error = %v, want ErrRangeNotSupported", err)
+	}
+}
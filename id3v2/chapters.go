@@ -0,0 +1,182 @@
+package id3v2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"time"
+)
+
+// NoOffset is the value of Chapter.StartOffset and Chapter.EndOffset when
+// the byte offsets are not set and the times should be used instead.
+const NoOffset = 0xFFFFFFFF
+
+// A Chapter is a chapter (CHAP) frame, as used by podcasts and audiobooks.
+type Chapter struct {
+	// ID is the element ID that identifies the chapter within the tag.
+	ID string
+
+	// Start and End delimit the chapter in time.
+	Start time.Duration
+	End   time.Duration
+
+	// StartOffset and EndOffset are the byte offsets of the chapter
+	// boundaries in the file, or NoOffset.
+	StartOffset uint32
+	EndOffset   uint32
+
+	// Frames holds the frames embedded in the chapter, typically a TIT2
+	// title and sometimes a URL or picture.
+	Frames []Frame
+}
+
+// Title returns the title of the chapter from its embedded TIT2 frame.
+func (c *Chapter) Title() string {
+	sub := Tag{Frames: c.Frames}
+	return sub.Title()
+}
+
+// A TableOfContents is a table of contents (CTOC) frame listing the
+// element IDs of chapters or of nested tables of contents.
+type TableOfContents struct {
+	// ID is the element ID of the table of contents.
+	ID string
+
+	// TopLevel reports whether this is the root of the hierarchy.
+	TopLevel bool
+
+	// Ordered reports whether Children are listed in playback order.
+	Ordered bool
+
+	// Children lists the element IDs of the entries.
+	Children []string
+
+	// Frames holds the frames embedded in the table of contents.
+	Frames []Frame
+}
+
+// Title returns the title of the table of contents from its embedded TIT2
+// frame.
+func (c *TableOfContents) Title() string {
+	sub := Tag{Frames: c.Frames}
+	return sub.Title()
+}
+
+// Chapters returns the chapters of the tag. When an ordered top-level table
+// of contents is present, chapters are returned in its order; otherwise they
+// are sorted by start time.
+func (t *Tag) Chapters() []Chapter {
+	var chapters []Chapter
+	for _, f := range t.Frames {
+		if f.ID != "CHAP" {
+			continue
+		}
+		c, ok := parseChapter(t.Version, f.Data)
+		if ok {
+			chapters = append(chapters, c)
+		}
+	}
+	if len(chapters) == 0 {
+		return nil
+	}
+
+	for _, toc := range t.TablesOfContents() {
+		if !toc.TopLevel || !toc.Ordered {
+			continue
+		}
+		rank := make(map[string]int, len(toc.Children))
+		for i, id := range toc.Children {
+			rank[id] = i
+		}
+		sort.SliceStable(chapters, func(i, j int) bool {
+			ri, iok := rank[chapters[i].ID]
+			rj, jok := rank[chapters[j].ID]
+			if iok != jok {
+				return iok
+			}
+			if !iok {
+				return chapters[i].Start < chapters[j].Start
+			}
+			return ri < rj
+		})
+		return chapters
+	}
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].Start < chapters[j].Start
+	})
+	return chapters
+}
+
+// Chapter returns the chapter with the given element ID, or nil.
+func (t *Tag) Chapter(id string) *Chapter {
+	for _, c := range t.Chapters() {
+		if c.ID == id {
+			return &c
+		}
+	}
+	return nil
+}
+
+// TablesOfContents returns the tables of contents of the tag.
+func (t *Tag) TablesOfContents() []TableOfContents {
+	var tocs []TableOfContents
+	for _, f := range t.Frames {
+		if f.ID != "CTOC" {
+			continue
+		}
+		toc, ok := parseTableOfContents(t.Version, f.Data)
+		if ok {
+			tocs = append(tocs, toc)
+		}
+	}
+	return tocs
+}
+
+// splitID splits the NUL-terminated element ID at the start of b.
+func splitID(b []byte) (string, []byte, bool) {
+	i := bytes.IndexByte(b, 0)
+	if i < 0 {
+		return "", nil, false
+	}
+	return string(b[:i]), b[i+1:], true
+}
+
+func parseChapter(version byte, b []byte) (Chapter, bool) {
+	id, b, ok := splitID(b)
+	if !ok || len(b) < 16 {
+		return Chapter{}, false
+	}
+	c := Chapter{
+		ID:          id,
+		Start:       time.Duration(binary.BigEndian.Uint32(b[0:4])) * time.Millisecond,
+		End:         time.Duration(binary.BigEndian.Uint32(b[4:8])) * time.Millisecond,
+		StartOffset: binary.BigEndian.Uint32(b[8:12]),
+		EndOffset:   binary.BigEndian.Uint32(b[12:16]),
+		Frames:      parseFrames(version, b[16:]),
+	}
+	return c, true
+}
+
+func parseTableOfContents(version byte, b []byte) (TableOfContents, bool) {
+	id, b, ok := splitID(b)
+	if !ok || len(b) < 2 {
+		return TableOfContents{}, false
+	}
+	toc := TableOfContents{
+		ID:       id,
+		TopLevel: b[0]&0x02 != 0,
+		Ordered:  b[0]&0x01 != 0,
+	}
+	count := int(b[1])
+	b = b[2:]
+	for range count {
+		child, rest, ok := splitID(b)
+		if !ok {
+			return TableOfContents{}, false
+		}
+		toc.Children = append(toc.Children, child)
+		b = rest
+	}
+	toc.Frames = parseFrames(version, b)
+	return toc, true
+}
@@ -0,0 +1,401 @@
+// Package id3v2 parses ID3v2 tags, the metadata blocks commonly found at the
+// start of MP3 files.
+//
+// Versions 2.2, 2.3 and 2.4 are supported. Frames are exposed with their raw
+// content, and helpers decode the common text frames such as the title
+// (TIT2), artist (TPE1), album (TALB), track number (TRCK) and recording
+// date (TDRC). Version 2.2 frame identifiers are mapped to their 2.3/2.4
+// equivalents so callers only need to know one set of names.
+package id3v2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"unicode/utf16"
+)
+
+// HeaderSize is the size of the ID3v2 tag header in bytes.
+const HeaderSize = 10
+
+// Header flags.
+const (
+	FlagUnsynchronisation = 0x80
+	FlagExtendedHeader    = 0x40
+	FlagExperimental      = 0x20
+	FlagFooter            = 0x10
+)
+
+var (
+	// ErrNoTag is returned when the data does not start with an ID3v2 tag.
+	ErrNoTag = errors.New("id3v2: no ID3v2 tag found")
+
+	// ErrTruncated is returned when the tag is shorter than its header claims.
+	ErrTruncated = errors.New("id3v2: tag is truncated")
+
+	// ErrUnsupportedVersion is returned for tag versions other than 2.2, 2.3
+	// and 2.4.
+	ErrUnsupportedVersion = errors.New("id3v2: unsupported tag version")
+)
+
+// A Tag is a parsed ID3v2 tag.
+type Tag struct {
+	// Version is the major version of the tag: 2, 3 or 4.
+	Version byte
+
+	// Revision is the revision number of the tag.
+	Revision byte
+
+	// Flags holds the tag header flags.
+	Flags byte
+
+	// Size is the size of the tag body, excluding the header and footer.
+	Size int
+
+	// Frames holds the frames of the tag in file order.
+	Frames []Frame
+}
+
+// A Frame is a single ID3v2 frame.
+type Frame struct {
+	// ID is the four-character frame identifier, such as "TIT2". Version
+	// 2.2 identifiers are mapped to their version 2.3 equivalent when one
+	// exists.
+	ID string
+
+	// Flags holds the frame flags. It is always 0 for version 2.2 tags.
+	Flags uint16
+
+	// Data is the frame content, with unsynchronisation undone.
+	Data []byte
+}
+
+// syncsafe decodes a 28-bit syncsafe integer stored in 4 bytes.
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7f)<<21 | int(b[1]&0x7f)<<14 | int(b[2]&0x7f)<<7 | int(b[3]&0x7f)
+}
+
+// TagSize returns the total size of the tag starting at b, including the
+// header and the footer if present. b must hold at least HeaderSize bytes.
+func TagSize(b []byte) (int, error) {
+	if len(b) < HeaderSize || string(b[:3]) != "ID3" {
+		return 0, ErrNoTag
+	}
+	size := HeaderSize + syncsafe(b[6:10])
+	if b[5]&FlagFooter != 0 {
+		size += HeaderSize
+	}
+	return size, nil
+}
+
+// Parse parses the ID3v2 tag at the start of b.
+func Parse(b []byte) (*Tag, error) {
+	if len(b) < HeaderSize || string(b[:3]) != "ID3" {
+		return nil, ErrNoTag
+	}
+	t := &Tag{
+		Version:  b[3],
+		Revision: b[4],
+		Flags:    b[5],
+		Size:     syncsafe(b[6:10]),
+	}
+	if t.Version < 2 || t.Version > 4 {
+		return nil, ErrUnsupportedVersion
+	}
+	if len(b) < HeaderSize+t.Size {
+		return nil, ErrTruncated
+	}
+	body := b[HeaderSize : HeaderSize+t.Size]
+	if t.Version < 4 && t.Flags&FlagUnsynchronisation != 0 {
+		// Version 2.4 unsynchronises frames individually instead.
+		body = removeUnsynchronisation(body)
+	}
+	if t.Version >= 3 && t.Flags&FlagExtendedHeader != 0 {
+		body = skipExtendedHeader(t.Version, body)
+	}
+	t.Frames = parseFrames(t.Version, body)
+	if t.Version == 4 && t.Flags&FlagUnsynchronisation != 0 {
+		// The tag flag means every frame is unsynchronised, even when the
+		// frame flag was left unset.
+		for i := range t.Frames {
+			if t.Frames[i].Flags&frameFlagUnsynchronisation == 0 {
+				t.Frames[i].Data = removeUnsynchronisation(t.Frames[i].Data)
+			}
+		}
+	}
+	return t, nil
+}
+
+// removeUnsynchronisation undoes the unsynchronisation scheme, which inserts
+// a zero byte after every 0xFF byte that could be mistaken for a sync word.
+func removeUnsynchronisation(b []byte) []byte {
+	if !bytes.Contains(b, []byte{0xff, 0x00}) {
+		return b
+	}
+	out := make([]byte, 0, len(b))
+	for i := 0; i < len(b); i++ {
+		out = append(out, b[i])
+		if b[i] == 0xff && i+1 < len(b) && b[i+1] == 0x00 {
+			i++
+		}
+	}
+	return out
+}
+
+// skipExtendedHeader returns the tag body past its extended header.
+func skipExtendedHeader(version byte, body []byte) []byte {
+	if len(body) < 4 {
+		return nil
+	}
+	var size int
+	if version == 3 {
+		// The size excludes the size field itself.
+		size = int(binary.BigEndian.Uint32(body[:4])) + 4
+	} else {
+		size = syncsafe(body[:4])
+	}
+	if size < 4 || size > len(body) {
+		return nil
+	}
+	return body[size:]
+}
+
+var v22FrameIDs = map[string]string{
+	"TT1": "TIT1", "TT2": "TIT2", "TT3": "TIT3",
+	"TP1": "TPE1", "TP2": "TPE2", "TP3": "TPE3", "TP4": "TPE4",
+	"TAL": "TALB", "TRK": "TRCK", "TPA": "TPOS", "TYE": "TYER",
+	"TCO": "TCON", "TCM": "TCOM", "TEN": "TENC", "TCR": "TCOP",
+	"TBP": "TBPM", "TLE": "TLEN", "TXX": "TXXX", "COM": "COMM",
+	"PIC": "APIC", "ULT": "USLT", "SLT": "SYLT", "WXX": "WXXX",
+}
+
+func parseFrames(version byte, body []byte) []Frame {
+	idLen, headerLen := 4, 10
+	if version == 2 {
+		idLen, headerLen = 3, 6
+	}
+	var frames []Frame
+	for len(body) >= headerLen {
+		if body[0] == 0 {
+			// Padding.
+			break
+		}
+		id := string(body[:idLen])
+		var size int
+		var flags uint16
+		switch version {
+		case 2:
+			size = int(body[3])<<16 | int(body[4])<<8 | int(body[5])
+		case 3:
+			size = int(binary.BigEndian.Uint32(body[4:8]))
+			flags = binary.BigEndian.Uint16(body[8:10])
+		default:
+			size = syncsafe(body[4:8])
+			flags = binary.BigEndian.Uint16(body[8:10])
+		}
+		if size < 0 || size > len(body)-headerLen {
+			break
+		}
+		if version == 2 {
+			if mapped, ok := v22FrameIDs[id]; ok {
+				id = mapped
+			}
+		}
+		data := body[headerLen : headerLen+size]
+		if version == 4 {
+			data = frameData(flags, data)
+		}
+		frames = append(frames, Frame{
+			ID:    id,
+			Flags: flags,
+			Data:  data,
+		})
+		body = body[headerLen+size:]
+	}
+	return frames
+}
+
+// Version 2.4 frame format flags.
+const (
+	frameFlagUnsynchronisation   = 0x0002
+	frameFlagDataLengthIndicator = 0x0001
+)
+
+// frameData returns the content of a version 2.4 frame, undoing the
+// unsynchronisation and skipping the data length indicator if the frame
+// flags say so.
+func frameData(flags uint16, data []byte) []byte {
+	if flags&frameFlagDataLengthIndicator != 0 {
+		if len(data) < 4 {
+			return nil
+		}
+		data = data[4:]
+	}
+	if flags&frameFlagUnsynchronisation != 0 {
+		data = removeUnsynchronisation(data)
+	}
+	return data
+}
+
+// Frame returns the first frame with the given ID, or nil if there is none.
+func (t *Tag) Frame(id string) *Frame {
+	for i := range t.Frames {
+		if t.Frames[i].ID == id {
+			return &t.Frames[i]
+		}
+	}
+	return nil
+}
+
+// Text returns the first value of the text frame with the given ID, such as
+// "TIT2", or "" if the frame is absent.
+func (t *Tag) Text(id string) string {
+	values := t.TextValues(id)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// TextValues returns all values of the text frame with the given ID.
+// Version 2.4 allows several values separated by NUL characters.
+func (t *Tag) TextValues(id string) []string {
+	f := t.Frame(id)
+	if f == nil || len(f.Data) == 0 {
+		return nil
+	}
+	text := decodeText(f.Data[0], f.Data[1:])
+	text = strings.TrimRight(text, "\x00")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\x00")
+}
+
+// Title returns the title (TIT2).
+func (t *Tag) Title() string {
+	return t.Text("TIT2")
+}
+
+// Artist returns the lead artist (TPE1).
+func (t *Tag) Artist() string {
+	return t.Text("TPE1")
+}
+
+// Album returns the album title (TALB).
+func (t *Tag) Album() string {
+	return t.Text("TALB")
+}
+
+// AlbumArtist returns the album artist (TPE2).
+func (t *Tag) AlbumArtist() string {
+	return t.Text("TPE2")
+}
+
+// Track returns the track number (TRCK), possibly in "n/total" form.
+func (t *Tag) Track() string {
+	return t.Text("TRCK")
+}
+
+// Genre returns the content type (TCON).
+func (t *Tag) Genre() string {
+	return t.Text("TCON")
+}
+
+// Date returns the recording time (TDRC), falling back to the version 2.3
+// year (TYER) frame.
+func (t *Tag) Date() string {
+	if s := t.Text("TDRC"); s != "" {
+		return s
+	}
+	return t.Text("TYER")
+}
+
+// Comment returns the text of the first comment (COMM) frame.
+func (t *Tag) Comment() string {
+	f := t.Frame("COMM")
+	// Encoding byte and 3-byte language code.
+	if f == nil || len(f.Data) < 4 {
+		return ""
+	}
+	_, text := splitTerminated(f.Data[0], f.Data[4:])
+	return strings.TrimRight(decodeText(f.Data[0], text), "\x00")
+}
+
+// Text encodings.
+const (
+	EncodingISO88591 = 0
+	EncodingUTF16    = 1
+	EncodingUTF16BE  = 2
+	EncodingUTF8     = 3
+)
+
+// decodeText converts text stored with the given encoding byte to UTF-8.
+func decodeText(encoding byte, b []byte) string {
+	switch encoding {
+	case EncodingUTF16:
+		return decodeUTF16(b, true)
+	case EncodingUTF16BE:
+		return decodeUTF16(b, false)
+	case EncodingUTF8:
+		return string(b)
+	default:
+		r := make([]rune, len(b))
+		for i, c := range b {
+			r[i] = rune(c)
+		}
+		return string(r)
+	}
+}
+
+// decodeUTF16 decodes UTF-16 text. When bom is true, an optional byte order
+// mark selects the byte order; each NUL-separated value may carry its own.
+func decodeUTF16(b []byte, bom bool) string {
+	var out []uint16
+	bigEndian := !bom
+	start := true
+	for i := 0; i+1 < len(b); i += 2 {
+		if bom && start {
+			switch {
+			case b[i] == 0xff && b[i+1] == 0xfe:
+				bigEndian = false
+				start = false
+				continue
+			case b[i] == 0xfe && b[i+1] == 0xff:
+				bigEndian = true
+				start = false
+				continue
+			}
+		}
+		start = false
+		var u uint16
+		if bigEndian {
+			u = uint16(b[i])<<8 | uint16(b[i+1])
+		} else {
+			u = uint16(b[i+1])<<8 | uint16(b[i])
+		}
+		out = append(out, u)
+		if u == 0 {
+			start = true
+		}
+	}
+	return string(utf16.Decode(out))
+}
+
+// splitTerminated splits b at the first string terminator for the given
+// encoding and returns the decoded string and the remaining bytes.
+func splitTerminated(encoding byte, b []byte) (string, []byte) {
+	if encoding == EncodingUTF16 || encoding == EncodingUTF16BE {
+		for i := 0; i+1 < len(b); i += 2 {
+			if b[i] == 0 && b[i+1] == 0 {
+				return decodeText(encoding, b[:i]), b[i+2:]
+			}
+		}
+		return decodeText(encoding, b), nil
+	}
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		return decodeText(encoding, b[:i]), b[i+1:]
+	}
+	return decodeText(encoding, b), nil
+}
@@ -0,0 +1,359 @@
+package id3v2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+// encodeSyncsafe encodes n as a 4-byte syncsafe integer.
+func encodeSyncsafe(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7f), byte(n >> 14 & 0x7f), byte(n >> 7 & 0x7f), byte(n & 0x7f)}
+}
+
+// buildFrame builds a frame with the header layout of the given version.
+func buildFrame(version byte, id string, data []byte) []byte {
+	var b []byte
+	b = append(b, id...)
+	switch version {
+	case 2:
+		b = append(b, byte(len(data)>>16), byte(len(data)>>8), byte(len(data)))
+	case 3:
+		b = binary.BigEndian.AppendUint32(b, uint32(len(data)))
+		b = append(b, 0, 0)
+	default:
+		b = append(b, encodeSyncsafe(len(data))...)
+		b = append(b, 0, 0)
+	}
+	return append(b, data...)
+}
+
+// buildTag builds a tag of the given version holding frames, followed by
+// padding bytes of padding.
+func buildTag(version byte, flags byte, padding int, frames ...[]byte) []byte {
+	var body []byte
+	for _, f := range frames {
+		body = append(body, f...)
+	}
+	body = append(body, make([]byte, padding)...)
+	b := []byte{'I', 'D', '3', version, 0, flags}
+	b = append(b, encodeSyncsafe(len(body))...)
+	return append(b, body...)
+}
+
+func textData(encoding byte, text string) []byte {
+	return append([]byte{encoding}, text...)
+}
+
+func TestParse_V24TextFrames(t *testing.T) {
+	tag := buildTag(4, 0, 32,
+		buildFrame(4, "TIT2", textData(EncodingUTF8, "Eine Kleine Nachtmusik")),
+		buildFrame(4, "TPE1", textData(EncodingISO88591, "Mozart")),
+		buildFrame(4, "TALB", textData(EncodingUTF8, "Serenades")),
+		buildFrame(4, "TRCK", textData(EncodingUTF8, "4/12")),
+		buildFrame(4, "TDRC", textData(EncodingUTF8, "1787")),
+		buildFrame(4, "TCON", textData(EncodingUTF8, "Classical\x00Baroque")),
+	)
+	got, err := Parse(tag)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got.Version != 4 {
+		t.Errorf("Version = %d, want 4", got.Version)
+	}
+	checks := []struct {
+		name, got, want string
+	}{
+		{"Title", got.Title(), "Eine Kleine Nachtmusik"},
+		{"Artist", got.Artist(), "Mozart"},
+		{"Album", got.Album(), "Serenades"},
+		{"Track", got.Track(), "4/12"},
+		{"Date", got.Date(), "1787"},
+		{"Genre", got.Genre(), "Classical"},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s() = %q, want %q", c.name, c.got, c.want)
+		}
+	}
+	if values := got.TextValues("TCON"); len(values) != 2 || values[1] != "Baroque" {
+		t.Errorf("TextValues(TCON) = %q, want [Classical Baroque]", values)
+	}
+	if len(got.Frames) != 6 {
+		t.Errorf("got %d frames, want 6", len(got.Frames))
+	}
+}
+
+func TestParse_V23UTF16(t *testing.T) {
+	// "Été" in UTF-16 little endian with BOM.
+	title := []byte{EncodingUTF16, 0xff, 0xfe, 0xc9, 0x00, 0x74, 0x00, 0xe9, 0x00}
+	tag := buildTag(3, 0, 0,
+		buildFrame(3, "TIT2", title),
+		buildFrame(3, "TYER", textData(EncodingISO88591, "1999")),
+	)
+	got, err := Parse(tag)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got.Title() != "Été" {
+		t.Errorf("Title() = %q, want %q", got.Title(), "Été")
+	}
+	if got.Date() != "1999" {
+		t.Errorf("Date() = %q, want 1999 from TYER", got.Date())
+	}
+}
+
+func TestParse_V22MapsFrameIDs(t *testing.T) {
+	tag := buildTag(2, 0, 0,
+		buildFrame(2, "TT2", textData(EncodingISO88591, "Title")),
+		buildFrame(2, "TP1", textData(EncodingISO88591, "Artist")),
+	)
+	got, err := Parse(tag)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got.Title() != "Title" || got.Artist() != "Artist" {
+		t.Errorf("got title %q artist %q", got.Title(), got.Artist())
+	}
+}
+
+func TestParse_Comment(t *testing.T) {
+	data := append([]byte{EncodingISO88591, 'e', 'n', 'g'}, "desc\x00the comment"...)
+	got, err := Parse(buildTag(3, 0, 0, buildFrame(3, "COMM", data)))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got.Comment() != "the comment" {
+		t.Errorf("Comment() = %q, want %q", got.Comment(), "the comment")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	if _, err := Parse([]byte("not a tag")); !errors.Is(err, ErrNoTag) {
+		t.Errorf("got %v, want ErrNoTag", err)
+	}
+	tag := buildTag(4, 0, 0, buildFrame(4, "TIT2", textData(EncodingUTF8, "x")))
+	if _, err := Parse(tag[:len(tag)-1]); !errors.Is(err, ErrTruncated) {
+		t.Errorf("got %v, want ErrTruncated", err)
+	}
+	tag[3] = 5
+	if _, err := Parse(tag); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("got %v, want ErrUnsupportedVersion", err)
+	}
+}
+
+func TestTagSize(t *testing.T) {
+	tag := buildTag(4, 0, 5, buildFrame(4, "TIT2", textData(EncodingUTF8, "x")))
+	size, err := TagSize(tag)
+	if err != nil {
+		t.Fatalf("TagSize failed: %v", err)
+	}
+	if size != len(tag) {
+		t.Errorf("TagSize = %d, want %d", size, len(tag))
+	}
+	tag[5] |= FlagFooter
+	if size, _ := TagSize(tag); size != len(tag)+HeaderSize {
+		t.Errorf("TagSize with footer = %d, want %d", size, len(tag)+HeaderSize)
+	}
+}
+
+func chapterData(id string, start, end uint32, frames ...[]byte) []byte {
+	b := append([]byte(id), 0)
+	b = binary.BigEndian.AppendUint32(b, start)
+	b = binary.BigEndian.AppendUint32(b, end)
+	b = binary.BigEndian.AppendUint32(b, NoOffset)
+	b = binary.BigEndian.AppendUint32(b, NoOffset)
+	for _, f := range frames {
+		b = append(b, f...)
+	}
+	return b
+}
+
+func tocData(id string, flags byte, children ...string) []byte {
+	b := append([]byte(id), 0, flags, byte(len(children)))
+	for _, c := range children {
+		b = append(b, c...)
+		b = append(b, 0)
+	}
+	return b
+}
+
+func TestChapters(t *testing.T) {
+	tag := buildTag(4, 0, 0,
+		buildFrame(4, "CHAP", chapterData("ch1", 60000, 120000,
+			buildFrame(4, "TIT2", textData(EncodingUTF8, "Second"))),
+		),
+		buildFrame(4, "CHAP", chapterData("ch0", 0, 60000,
+			buildFrame(4, "TIT2", textData(EncodingUTF8, "First"))),
+		),
+	)
+	got, err := Parse(tag)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	chapters := got.Chapters()
+	if len(chapters) != 2 {
+		t.Fatalf("got %d chapters, want 2", len(chapters))
+	}
+	if chapters[0].ID != "ch0" || chapters[0].Title() != "First" {
+		t.Errorf("first chapter = %q %q, want ch0 First", chapters[0].ID, chapters[0].Title())
+	}
+	if chapters[1].Start != time.Minute || chapters[1].End != 2*time.Minute {
+		t.Errorf("second chapter spans %v-%v, want 1m-2m", chapters[1].Start, chapters[1].End)
+	}
+	if chapters[1].StartOffset != NoOffset {
+		t.Errorf("StartOffset = %#x, want NoOffset", chapters[1].StartOffset)
+	}
+	if c := got.Chapter("ch1"); c == nil || c.Title() != "Second" {
+		t.Errorf("Chapter(ch1) = %+v", c)
+	}
+	if got.Chapter("missing") != nil {
+		t.Error("Chapter(missing) should be nil")
+	}
+}
+
+func TestChapters_TableOfContentsOrder(t *testing.T) {
+	tag := buildTag(3, 0, 0,
+		buildFrame(3, "CTOC", tocData("toc", 0x03, "b", "a")),
+		buildFrame(3, "CHAP", chapterData("a", 0, 1000)),
+		buildFrame(3, "CHAP", chapterData("b", 1000, 2000)),
+	)
+	got, err := Parse(tag)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	tocs := got.TablesOfContents()
+	if len(tocs) != 1 || !tocs[0].TopLevel || !tocs[0].Ordered {
+		t.Fatalf("unexpected tables of contents: %+v", tocs)
+	}
+	chapters := got.Chapters()
+	if len(chapters) != 2 || chapters[0].ID != "b" || chapters[1].ID != "a" {
+		t.Errorf("chapters not in table of contents order: %+v", chapters)
+	}
+}
+
+func syncedLyricsData(format byte, lines ...LyricLine) []byte {
+	b := []byte{EncodingUTF8, 'e', 'n', 'g', format, 1, 0}
+	for _, l := range lines {
+		b = append(b, l.Text...)
+		b = append(b, 0)
+		b = binary.BigEndian.AppendUint32(b, l.Timestamp)
+	}
+	return b
+}
+
+func TestLyrics(t *testing.T) {
+	uslt := append([]byte{EncodingISO88591, 'e', 'n', 'g'}, "intro\x00Line one\nLine two"...)
+	tag := buildTag(3, 0, 0, buildFrame(3, "USLT", uslt))
+	got, err := Parse(tag)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	lyrics := got.Lyrics()
+	if len(lyrics) != 1 {
+		t.Fatalf("got %d lyrics, want 1", len(lyrics))
+	}
+	want := Lyrics{Language: "eng", Description: "intro", Text: "Line one\nLine two"}
+	if lyrics[0] != want {
+		t.Errorf("Lyrics() = %+v, want %+v", lyrics[0], want)
+	}
+}
+
+func TestSyncedLyrics(t *testing.T) {
+	tag := buildTag(4, 0, 0, buildFrame(4, "SYLT", syncedLyricsData(TimestampMilliseconds,
+		LyricLine{Timestamp: 2500, Text: "second"},
+		LyricLine{Timestamp: 1000, Text: "first"},
+	)))
+	got, err := Parse(tag)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	all := got.SyncedLyrics()
+	if len(all) != 1 {
+		t.Fatalf("got %d synced lyrics, want 1", len(all))
+	}
+	s := all[0]
+	if s.Language != "eng" || s.ContentType != 1 || len(s.Lines) != 2 {
+		t.Fatalf("unexpected synced lyrics: %+v", s)
+	}
+	if s.Lines[0].Text != "first" || s.Lines[1].Text != "second" {
+		t.Errorf("lines not sorted by timestamp: %+v", s.Lines)
+	}
+
+	tests := []struct {
+		at   time.Duration
+		want int
+	}{
+		{0, -1},
+		{time.Second, 0},
+		{2 * time.Second, 0},
+		{3 * time.Second, 1},
+	}
+	for _, tt := range tests {
+		if got := s.LineAt(tt.at, 0); got != tt.want {
+			t.Errorf("LineAt(%v) = %d, want %d", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestSyncedLyrics_MPEGFrames(t *testing.T) {
+	s := SyncedLyrics{TimestampFormat: TimestampMPEGFrames}
+	frame := 26 * time.Millisecond
+	if got := s.Time(LyricLine{Timestamp: 10}, frame); got != 260*time.Millisecond {
+		t.Errorf("Time = %v, want 260ms", got)
+	}
+}
+
+func TestParse_Unsynchronisation(t *testing.T) {
+	// Version 2.3 unsynchronises the whole tag body, so "\xffA" is stored
+	// as "\xff\x00A".
+	frame := buildFrame(3, "TIT2", textData(EncodingISO88591, "x\xffA"))
+	frame = bytes.ReplaceAll(frame, []byte{0xff}, []byte{0xff, 0x00})
+	tag := buildTag(3, FlagUnsynchronisation, 0, frame)
+	got, err := Parse(tag)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if title := got.Frame("TIT2").Data; string(title) != "\x00x\xffA" {
+		t.Errorf("TIT2 data = %q, want %q", title, "\x00x\xffA")
+	}
+}
+
+func TestParse_V24FrameFlags(t *testing.T) {
+	data := append([]byte{0, 0, 0, 5}, textData(EncodingUTF8, "a\xff\x00b")...)
+	frame := buildFrame(4, "TIT2", data)
+	// Unsynchronisation and data length indicator.
+	frame[9] = 0x03
+	got, err := Parse(buildTag(4, 0, 0, frame))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if title := got.Title(); title != "a\xffb" {
+		t.Errorf("Title() = %q, want %q", title, "a\xffb")
+	}
+}
+
+func TestParse_ExtendedHeader(t *testing.T) {
+	tests := []struct {
+		version byte
+		ext     []byte
+	}{
+		// Size excluding itself, flags and padding size.
+		{3, []byte{0, 0, 0, 6, 0, 0, 0, 0, 0, 0}},
+		// Syncsafe size including itself, flag byte count and flags.
+		{4, []byte{0, 0, 0, 6, 1, 0}},
+	}
+	for _, tt := range tests {
+		frame := buildFrame(tt.version, "TIT2", textData(EncodingUTF8, "Title"))
+		tag := buildTag(tt.version, FlagExtendedHeader, 0, tt.ext, frame)
+		got, err := Parse(tag)
+		if err != nil {
+			t.Fatalf("v2.%d: Parse failed: %v", tt.version, err)
+		}
+		if got.Title() != "Title" {
+			t.Errorf("v2.%d: Title() = %q, want Title", tt.version, got.Title())
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package id3v2
+
+import (
+	"encoding/binary"
+	"sort"
+	"time"
+)
+
+// Timestamp formats of synchronised lyrics.
+const (
+	TimestampMPEGFrames   = 1
+	TimestampMilliseconds = 2
+)
+
+// Lyrics is an unsynchronised lyrics (USLT) frame.
+type Lyrics struct {
+	// Language is the ISO 639-2 language code, such as "eng".
+	Language string
+
+	// Description is the content descriptor, often empty.
+	Description string
+
+	// Text holds the full lyrics.
+	Text string
+}
+
+// A LyricLine is a piece of text of synchronised lyrics.
+type LyricLine struct {
+	// Timestamp is the time the text starts, in the unit given by the
+	// TimestampFormat of the enclosing SyncedLyrics.
+	Timestamp uint32
+
+	// Text is the text of the line. By convention a line starting with
+	// "\n" begins a new line of the display.
+	Text string
+}
+
+// SyncedLyrics is a synchronised lyrics or text (SYLT) frame.
+type SyncedLyrics struct {
+	// Language is the ISO 639-2 language code, such as "eng".
+	Language string
+
+	// Description is the content descriptor, often empty.
+	Description string
+
+	// TimestampFormat is TimestampMPEGFrames or TimestampMilliseconds.
+	TimestampFormat byte
+
+	// ContentType tells what the text is: 1 for lyrics, 2 for a text
+	// transcription, 5 for chords and so on.
+	ContentType byte
+
+	// Lines holds the timestamped text sorted by timestamp.
+	Lines []LyricLine
+}
+
+// Time returns the start time of l. frameDuration is the duration of an MPEG
+// frame of the stream, used when timestamps count frames.
+func (s *SyncedLyrics) Time(l LyricLine, frameDuration time.Duration) time.Duration {
+	if s.TimestampFormat == TimestampMPEGFrames {
+		return time.Duration(l.Timestamp) * frameDuration
+	}
+	return time.Duration(l.Timestamp) * time.Millisecond
+}
+
+// LineAt returns the index of the line being sung at t, or -1 if t is before
+// the first line. frameDuration is used as in Time.
+func (s *SyncedLyrics) LineAt(t, frameDuration time.Duration) int {
+	i := sort.Search(len(s.Lines), func(i int) bool {
+		return s.Time(s.Lines[i], frameDuration) > t
+	})
+	return i - 1
+}
+
+// Lyrics returns the unsynchronised lyrics (USLT) frames of the tag.
+func (t *Tag) Lyrics() []Lyrics {
+	var lyrics []Lyrics
+	for _, f := range t.Frames {
+		if f.ID != "USLT" || len(f.Data) < 4 {
+			continue
+		}
+		encoding := f.Data[0]
+		desc, text := splitTerminated(encoding, f.Data[4:])
+		lyrics = append(lyrics, Lyrics{
+			Language:    string(f.Data[1:4]),
+			Description: desc,
+			Text:        decodeText(encoding, text),
+		})
+	}
+	return lyrics
+}
+
+// SyncedLyrics returns the synchronised lyrics (SYLT) frames of the tag.
+func (t *Tag) SyncedLyrics() []SyncedLyrics {
+	var lyrics []SyncedLyrics
+	for _, f := range t.Frames {
+		if f.ID != "SYLT" {
+			continue
+		}
+		l, ok := parseSyncedLyrics(f.Data)
+		if ok {
+			lyrics = append(lyrics, l)
+		}
+	}
+	return lyrics
+}
+
+func parseSyncedLyrics(b []byte) (SyncedLyrics, bool) {
+	// Encoding byte, 3-byte language code, timestamp format and content
+	// type.
+	if len(b) < 6 {
+		return SyncedLyrics{}, false
+	}
+	encoding := b[0]
+	s := SyncedLyrics{
+		Language:        string(b[1:4]),
+		TimestampFormat: b[4],
+		ContentType:     b[5],
+	}
+	s.Description, b = splitTerminated(encoding, b[6:])
+	for len(b) > 0 {
+		var text string
+		text, b = splitTerminated(encoding, b)
+		if len(b) < 4 {
+			break
+		}
+		s.Lines = append(s.Lines, LyricLine{
+			Timestamp: binary.BigEndian.Uint32(b[:4]),
+			Text:      text,
+		})
+		b = b[4:]
+	}
+	sort.SliceStable(s.Lines, func(i, j int) bool {
+		return s.Lines[i].Timestamp < s.Lines[j].Timestamp
+	})
+	return s, true
+}
@@ -0,0 +1,18 @@
+package mp3
+
+// InputPosition returns the offset in the input of the next byte the
+// decoder parses. It runs ahead of Position by the frame being decoded and
+// any data read ahead, and follows seeks on io.Seeker sources. Tools can use
+// it to relate the state of the decoder to the reads of a network source.
+func (d *Decoder) InputPosition() int64 {
+	return d.source.pos
+}
+
+// InputBytesRead returns the number of bytes the decoder has read from its
+// source so far, such as to estimate the bandwidth or the buffering of a
+// network stream. Unlike InputPosition, it counts again the bytes read again
+// after a seek, and the bytes read by the scan of io.Seeker sources, but
+// not those the scan skips.
+func (d *Decoder) InputBytesRead() int64 {
+	return d.source.consumed
+}
@@ -0,0 +1,54 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+func TestInputPosition(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{Frames: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewDecoder(&nonSeekableReader{bytes.NewReader(data)})
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	// The first frame has been read.
+	if got, want := d.InputPosition(), int64(frameOffset(data, 1)); got != want {
+		t.Errorf("InputPosition() = %d, want %d", got, want)
+	}
+	if d.InputBytesRead() < d.InputPosition() {
+		t.Errorf("InputBytesRead() = %d, less than InputPosition() %d", d.InputBytesRead(), d.InputPosition())
+	}
+	if _, err := io.Copy(io.Discard, d); err != nil {
+		t.Fatalf("decoding failed: %v", err)
+	}
+	if d.InputPosition() != int64(len(data)) || d.InputBytesRead() != int64(len(data)) {
+		t.Errorf("at the end InputPosition() = %d, InputBytesRead() = %d, want %d",
+			d.InputPosition(), d.InputBytesRead(), len(data))
+	}
+
+	// A seek moves the position back, and the frames are read again.
+	d, err = NewDecoder(bytes.NewReader(data), WithReservoirFree())
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if _, err := io.Copy(io.Discard, d); err != nil {
+		t.Fatalf("decoding failed: %v", err)
+	}
+	read := d.InputBytesRead()
+	if _, err := d.Seek(10*1152*4, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	if got, want := d.InputPosition(), int64(frameOffset(data, 11)); got != want {
+		t.Errorf("InputPosition() after Seek = %d, want %d", got, want)
+	}
+	if got, want := d.InputBytesRead(), read+int64(frameOffset(data, 11)-frameOffset(data, 10)); got != want {
+		t.Errorf("InputBytesRead() after Seek = %d, want %d", got, want)
+	}
+}
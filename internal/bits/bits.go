@@ -14,16 +14,25 @@
 
 package bits
 
-import "errors"
+import (
+	"encoding/binary"
+	"errors"
+)
 
 // ErrOutOfBounds is returned when attempting to read past the end of the buffer.
 var ErrOutOfBounds = errors.New("bits: read past end of buffer")
 
+// Bits reads a byte slice bit by bit, most significant bit first. The bits
+// following the position are cached in a 64-bit word, refilled eight bytes
+// at a time, so that reads are shifts of the word.
 type Bits struct {
-	vec     []byte
-	bitPos  int
-	bytePos int
-	err     error
+	vec []byte
+	pos int // position of the next bit
+	err error
+
+	// cache holds n bits from pos, left aligned.
+	cache uint64
+	n     int
 }
 
 // Err returns any error that occurred during bit reading operations.
@@ -42,17 +51,31 @@ func Append(bits *Bits, buf []byte) *Bits {
 	return New(append(bits.vec, buf...))
 }
 
-func (b *Bits) Bit() int {
-	if len(b.vec) <= b.bytePos {
-		b.err = ErrOutOfBounds
-		return 0
+// refill fills the cache with at least 57 bits from pos. The bits past the
+// end of the buffer read as 0. It is kept out of line so that the reads
+// are inlined.
+//
+//go:noinline
+func (b *Bits) refill() {
+	i := b.pos >> 3
+	var w uint64
+	if i+8 <= len(b.vec) {
+		w = binary.BigEndian.Uint64(b.vec[i:])
+	} else {
+		for j := range 8 {
+			w <<= 8
+			if i+j < len(b.vec) {
+				w |= uint64(b.vec[i+j])
+			}
+		}
 	}
-	// bitPos is always 0-7 (controlled by modulo 8 arithmetic), so conversion is safe
-	tmp := uint(b.vec[b.bytePos]) >> (7 - uint(b.bitPos)) //nolint:gosec // bitPos is always 0-7
-	tmp &= 0x01
-	b.bytePos += (b.bitPos + 1) >> 3
-	b.bitPos = (b.bitPos + 1) & 0x07
-	return int(tmp) //nolint:gosec // tmp is always 0-1
+	shift := b.pos & 7
+	b.cache = w << uint(shift) //nolint:gosec // shift is 0-7
+	b.n = 64 - shift
+}
+
+func (b *Bits) Bit() int {
+	return b.Bits(1)
 }
 
 func (b *Bits) Bits(num int) int {
@@ -60,29 +83,27 @@ func (b *Bits) Bits(num int) int {
 		return 0
 	}
 	// Check if we have enough bits remaining
-	currentBitPos := b.bytePos*8 + b.bitPos
-	totalBits := len(b.vec) * 8
-	if currentBitPos+num > totalBits {
+	if b.pos+num > len(b.vec)*8 {
 		b.err = ErrOutOfBounds
 		return 0
 	}
-	bb := make([]byte, 4)
-	copy(bb, b.vec[b.bytePos:])
-	tmp := (uint32(bb[0]) << 24) | (uint32(bb[1]) << 16) | (uint32(bb[2]) << 8) | (uint32(bb[3]))
-	tmp <<= uint(b.bitPos)   //nolint:gosec // bitPos is always 0-7, safe for uint conversion
-	tmp >>= (32 - uint(num)) //nolint:gosec // num is always 0-32 for MP3 parsing
-	b.bytePos += (b.bitPos + num) >> 3
-	b.bitPos = (b.bitPos + num) & 0x07
-	return int(tmp) //nolint:gosec // tmp fits in int after right shift
+	if b.n < num {
+		b.refill()
+	}
+	v := b.cache >> (64 - uint(num)) //nolint:gosec // num is always 1-32 for MP3 parsing
+	b.cache <<= uint(num)            //nolint:gosec // num is always 1-32 for MP3 parsing
+	b.n -= num
+	b.pos += num
+	return int(v) //nolint:gosec // v has at most 32 bits
 }
 
 func (b *Bits) BitPos() int {
-	return b.bytePos<<3 + b.bitPos
+	return b.pos
 }
 
 func (b *Bits) SetPos(pos int) {
-	b.bytePos = pos >> 3
-	b.bitPos = pos & 0x7
+	b.pos = pos
+	b.n = 0
 }
 
 func (b *Bits) LenInBytes() int {
@@ -92,3 +113,35 @@ func (b *Bits) LenInBytes() int {
 func (b *Bits) Tail(offset int) []byte {
 	return b.vec[len(b.vec)-offset:]
 }
+
+// Peek returns the next num bits, up to 32, without consuming them. The bits
+// past the end of the buffer read as 0.
+func (b *Bits) Peek(num int) int {
+	if b.n < num {
+		b.refill()
+	}
+	return int(b.cache >> (64 - uint(num))) //nolint:gosec // num is always 1-32
+}
+
+// Skip consumes num bits. Skipping past the end of the buffer stops at the
+// end and reports ErrOutOfBounds, like reading the bits one by one.
+func (b *Bits) Skip(num int) {
+	pos := b.pos + num
+	if total := len(b.vec) * 8; pos > total {
+		b.err = ErrOutOfBounds
+		pos = total
+	}
+	if num := pos - b.pos; num <= b.n {
+		b.cache <<= uint(num) //nolint:gosec // num is 0-64
+		b.n -= num
+		b.pos = pos
+		return
+	}
+	b.SetPos(pos)
+}
+
+// Reset makes b read vec from its start, so that a Bits can be reused
+// without allocating.
+func (b *Bits) Reset(vec []byte) {
+	*b = Bits{vec: vec}
+}
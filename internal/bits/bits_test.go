@@ -110,3 +110,87 @@ func TestBits(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestPeek(t *testing.T) {
+	b := bits.New([]byte{0xAB, 0xCD, 0xEF})
+	b.Skip(4)
+	if got := b.Peek(12); got != 0xBCD {
+		t.Errorf("Peek(12) = %#x, want 0xbcd", got)
+	}
+	// Bits past the end read as 0.
+	if got := b.Peek(24); got != 0xBCDEF0 {
+		t.Errorf("Peek(24) = %#x, want 0xbcdef0", got)
+	}
+	if b.BitPos() != 4 || b.Err() != nil {
+		t.Errorf("Peek moved to bit %d (err %v), want 4", b.BitPos(), b.Err())
+	}
+}
+
+func TestSkip_OutOfBounds_ShouldReportError(t *testing.T) {
+	b := bits.New([]byte{0xAB, 0xCD})
+	b.Skip(16)
+	if b.Err() != nil {
+		t.Fatalf("unexpected error skipping to the end: %v", b.Err())
+	}
+	b.Skip(1)
+	if b.Err() == nil {
+		t.Error("expected error after skipping past buffer")
+	}
+	if b.BitPos() != 16 {
+		t.Errorf("BitPos() = %d, want 16", b.BitPos())
+	}
+}
+
+func TestBits_MatchesBitByBit(t *testing.T) {
+	data := make([]byte, 64)
+	for i := range data {
+		data[i] = byte(i*37 + 11)
+	}
+	bitAt := func(pos int) int {
+		return int(data[pos/8]>>(7-pos%8)) & 1
+	}
+	b := bits.New(data)
+	pos := 0
+	for n := 1; pos+n <= len(data)*8; n = n%32 + 1 {
+		if n%7 == 0 {
+			// Jump back and forth, which drops the cache.
+			pos -= 5
+			b.SetPos(pos)
+		}
+		want := 0
+		for i := range n {
+			want = want<<1 | bitAt(pos+i)
+		}
+		if n%5 == 0 {
+			if got := b.Peek(n); got != want {
+				t.Fatalf("Peek(%d) at bit %d = %#x, want %#x", n, pos, got, want)
+			}
+			b.Skip(n)
+		} else if got := b.Bits(n); got != want {
+			t.Fatalf("Bits(%d) at bit %d = %#x, want %#x", n, pos, got, want)
+		}
+		pos += n
+		if b.BitPos() != pos {
+			t.Fatalf("BitPos() = %d, want %d", b.BitPos(), pos)
+		}
+	}
+	if b.Err() != nil {
+		t.Errorf("unexpected error: %v", b.Err())
+	}
+}
+
+func BenchmarkBits(b *testing.B) {
+	data := make([]byte, 4096)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	for b.Loop() {
+		m := bits.New(data)
+		for m.BitPos()+32 <= len(data)*8 {
+			m.Bits(1)
+			m.Bits(4)
+			m.Bits(9)
+			m.Bits(12)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"slices"
 
 	"github.com/llehouerou/go-mp3/internal/bits"
 	"github.com/llehouerou/go-mp3/internal/consts"
@@ -47,15 +48,17 @@ type Frame struct {
 	mainDataBits *bits.Bits
 	store        [2][32][18]float32
 	vVec         [2][1024]float32
+
+	// crc is scratch space for the CRC, which is read but not checked.
+	crc [2]byte
 }
 
 type FullReader interface {
 	ReadFull([]byte) (int, error)
 }
 
-func readCRC(source FullReader) error {
-	buf := make([]byte, 2)
-	if n, err := source.ReadFull(buf); n < 2 {
+func readCRC(source FullReader, buf *[2]byte) error {
+	if n, err := source.ReadFull(buf[:]); n < 2 {
 		if errors.Is(err, io.EOF) {
 			return &consts.UnexpectedEOFError{At: "readCRC"}
 		}
@@ -64,15 +67,63 @@ func readCRC(source FullReader) error {
 	return nil
 }
 
+// TruncatedError is returned by Read when the source ends within the frame
+// starting at Position, after its header.
+type TruncatedError struct {
+	Position int64
+	Err      *consts.UnexpectedEOFError
+}
+
+func (e *TruncatedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TruncatedError) Unwrap() error {
+	return e.Err
+}
+
+// truncated returns err as a *TruncatedError if the source ended within the
+// frame at pos.
+func truncated(err error, pos int64) error {
+	var eof *consts.UnexpectedEOFError
+	if errors.As(err, &eof) {
+		return &TruncatedError{Position: pos, Err: eof}
+	}
+	return err
+}
+
+// Read reads the frame at position from source. prev is the previous frame
+// of the stream, if any, which supplies the bit reservoir and the synthesis
+// filterbank state. On success, prev is reused for the returned frame; on
+// error, it is left untouched.
 func Read(source FullReader, position int64, prev *Frame) (frame *Frame, startPosition int64, err error) {
+	return read(source, position, prev, false)
+}
+
+// ReadSelfContained is like Read but ignores the bit reservoir of prev, so
+// that the result only depends on the bytes of the frame itself. Granules
+// whose data starts in a previous frame decode as silence. prev still
+// provides the synthesis filterbank state.
+func ReadSelfContained(source FullReader, position int64, prev *Frame) (frame *Frame, startPosition int64, err error) {
+	return read(source, position, prev, true)
+}
+
+func read(source FullReader, position int64, prev *Frame, selfContained bool) (frame *Frame, startPosition int64, err error) {
 	h, pos, err := frameheader.Read(source, position)
 	if err != nil {
 		return nil, 0, err
 	}
 
+	// The new frame takes over prev, whose synthesis state it carries on,
+	// rather than copying the state into a new frame.
+	nf := prev
+	if nf == nil {
+		nf = &Frame{}
+	}
+
 	if h.ProtectionBit() == 0 {
-		if err := readCRC(source); err != nil {
-			return nil, 0, err
+		if err := readCRC(source, &nf.crc); err != nil {
+			return nil, 0, truncated(err, pos)
 		}
 	}
 
@@ -83,9 +134,13 @@ func Read(source FullReader, position int64, prev *Frame) (frame *Frame, startPo
 		return nil, 0, fmt.Errorf("mp3: only layer3 (want %d; got %d) is supported", consts.Layer3, h.Layer())
 	}
 
-	si, err := sideinfo.Read(source, h)
+	var reuseSideInfo *sideinfo.SideInfo
+	if prev != nil {
+		reuseSideInfo = prev.sideInfo
+	}
+	si, err := sideinfo.Read(source, h, reuseSideInfo)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, truncated(err, pos)
 	}
 
 	// If there's not enough main data in the bit reservoir,
@@ -97,30 +152,124 @@ func Read(source FullReader, position int64, prev *Frame) (frame *Frame, startPo
 		prevM = prev.mainDataBits
 		reuseMainData = prev.mainData
 	}
-	md, mdb, err := maindata.Read(source, prevM, h, si, reuseMainData)
+	var md *maindata.MainData
+	var mdb *bits.Bits
+	if selfContained {
+		md, mdb, err = maindata.ReadSelfContained(source, h, si, reuseMainData)
+	} else {
+		md, mdb, err = maindata.Read(source, prevM, h, si, reuseMainData)
+	}
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, truncated(err, pos)
 	}
-	nf := &Frame{
-		header:       h,
-		sideInfo:     si,
-		mainData:     md,
-		mainDataBits: mdb,
+	nf.header = h
+	nf.sideInfo = si
+	nf.mainData = md
+	nf.mainDataBits = mdb
+	return nf, pos, nil
+}
+
+// State returns a copy of the inter-frame decoding state of f: the bit
+// reservoir and the synthesis filterbank memory. Passing the result as prev
+// to Read leaves f untouched, unlike passing f itself, whose buffers may be
+// reused.
+func (f *Frame) State() *Frame {
+	s := *f
+	s.sideInfo = nil
+	s.mainData = nil
+	// The bits of f are in a buffer of its main data, which is reused.
+	if m := f.mainDataBits; m != nil {
+		s.mainDataBits = bits.New(slices.Clone(m.Tail(m.LenInBytes())))
 	}
-	if prev != nil {
-		nf.store = prev.store
-		nf.vVec = prev.vVec
+	return &s
+}
+
+// MainDataSize returns the size in bytes of the main data buffer of f, which
+// holds the bit reservoir of the previous frames and the main data of f.
+func (f *Frame) MainDataSize() int {
+	if f.mainDataBits == nil {
+		return 0
 	}
-	return nf, pos, nil
+	return f.mainDataBits.LenInBytes()
 }
 
 func (f *Frame) SamplingFrequency() (int, error) {
 	return f.header.SamplingFrequencyValue()
 }
 
-func (f *Frame) Decode() []byte {
-	out := make([]byte, f.header.BytesPerFrame())
+// Header returns the header of the frame.
+func (f *Frame) Header() frameheader.FrameHeader {
+	return f.header
+}
+
+// MaxSamples is the largest number of samples per channel in a frame.
+const MaxSamples = consts.SamplesPerGr * consts.GranulesMpeg1
+
+// PCM holds the decoded samples of a frame, one slice per channel. Samples
+// are nominally in the range [-1, 1] but are not clipped.
+type PCM [2][MaxSamples]float32
+
+// Analyze decodes the frame up to the stereo processing, without the
+// antialiasing and the synthesis filterbank, and returns the number of
+// granules. The frequency lines are then available from Lines.
+//
+// The synthesis state is not updated, so the PCM of frames decoded next
+// with DecodeFloat glitches for a granule or two.
+func (f *Frame) Analyze() int {
+	for gr := range f.header.Granules() {
+		for ch := range f.header.NumberOfChannels() {
+			f.requantize(gr, ch)
+			f.reorder(gr, ch)
+		}
+		f.stereo(gr)
+	}
+	return f.header.Granules()
+}
+
+// Lines returns the 576 frequency lines of granule gr and channel ch
+// computed by Analyze. Lines of short blocks are in frequency order, with the
+// three windows interleaved.
+func (f *Frame) Lines(gr, ch int) *[consts.SamplesPerGr]float32 {
+	return &f.mainData.Is[gr][ch]
+}
+
+// ShortBlocks reports whether granule gr of channel ch uses short blocks,
+// possibly mixed with long blocks in the two lowest subbands.
+func (f *Frame) ShortBlocks(gr, ch int) bool {
+	return f.sideInfo.WinSwitchFlag[gr][ch] == 1 && f.sideInfo.BlockType[gr][ch] == 2
+}
+
+// Equalizer holds the gains applied to the 32 subbands of each channel in
+// the synthesis filterbank.
+type Equalizer [2][32]float32
+
+// Synthesis holds options of the synthesis of decoded samples.
+type Synthesis struct {
+	// Equalizer, unless nil, scales the subband samples.
+	Equalizer *Equalizer
+
+	// HalfRate synthesizes only the lower 16 subbands and outputs half the
+	// samples, at half the sample rate.
+	HalfRate bool
+
+	// Lines, unless nil, is called with the frequency lines of each granule
+	// and channel before the synthesis, in the same order as Lines.
+	Lines func(gr, ch int, lines *[consts.SamplesPerGr]float32)
+}
+
+// DecodeFloat decodes the frame into out and returns the number of samples
+// per channel. Only the first NumberOfChannels channels of out are written.
+func (f *Frame) DecodeFloat(out *PCM) int {
+	return f.DecodeFloatWith(out, Synthesis{})
+}
+
+// DecodeFloatWith is like DecodeFloat with synthesis options.
+func (f *Frame) DecodeFloatWith(out *PCM, s Synthesis) int {
 	nch := f.header.NumberOfChannels()
+	sblimit, outPerGr := 32, consts.SamplesPerGr
+	if s.HalfRate {
+		sblimit, outPerGr = 16, consts.SamplesPerGr/2
+	}
 	for gr := range f.header.Granules() {
 		for ch := range nch {
 			f.requantize(gr, ch)
@@ -128,13 +277,58 @@ func (f *Frame) Decode() []byte {
 		}
 		f.stereo(gr)
 		for ch := range nch {
+			if s.Lines != nil {
+				s.Lines(gr, ch, &f.mainData.Is[gr][ch])
+			}
 			f.antialias(gr, ch)
-			f.hybridSynthesis(gr, ch)
+			f.hybridSynthesis(gr, ch, sblimit)
 			f.frequencyInversion(gr, ch)
-			f.subbandSynthesis(gr, ch, out[consts.SamplesPerGr*4*gr:])
+			var gains *[32]float32
+			if s.Equalizer != nil {
+				gains = &s.Equalizer[ch]
+			}
+			if s.HalfRate {
+				f.subbandSynthesisHalf(gr, ch, out[ch][outPerGr*gr:], gains)
+			} else {
+				f.subbandSynthesis(gr, ch, out[ch][outPerGr*gr:], gains)
+			}
 		}
 	}
-	return out
+	return outPerGr * f.header.Granules()
+}
+
+// Decode decodes the frame into 16-bit little endian stereo PCM. Mono
+// frames are duplicated into both channels.
+func (f *Frame) Decode() []byte {
+	var pcm PCM
+	n := f.DecodeFloat(&pcm)
+	if f.header.NumberOfChannels() == 1 {
+		pcm[1] = pcm[0]
+	}
+	return AppendS16(make([]byte, 0, n*4), &pcm, n)
+}
+
+// S16 converts a sample to a 16-bit integer, truncating toward zero and
+// clipping to [-32767, 32767].
+func S16(sample float32) int16 {
+	samp := int(sample * 32767)
+	if samp > 32767 {
+		samp = 32767
+	} else if samp < -32767 {
+		samp = -32767
+	}
+	return int16(samp) //nolint:gosec // samp is clamped to [-32767, 32767] above
+}
+
+// AppendS16 appends the first n samples of both channels of pcm to buf as
+// interleaved 16-bit little endian stereo and returns the extended buffer.
+func AppendS16(buf []byte, pcm *PCM, n int) []byte {
+	for i := range n {
+		l := S16(pcm[0][i])
+		r := S16(pcm[1][i])
+		buf = append(buf, byte(l), byte(l>>8), byte(r), byte(r>>8))
+	}
+	return buf
 }
 
 func (f *Frame) requantizeProcessLong(gr, ch, isPos, sfb int) {
@@ -255,7 +449,7 @@ func (f *Frame) requantize(gr, ch int) {
 }
 
 func (f *Frame) reorder(gr, ch int) {
-	re := make([]float32, consts.SamplesPerGr)
+	var re [consts.SamplesPerGr]float32
 
 	_, sfBandIndicesShort := getSfBandIndicesArray(&f.header)
 
@@ -451,13 +645,13 @@ func (f *Frame) antialias(gr, ch int) {
 	}
 }
 
-func (f *Frame) hybridSynthesis(gr, ch int) {
+// hybridSynthesis runs the IMDCT of the subbands below sblimit.
+func (f *Frame) hybridSynthesis(gr, ch, sblimit int) {
 	// Scratch buffers reused across all subbands (stack-allocated)
 	var in [18]float32
 	var rawout [36]float32
 
-	// Loop through all 32 subbands
-	for sb := range 32 {
+	for sb := range sblimit {
 		// Determine blocktype for this subband
 		bt := f.sideInfo.BlockType[gr][ch]
 		if (f.sideInfo.WinSwitchFlag[gr][ch] == 1) &&
@@ -468,7 +662,7 @@ func (f *Frame) hybridSynthesis(gr, ch int) {
 		for i := range in {
 			in[i] = f.mainData.Is[gr][ch][sb*18+i]
 		}
-		imdct.Win(rawout[:], in[:], bt)
+		imdct.Win(&rawout, &in, bt)
 		// Overlap add with stored vector into main_data vector
 		for i := range 18 {
 			f.mainData.Is[gr][ch][sb*18+i] = rawout[i] + f.store[ch][sb][i] //nolint:gosec // i is bounded by range 18, rawout is [36]float32
@@ -627,11 +821,10 @@ var synthDtbl = [512]float32{
 	0.000015259, 0.000015259, 0.000015259, 0.000015259,
 }
 
-func (f *Frame) subbandSynthesis(gr, ch int, out []byte) {
-	uVec := make([]float32, 512)
-	sVec := make([]float32, 32)
+func (f *Frame) subbandSynthesis(gr, ch int, out []float32, gains *[32]float32) {
+	var uVec [512]float32
+	var sVec [32]float32
 
-	nch := f.header.NumberOfChannels()
 	// Setup the n_win windowing vector and the vVec intermediate vector
 	for ss := range 18 { // Loop through 18 samples in 32 subbands
 		copy(f.vVec[ch][64:1024], f.vVec[ch][0:1024-64])
@@ -639,6 +832,11 @@ func (f *Frame) subbandSynthesis(gr, ch int, out []byte) {
 		for i := range 32 { // Copy next 32 time samples to a temp vector
 			sVec[i] = d[i*18+ss] //nolint:gosec // i is 0-31 and ss is 0-17, so max index is 31*18+17=575 < 576
 		}
+		if gains != nil {
+			for i := range 32 {
+				sVec[i] *= gains[i]
+			}
+		}
 		for i := range 64 { // Matrix multiply input with n_win[][] matrix
 			sum := float32(0)
 			for j := range 32 {
@@ -659,30 +857,45 @@ func (f *Frame) subbandSynthesis(gr, ch int, out []byte) {
 			for j := 0; j < 512; j += 32 {
 				sum += uVec[j+i]
 			}
-			// sum now contains time sample 32*ss+i. Convert to 16-bit signed int
-			samp := int(sum * 32767)
-			if samp > 32767 {
-				samp = 32767
-			} else if samp < -32767 {
-				samp = -32767
+			// sum now contains time sample 32*ss+i
+			out[32*ss+i] = sum
+		}
+	}
+}
+
+// subbandSynthesisHalf is like subbandSynthesis but synthesizes only the
+// lower 16 subbands and outputs every other sample. The upper subbands being
+// left out, the decimation does not alias.
+func (f *Frame) subbandSynthesisHalf(gr, ch int, out []float32, gains *[32]float32) {
+	var sVec [16]float32
+	d := f.mainData.Is[gr][ch]
+	for ss := range 18 {
+		copy(f.vVec[ch][64:1024], f.vVec[ch][0:1024-64])
+		for i := range 16 {
+			sVec[i] = d[i*18+ss] //nolint:gosec // i is 0-15 and ss is 0-17, so max index is 15*18+17=287 < 576
+		}
+		if gains != nil {
+			for i := range 16 {
+				sVec[i] *= gains[i]
 			}
-			s := int16(samp) //nolint:gosec // samp is clamped to [-32767, 32767] above
-			idx := 4 * (32*ss + i)
-			if nch == 1 {
-				// We always run in stereo mode and duplicate channels here for mono.
-				out[idx] = byte(s)
-				out[idx+1] = byte(s >> 8)
-				out[idx+2] = byte(s)
-				out[idx+3] = byte(s >> 8)
-				continue
+		}
+		for i := range 64 {
+			sum := float32(0)
+			for j := range 16 {
+				sum += synthNWin[i][j] * sVec[j]
 			}
-			if ch == 0 {
-				out[idx] = byte(s)
-				out[idx+1] = byte(s >> 8)
-			} else {
-				out[idx+2] = byte(s)
-				out[idx+3] = byte(s >> 8)
+			f.vVec[ch][i] = sum
+		}
+		v := &f.vVec[ch]
+		for i := 0; i < 32; i += 2 {
+			// Window the U vector of subbandSynthesis, reading it from the
+			// V vector: U[32q+i] is V[64q+i] for even q and V[64q+32+i] for
+			// odd q.
+			sum := float32(0)
+			for q := range 16 {
+				sum += v[64*q+32*(q&1)+i] * synthDtbl[32*q+i]
 			}
+			out[16*ss+i/2] = sum
 		}
 	}
 }
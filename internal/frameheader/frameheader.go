@@ -83,8 +83,8 @@ func (f FrameHeader) Mode() consts.Mode {
 	return consts.Mode((f & 0x000000c0) >> 6)
 }
 
-// modeExtension returns the mode_extension - for use with Joint Stereo - stored in position 4,5
-func (f FrameHeader) modeExtension() int {
+// ModeExtension returns the mode_extension - for use with Joint Stereo - stored in position 4,5
+func (f FrameHeader) ModeExtension() int {
 	return int(f&0x00000030) >> 4
 }
 
@@ -93,7 +93,7 @@ func (f FrameHeader) UseMSStereo() bool {
 	if f.Mode() != consts.ModeJointStereo {
 		return false
 	}
-	return f.modeExtension()&0x2 != 0
+	return f.ModeExtension()&0x2 != 0
 }
 
 // UseIntensityStereo returns a boolean value indicating whether the frame uses intensity stereo.
@@ -101,7 +101,7 @@ func (f FrameHeader) UseIntensityStereo() bool {
 	if f.Mode() != consts.ModeJointStereo {
 		return false
 	}
-	return f.modeExtension()&0x1 != 0
+	return f.ModeExtension()&0x1 != 0
 }
 
 // Copyright returns whether or not this recording is copywritten - stored in position 3
@@ -272,6 +272,40 @@ func (e *SyncSearchLimitError) Error() string {
 	return fmt.Sprintf("mp3: no valid frame header found within %d bytes", e.BytesSearched)
 }
 
+// skipID3v2 skips the rest of an ID3v2 tag whose first 4 bytes were just
+// read, and returns the size of the whole tag. If the following bytes turn
+// out not to be a tag header, it returns 0 and the 6 bytes it consumed.
+func skipID3v2(source FullReader) (int64, []byte, error) {
+	// Revision, flags and syncsafe size.
+	rest := make([]byte, 6)
+	if _, err := source.ReadFull(rest); err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, nil, &consts.UnexpectedEOFError{At: "skipID3v2 (1)"}
+		}
+		return 0, nil, err
+	}
+	if rest[2]|rest[3]|rest[4]|rest[5] >= 0x80 {
+		return 0, rest, nil
+	}
+	size := int64(rest[2])<<21 | int64(rest[3])<<14 | int64(rest[4])<<7 | int64(rest[5])
+	if rest[1]&0x10 != 0 {
+		// Footer.
+		size += 10
+	}
+	buf := make([]byte, min(size, 4096))
+	for remaining := size; remaining > 0; {
+		n := min(remaining, int64(len(buf)))
+		if _, err := source.ReadFull(buf[:n]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, nil, &consts.UnexpectedEOFError{At: "skipID3v2 (2)"}
+			}
+			return 0, nil, err
+		}
+		remaining -= n
+	}
+	return 10 + size, nil, nil
+}
+
 type FullReader interface {
 	ReadFull([]byte) (int, error)
 }
@@ -296,6 +330,40 @@ func Read(source FullReader, position int64) (h FrameHeader, startPosition int64
 	header := FrameHeader((b1 << 24) | (b2 << 16) | (b3 << 8) | (b4 << 0))
 	bytesSearched := int64(4)
 	for !header.IsValid() {
+		if b1 == 'I' && b2 == 'D' && b3 == '3' && b4 >= 2 && b4 <= 4 {
+			// An ID3v2 tag between frames, as found in streams and
+			// concatenated files. Skip it as a whole rather than scanning
+			// its body, which does not count against the search limit.
+			skipped, consumed, err := skipID3v2(source)
+			if err != nil {
+				return 0, 0, err
+			}
+			if skipped == 0 {
+				// Not a tag after all; resume the search after the bytes
+				// that were read.
+				b1, b2, b3, b4 = uint32(consumed[2]), uint32(consumed[3]), uint32(consumed[4]), uint32(consumed[5])
+				header = FrameHeader((b1 << 24) | (b2 << 16) | (b3 << 8) | (b4 << 0))
+				position += int64(len(consumed))
+				bytesSearched += int64(len(consumed))
+				continue
+			}
+			position += skipped
+			n, err := source.ReadFull(buf)
+			if n < 4 {
+				if errors.Is(err, io.EOF) {
+					if n == 0 {
+						// The tag ends the stream.
+						return 0, 0, io.EOF
+					}
+					return 0, 0, &consts.UnexpectedEOFError{At: "readHeader (3)"}
+				}
+				return 0, 0, err
+			}
+			b1, b2, b3, b4 = uint32(buf[0]), uint32(buf[1]), uint32(buf[2]), uint32(buf[3])
+			header = FrameHeader((b1 << 24) | (b2 << 16) | (b3 << 8) | (b4 << 0))
+			continue
+		}
+
 		if bytesSearched >= MaxSyncSearchBytes {
 			return 0, 0, &SyncSearchLimitError{BytesSearched: bytesSearched}
 		}
@@ -284,3 +284,49 @@ func TestRead_SkipsNonLayer3Headers(t *testing.T) {
 		t.Errorf("Read() header = 0x%08X, want 0x%08X", header, layer3Header)
 	}
 }
+
+func TestRead_SkipsID3v2Tag(t *testing.T) {
+	// A tag larger than the sync search limit, followed by a frame header.
+	tagBody := 100 * 1024
+	data := []byte{'I', 'D', '3', 4, 0, 0,
+		byte(tagBody >> 21 & 0x7f), byte(tagBody >> 14 & 0x7f), byte(tagBody >> 7 & 0x7f), byte(tagBody & 0x7f)}
+	data = append(data, make([]byte, tagBody)...)
+	data = append(data, 0xFF, 0xFB, 0x90, 0x44)
+
+	reader := &mockReader{data: data}
+	header, pos, err := Read(reader, 0)
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if want := int64(10 + tagBody); pos != want {
+		t.Errorf("Read() position = %d, want %d", pos, want)
+	}
+	if header != 0xFFFB9044 {
+		t.Errorf("Read() header = 0x%08X, want 0xFFFB9044", uint32(header))
+	}
+}
+
+func TestRead_ID3v2TagAtEnd(t *testing.T) {
+	data := []byte{'I', 'D', '3', 3, 0, 0, 0, 0, 0, 20}
+	data = append(data, make([]byte, 20)...)
+
+	reader := &mockReader{data: data}
+	if _, _, err := Read(reader, 0); !errors.Is(err, io.EOF) {
+		t.Errorf("Read() error = %v, want io.EOF", err)
+	}
+}
+
+func TestRead_FalseID3v2Marker(t *testing.T) {
+	// "ID3" followed by bytes that are not a valid syncsafe size.
+	data := []byte{'I', 'D', '3', 3, 0, 0, 0xFF, 0xFF, 0xFF, 0xFF, 0, 0}
+	data = append(data, 0xFF, 0xFB, 0x90, 0x44)
+
+	reader := &mockReader{data: data}
+	_, pos, err := Read(reader, 0)
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if pos != 12 {
+		t.Errorf("Read() position = %d, want 12", pos)
+	}
+}
@@ -346,44 +346,16 @@ var huffmanMain = [...]huffTables{
 }
 
 func Decode(m *bits.Bits, tableNum int) (x, y, v, w int, err error) {
-	point := 0
-	decodeError := 1
-	bitsleft := 32
-	treelen := huffmanMain[tableNum].treelen
-	linbits := huffmanMain[tableNum].linbits
-	if treelen == 0 { // Check for empty tables
+	if huffmanMain[tableNum].treelen == 0 { // Check for empty tables
 		return 0, 0, 0, 0, nil
 	}
-	htptr := huffmanMain[tableNum].hufftable
-	for { // Start reading the Huffman code word,bit by bit
-		// Check if we've matched a code word
-		if (htptr[point] & 0xff00) == 0 {
-			decodeError = 0
-			x = int((htptr[point] >> 4) & 0xf)
-			y = int(htptr[point] & 0xf)
-			break
-		}
-		if m.Bit() != 0 { // Go right in tree
-			for (htptr[point] & 0xff) >= 250 {
-				point += int(htptr[point]) & 0xff
-			}
-			point += int(htptr[point]) & 0xff
-		} else { // Go left in tree
-			for (htptr[point] >> 8) >= 250 {
-				point += int(htptr[point]) >> 8
-			}
-			point += int(htptr[point]) >> 8
-		}
-		bitsleft--
-		if bitsleft <= 0 || point >= treelen {
-			break
-		}
-	}
-	if decodeError != 0 { // Check for error.
-		err := fmt.Errorf("mp3: illegal Huff code in data, bleft = %d, point = %d, tab = %d",
-			bitsleft, point, tableNum)
-		return 0, 0, 0, 0, err
+	linbits := huffmanMain[tableNum].linbits
+	value, ok := lookupTables[tableNum].decode(m)
+	if !ok {
+		return 0, 0, 0, 0, fmt.Errorf("mp3: illegal Huff code in data, tab = %d", tableNum)
 	}
+	x = (value >> 4) & 0xf
+	y = value & 0xf
 	if tableNum > 31 { // Process sign encodings for quadruples tables.
 		v = (y >> 3) & 1
 		w = (y >> 2) & 1
@@ -0,0 +1,89 @@
+package huffman
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/internal/bits"
+)
+
+// treeDecode reads a code word from m by walking the tree of table tableNum
+// bit by bit.
+func treeDecode(m *bits.Bits, tableNum int) (value int, ok bool) {
+	htptr := huffmanMain[tableNum].hufftable
+	treelen := huffmanMain[tableNum].treelen
+	point := 0
+	for range 32 {
+		if htptr[point]&0xff00 == 0 {
+			return int(htptr[point] & 0xff), true
+		}
+		if m.Bit() != 0 {
+			for htptr[point]&0xff >= 250 {
+				point += int(htptr[point]) & 0xff
+			}
+			point += int(htptr[point]) & 0xff
+		} else {
+			for htptr[point]>>8 >= 250 {
+				point += int(htptr[point]) >> 8
+			}
+			point += int(htptr[point]) >> 8
+		}
+		if point >= treelen {
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+func TestLookupTables_MatchTrees(t *testing.T) {
+	data := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(data)
+	for tableNum, h := range huffmanMain {
+		if h.treelen == 0 {
+			continue
+		}
+		// Decoding runs past the end of the data, which reads as 0.
+		want := bits.New(data)
+		got := bits.New(data)
+		for want.Err() == nil {
+			wv, wok := treeDecode(want, tableNum)
+			gv, gok := lookupTables[tableNum].decode(got)
+			if gv != wv || gok != wok {
+				t.Fatalf("table %d at bit %d: got %#x, %v, want %#x, %v", tableNum, want.BitPos(), gv, gok, wv, wok)
+			}
+			if got.BitPos() != want.BitPos() || (got.Err() == nil) != (want.Err() == nil) {
+				t.Fatalf("table %d: at bit %d (err %v), want bit %d (err %v)",
+					tableNum, got.BitPos(), got.Err(), want.BitPos(), want.Err())
+			}
+		}
+	}
+}
+
+func TestTreeCodes_Complete(t *testing.T) {
+	for tableNum, h := range huffmanMain {
+		if h.treelen == 0 {
+			continue
+		}
+		// Kraft's sum of the code words of a complete tree is 1.
+		var sum float64
+		for _, c := range treeCodes(h.hufftable, h.treelen) {
+			sum += 1 / float64(uint64(1)<<c.len)
+		}
+		if sum != 1 {
+			t.Errorf("table %d: Kraft sum %v, want 1", tableNum, sum)
+		}
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	data := make([]byte, 1<<16)
+	rand.New(rand.NewSource(1)).Read(data)
+	for b.Loop() {
+		m := bits.New(data)
+		for m.Err() == nil {
+			if _, _, _, _, err := Decode(m, 24); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package huffman
+
+import (
+	"sort"
+
+	"github.com/llehouerou/go-mp3/internal/bits"
+)
+
+// The trees of huffmanTable are turned into multi-level lookup tables at
+// init, as mpg123 and minimp3 do, so that a code word is decoded with one
+// or two table lookups instead of a walk of the tree bit by bit.
+//
+// A table indexed by the next n bits holds 1<<n entries. A leaf entry holds
+// the value of the code word (x<<4 | y) in its low byte and the length of
+// the rest of the code word, at most n, in bits 8-12. A code word longer
+// than n bits points to a subtable indexed by the following bits: bit 31 is
+// set, bits 16-20 hold the index size of the subtable and the low 16 bits
+// its position. An entry of 0 is not a valid code word.
+const (
+	lookupBits    = 8
+	lookupSubflag = 1 << 31
+)
+
+type lookupTable struct {
+	entries  []uint32
+	rootBits int
+}
+
+var lookupTables [len(huffmanMain)]lookupTable
+
+func init() {
+	built := map[*uint16]lookupTable{}
+	for i, h := range huffmanMain {
+		if h.treelen == 0 {
+			continue
+		}
+		// Tables differing only by linbits share their tree.
+		t, ok := built[&h.hufftable[0]]
+		if !ok {
+			t = newLookupTable(treeCodes(h.hufftable, h.treelen))
+			built[&h.hufftable[0]] = t
+		}
+		lookupTables[i] = t
+	}
+}
+
+// A codeWord is a code word of a Huffman tree.
+type codeWord struct {
+	bits  uint32
+	len   int
+	value uint8
+}
+
+// treeCodes returns the code words of the tree of htptr, walking it the
+// way the decoder of PDMP3 does: paths leading out of the tree are not code
+// words, nor are code words longer than 31 bits.
+func treeCodes(htptr []uint16, treelen int) []codeWord {
+	var codes []codeWord
+	var walk func(point int, code uint32, n int)
+	walk = func(point int, code uint32, n int) {
+		if htptr[point]&0xff00 == 0 {
+			codes = append(codes, codeWord{bits: code, len: n, value: uint8(htptr[point])}) //nolint:gosec // Leaves hold a byte
+			return
+		}
+		if n == 31 {
+			return
+		}
+		// Go left in tree
+		p := point
+		for htptr[p]>>8 >= 250 {
+			p += int(htptr[p]) >> 8
+		}
+		if p += int(htptr[p]) >> 8; p < treelen {
+			walk(p, code<<1, n+1)
+		}
+		// Go right in tree
+		p = point
+		for htptr[p]&0xff >= 250 {
+			p += int(htptr[p]) & 0xff
+		}
+		if p += int(htptr[p]) & 0xff; p < treelen {
+			walk(p, code<<1|1, n+1)
+		}
+	}
+	walk(0, 0, 0)
+	return codes
+}
+
+func newLookupTable(codes []codeWord) lookupTable {
+	maxLen := 0
+	for _, c := range codes {
+		maxLen = max(maxLen, c.len)
+	}
+	t := lookupTable{rootBits: min(maxLen, lookupBits)}
+	t.build(codes, 0, t.rootBits)
+	return t
+}
+
+// build appends the table indexed by the n bits following the first prefix
+// bits of codes, and its subtables, and returns its position.
+func (t *lookupTable) build(codes []codeWord, prefix, n int) int {
+	offset := len(t.entries)
+	t.entries = append(t.entries, make([]uint32, 1<<n)...)
+	long := map[uint32][]codeWord{}
+	for _, c := range codes {
+		rest := c.len - prefix
+		suffix := c.bits & (1<<rest - 1)
+		if rest > n {
+			key := suffix >> (rest - n)
+			long[key] = append(long[key], c)
+			continue
+		}
+		first := offset + int(suffix<<(n-rest))
+		for i := range 1 << (n - rest) {
+			t.entries[first+i] = uint32(rest)<<8 | uint32(c.value) //nolint:gosec // rest is at most 8
+		}
+	}
+	keys := make([]uint32, 0, len(long))
+	for key := range long {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	for _, key := range keys {
+		maxLen := 0
+		for _, c := range long[key] {
+			maxLen = max(maxLen, c.len)
+		}
+		subBits := min(maxLen-prefix-n, lookupBits)
+		sub := t.build(long[key], prefix+n, subBits)
+		t.entries[offset+int(key)] = lookupSubflag | uint32(subBits)<<16 | uint32(sub) //nolint:gosec // Tables are small
+	}
+	return offset
+}
+
+// decode reads a code word from m and returns its value.
+func (t *lookupTable) decode(m *bits.Bits) (value int, ok bool) {
+	n := t.rootBits
+	e := t.entries[m.Peek(n)]
+	for e&lookupSubflag != 0 {
+		m.Skip(n)
+		n = int(e>>16) & 0x1f
+		e = t.entries[int(e&0xffff)+m.Peek(n)]
+	}
+	l := int(e>>8) & 0x1f
+	if l == 0 {
+		return 0, false
+	}
+	m.Skip(l)
+	return int(e & 0xff), true
+}
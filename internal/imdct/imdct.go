@@ -80,10 +80,13 @@ func init() {
 
 // Win performs the inverse modified DCT and windowing.
 // out must be a slice of length 36. It will be zeroed and filled with the result.
-func Win(out, in []float32, blockType int) {
-	clear(out)
+// Win computes the inverse MDCT of the 18 frequency lines in of a subband
+// and windows the result for blockType into out. For short blocks
+// (blockType 2), in holds the lines of the three windows interleaved.
+func Win(out *[36]float32, in *[18]float32, blockType int) {
+	iwd := &imdctWinData[blockType]
 	if blockType == 2 {
-		iwd := imdctWinData[blockType]
+		*out = [36]float32{}
 		const N = 12
 		for i := range 3 {
 			for p := range N {
@@ -97,7 +100,6 @@ func Win(out, in []float32, blockType int) {
 		return
 	}
 	const N = 36
-	iwd := imdctWinData[blockType]
 	for p := range N {
 		sum := float32(0.0)
 		for m := range N / 2 {
@@ -0,0 +1,54 @@
+package imdct
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWin_LongBlock(t *testing.T) {
+	var in [18]float32
+	for i := range in {
+		in[i] = float32(i%5) - 2
+	}
+	out := [36]float32{}
+	Win(&out, &in, 0)
+	for p := range out {
+		var want float64
+		for m := range in {
+			want += float64(in[m]) * math.Cos(math.Pi/72*(2*float64(p)+1+18)*(2*float64(m)+1))
+		}
+		want *= math.Sin(math.Pi / 36 * (float64(p) + 0.5))
+		if math.Abs(float64(out[p])-want) > 1e-4 {
+			t.Errorf("out[%d] = %v, want %v", p, out[p], want)
+		}
+	}
+}
+
+func TestWin_ShortBlock(t *testing.T) {
+	var in [18]float32
+	// Only the second window has lines.
+	for m := range 6 {
+		in[1+3*m] = 1
+	}
+	out := [36]float32{}
+	for i := range out {
+		out[i] = 1
+	}
+	Win(&out, &in, 2)
+	for p := range out {
+		if (p < 12 || p >= 24) && out[p] != 0 {
+			t.Errorf("out[%d] = %v, want 0 outside the second window", p, out[p])
+		}
+	}
+	if out[12] == 0 {
+		t.Error("out[12] = 0, want the output of the second window")
+	}
+}
+
+func TestWin_NoAllocations(t *testing.T) {
+	var in [18]float32
+	var out [36]float32
+	if n := testing.AllocsPerRun(100, func() { Win(&out, &in, 1) }); n != 0 {
+		t.Errorf("Win allocates %v times, want 0", n)
+	}
+}
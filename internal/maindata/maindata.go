@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 
 	"github.com/llehouerou/go-mp3/internal/bits"
 	"github.com/llehouerou/go-mp3/internal/consts"
@@ -34,6 +35,12 @@ type MainData struct {
 	ScalefacL [2][2][22]int      // 0-4 bits
 	ScalefacS [2][2][13][3]int   // 0-4 bits
 	Is        [2][2][576]float32 // Huffman coded freq. lines
+
+	// The main data bits of a frame start with the bit reservoir of the
+	// previous frame, so they are read into the buffer that the bits of the
+	// previous frame do not use.
+	vecs [2][]byte
+	bits [2]bits.Bits
 }
 
 var scalefacSizesMpeg1 = [16][2]int{
@@ -81,7 +88,8 @@ func initSlen() (nSlen2 [512]int) {
 }
 
 // Read reads main data from the source and decodes scale factors.
-// If reuse is non-nil, it will be reused instead of allocating a new MainData.
+// If reuse is non-nil, it will be reused instead of allocating a new MainData,
+// along with its buffers, provided prev is the bits it returned last.
 func Read(source FullReader, prev *bits.Bits, header frameheader.FrameHeader, sideInfo *sideinfo.SideInfo, reuse *MainData) (*MainData, *bits.Bits, error) {
 	nch := header.NumberOfChannels()
 	// Calculate header audio data size
@@ -104,19 +112,71 @@ func Read(source FullReader, prev *bits.Bits, header frameheader.FrameHeader, si
 	// two frames. main_data_begin indicates how many bytes from previous
 	// frames that should be used. This buffer is later accessed by the
 	// Bits function in the same way as the side info is.
-	m, err := read(source, prev, mainDataSize, sideInfo.MainDataBegin)
+	md := reuse
+	if md == nil {
+		md = &MainData{}
+	}
+	m, err := md.read(source, prev, mainDataSize, sideInfo.MainDataBegin)
 	if err != nil {
 		// This could be due to not enough data in reservoir
 		return nil, nil, err
 	}
 
 	if header.LowSamplingFrequency() == 1 {
-		return getScaleFactorsMpeg2(m, header, sideInfo, reuse)
+		return getScaleFactorsMpeg2(m, header, sideInfo, md, 0)
 	}
-	return getScaleFactorsMpeg1(nch, m, header, sideInfo, reuse)
+	return getScaleFactorsMpeg1(nch, m, header, sideInfo, md, 0)
 }
 
-func getScaleFactorsMpeg2(m *bits.Bits, header frameheader.FrameHeader, sideInfo *sideinfo.SideInfo, reuse *MainData) (*MainData, *bits.Bits, error) {
+// ReadSelfContained is like Read but ignores the bit reservoir. Only the
+// granules and channels whose data is stored entirely inside the current
+// frame are decoded; the others, which would need bytes from previous frames,
+// are returned as silence.
+func ReadSelfContained(source FullReader, header frameheader.FrameHeader, sideInfo *sideinfo.SideInfo, reuse *MainData) (*MainData, *bits.Bits, error) {
+	nch := header.NumberOfChannels()
+	framesize, err := header.FrameSize()
+	if err != nil {
+		return nil, nil, err
+	}
+	if framesize > 2000 {
+		return nil, nil, fmt.Errorf("mp3: framesize = %d", framesize)
+	}
+	mainDataSize := framesize - header.SideInfoSize() - 4
+	if header.ProtectionBit() == 0 {
+		mainDataSize -= 2
+	}
+	md := reuse
+	if md == nil {
+		md = &MainData{}
+	}
+	if mainDataSize > 1500 {
+		return nil, nil, fmt.Errorf("mp3: size = %d", mainDataSize)
+	}
+	// Stand in zeros for the reservoir bytes so that bit positions computed
+	// from the side info stay valid; the parts starting in them are skipped.
+	missing := sideInfo.MainDataBegin
+	next := md.spare(nil)
+	vec := slices.Grow(md.vecs[next][:0], missing+mainDataSize)[:missing]
+	clear(vec)
+	m, err := md.readInto(source, next, vec, mainDataSize, "maindata.Read (2)")
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.LowSamplingFrequency() == 1 {
+		return getScaleFactorsMpeg2(m, header, sideInfo, md, missing*8)
+	}
+	return getScaleFactorsMpeg1(nch, m, header, sideInfo, md, missing*8)
+}
+
+// skipPart skips the scale factors and Huffman data of granule gr and
+// channel ch, which start at bit part2Start of m, and silences them.
+func skipPart(m *bits.Bits, sideInfo *sideinfo.SideInfo, md *MainData, part2Start, gr, ch int) {
+	md.Is[gr][ch] = [consts.SamplesPerGr]float32{}
+	sideInfo.Count1[gr][ch] = 0
+	m.SetPos(part2Start + sideInfo.Part2_3Length[gr][ch])
+}
+
+func getScaleFactorsMpeg2(m *bits.Bits, header frameheader.FrameHeader, sideInfo *sideinfo.SideInfo, reuse *MainData, firstBit int) (*MainData, *bits.Bits, error) {
 	nch := header.NumberOfChannels()
 
 	var md *MainData
@@ -131,6 +191,10 @@ func getScaleFactorsMpeg2(m *bits.Bits, header frameheader.FrameHeader, sideInfo
 
 	for ch := range nch {
 		part2Start := m.BitPos()
+		if part2Start < firstBit {
+			skipPart(m, sideInfo, md, part2Start, 0, ch)
+			continue
+		}
 		numbits := 0
 		slen := nSlen2[sideInfo.ScalefacCompress[0][ch]]
 		sideInfo.Preflag[0][ch] = (slen >> 15) & 0x1
@@ -187,7 +251,7 @@ func getScaleFactorsMpeg2(m *bits.Bits, header frameheader.FrameHeader, sideInfo
 	return md, m, nil
 }
 
-func getScaleFactorsMpeg1(nch int, m *bits.Bits, header frameheader.FrameHeader, sideInfo *sideinfo.SideInfo, reuse *MainData) (*MainData, *bits.Bits, error) {
+func getScaleFactorsMpeg1(nch int, m *bits.Bits, header frameheader.FrameHeader, sideInfo *sideinfo.SideInfo, reuse *MainData, firstBit int) (*MainData, *bits.Bits, error) {
 	var md *MainData
 	if reuse != nil {
 		md = reuse
@@ -200,6 +264,10 @@ func getScaleFactorsMpeg1(nch int, m *bits.Bits, header frameheader.FrameHeader,
 	for gr := range 2 {
 		for ch := range nch {
 			part2Start := m.BitPos()
+			if part2Start < firstBit {
+				skipPart(m, sideInfo, md, part2Start, gr, ch)
+				continue
+			}
 			// Number of bits in the bitstream for the bands
 			slen1 := scalefacSizesMpeg1[sideInfo.ScalefacCompress[gr][ch]][0]
 			slen2 := scalefacSizesMpeg1[sideInfo.ScalefacCompress[gr][ch]][1]
@@ -287,37 +355,49 @@ func getScaleFactorsMpeg1(nch int, m *bits.Bits, header frameheader.FrameHeader,
 	return md, m, nil
 }
 
-func read(source FullReader, prev *bits.Bits, size, offset int) (*bits.Bits, error) {
+func (md *MainData) read(source FullReader, prev *bits.Bits, size, offset int) (*bits.Bits, error) {
 	if size > 1500 {
 		return nil, fmt.Errorf("mp3: size = %d", size)
 	}
+	next := md.spare(prev)
+	vec := md.vecs[next][:0]
 	// Check that there's data available from previous frames if needed
 	if prev != nil && offset > prev.LenInBytes() {
 		// No, there is not, so we skip decoding this frame, but we have to
 		// read the main_data bits from the bitstream in case they are needed
 		// for decoding the next frame.
-		buf := make([]byte, size)
-		if n, err := source.ReadFull(buf); n < size {
-			if errors.Is(err, io.EOF) {
-				return nil, &consts.UnexpectedEOFError{At: "maindata.Read (1)"}
-			}
-			return nil, err
-		}
 		// TODO: Define a special error and enable to continue the next frame.
-		return bits.Append(prev, buf), nil
+		vec = append(vec, prev.Tail(prev.LenInBytes())...)
+		return md.readInto(source, next, vec, size, "maindata.Read (1)")
 	}
 	// Copy data from previous frames
-	vec := []byte{}
 	if prev != nil {
-		vec = prev.Tail(offset)
+		vec = append(vec, prev.Tail(offset)...)
 	}
 	// Read the main_data from file
-	buf := make([]byte, size)
-	if n, err := source.ReadFull(buf); n < size {
+	return md.readInto(source, next, vec, size, "maindata.Read (2)")
+}
+
+// spare returns the index of the buffer of md that prev does not use.
+func (md *MainData) spare(prev *bits.Bits) int {
+	if prev == &md.bits[0] {
+		return 1
+	}
+	return 0
+}
+
+// readInto reads size bytes of main data from source after vec and makes
+// the result the buffer next of md. at names the read in errors.
+func (md *MainData) readInto(source FullReader, next int, vec []byte, size int, at string) (*bits.Bits, error) {
+	n := len(vec)
+	vec = slices.Grow(vec, size)[:n+size]
+	if m, err := source.ReadFull(vec[n:]); m < size {
 		if errors.Is(err, io.EOF) {
-			return nil, &consts.UnexpectedEOFError{At: "maindata.Read (2)"}
+			return nil, &consts.UnexpectedEOFError{At: at}
 		}
 		return nil, err
 	}
-	return bits.New(append(vec, buf...)), nil
+	md.vecs[next] = vec
+	md.bits[next].Reset(vec)
+	return &md.bits[next], nil
 }
@@ -0,0 +1,47 @@
+package maindata
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/internal/sideinfo"
+)
+
+func TestRead_ReusesBuffers(t *testing.T) {
+	header := createTestFrameHeader()
+	// 128 kbps at 44100 Hz without padding: 417 bytes, less the header and
+	// the stereo side info.
+	const size = 417 - 4 - 32
+	data := make([]byte, 3*size)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	source := &mockReader{data: data}
+
+	md, m, err := Read(source, nil, header, &sideinfo.SideInfo{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last []byte
+	for i := 1; i < 3; i++ {
+		const reservoir = 100
+		prev := m.Tail(reservoir)
+		si := &sideinfo.SideInfo{MainDataBegin: reservoir}
+		var next *MainData
+		next, m, err = Read(source, m, header, si, md)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next != md {
+			t.Fatal("Read allocated a new MainData")
+		}
+		want := append(bytes.Clone(prev), data[i*size:(i+1)*size]...)
+		if got := m.Tail(m.LenInBytes()); !bytes.Equal(got, want) {
+			t.Fatalf("frame %d: main data does not start with the bit reservoir", i)
+		}
+		if last != nil && !bytes.Equal(last, data[size-reservoir:2*size]) {
+			t.Fatal("the main data of the previous frame was overwritten")
+		}
+		last = m.Tail(m.LenInBytes())
+	}
+}
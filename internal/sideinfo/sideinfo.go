@@ -52,6 +52,8 @@ type SideInfo struct {
 	ScalefacScale     [2][2]int // 1 bit
 	Count1TableSelect [2][2]int // 1 bit
 	Count1            [2][2]int // Not in file, calc by huffman decoder
+
+	raw [32]byte // The side info as read from the bitstream
 }
 
 var sideInfoBitsToRead = [2][4]int{
@@ -63,7 +65,10 @@ var sideInfoBitsToRead = [2][4]int{
 	},
 }
 
-func Read(source FullReader, header frameheader.FrameHeader) (*SideInfo, error) {
+// Read reads the side info of a frame with the given header from source.
+// If reuse is non-nil, it is overwritten instead of allocating a new
+// SideInfo. Its fields are left untouched if the side info cannot be read.
+func Read(source FullReader, header frameheader.FrameHeader, reuse *SideInfo) (*SideInfo, error) {
 	nch := header.NumberOfChannels()
 	framesize, err := header.FrameSize()
 	if err != nil {
@@ -74,8 +79,12 @@ func Read(source FullReader, header frameheader.FrameHeader) (*SideInfo, error)
 	}
 	sideinfoSize := header.SideInfoSize()
 
+	si := reuse
+	if si == nil {
+		si = &SideInfo{}
+	}
 	// Read sideinfo from bitstream into buffer used by Bits()
-	buf := make([]byte, sideinfoSize)
+	buf := si.raw[:sideinfoSize]
 	n, err := source.ReadFull(buf)
 	if n < sideinfoSize {
 		if errors.Is(err, io.EOF) {
@@ -83,14 +92,14 @@ func Read(source FullReader, header frameheader.FrameHeader) (*SideInfo, error)
 		}
 		return nil, fmt.Errorf("mp3: couldn't read sideinfo %d bytes: %w", sideinfoSize, err)
 	}
-	s := bits.New(buf)
+	*si = SideInfo{raw: si.raw}
+	s := bits.New(si.raw[:sideinfoSize])
 
 	mpeg1Frame := header.LowSamplingFrequency() == 0
 	bitsToRead := sideInfoBitsToRead[header.LowSamplingFrequency()]
 
 	// Parse audio data
 	// Pointer to where we should start reading main data
-	si := &SideInfo{}
 	si.MainDataBegin = s.Bits(bitsToRead[0])
 	// Get private bits. Not used for anything.
 	if header.Mode() == consts.ModeSingleChannel {
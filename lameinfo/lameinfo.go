@@ -14,6 +14,8 @@ import (
 	"encoding/binary"
 	"errors"
 	"io"
+
+	"github.com/llehouerou/go-mp3/frameheader"
 )
 
 // Info contains the parsed LAME/Xing header information.
@@ -41,6 +43,10 @@ type Info struct {
 	// Empty if no LAME tag is present.
 	LAMEVersion string
 
+	// VBRMethod is the bitrate mode recorded in the LAME tag, one of the
+	// VBRMethod constants. Valid only if HasLAMEInfo is true.
+	VBRMethod uint8
+
 	// EncoderDelay is the number of samples added at the start by the encoder.
 	// Typically 576 for LAME. Valid only if HasLAMEInfo is true.
 	EncoderDelay uint16
@@ -48,8 +54,37 @@ type Info struct {
 	// EncoderPadding is the number of samples added at the end by the encoder.
 	// Valid only if HasLAMEInfo is true.
 	EncoderPadding uint16
+
+	// MusicLength is the size in bytes of the stream from the start of the
+	// Xing header frame to the end of the last frame, or 0 if unknown.
+	// Valid only if HasLAMEInfo is true.
+	MusicLength uint32
+
+	// MusicCRC is the CRC-16 of the frames after the Xing header frame, up
+	// to MusicLength, as computed by UpdateCRC. Valid only if HasLAMEInfo
+	// is true.
+	MusicCRC uint16
+
+	// Valid reports whether the CRC that ends the LAME tag matches the
+	// bytes of the frame before it. Tags that are not valid were damaged or
+	// edited, or written by an old encoder, and their delay, padding and
+	// other values should not be trusted. Valid only if HasLAMEInfo is
+	// true.
+	Valid bool
 }
 
+// VBR methods of the VBRMethod field, as written by LAME.
+const (
+	VBRMethodUnknown    = 0
+	VBRMethodCBR        = 1
+	VBRMethodABR        = 2
+	VBRMethodVBROld     = 3
+	VBRMethodVBRMTRH    = 4
+	VBRMethodVBRMT      = 5
+	VBRMethodCBRTwoPass = 8
+	VBRMethodABRTwoPass = 9
+)
+
 // Flag constants for the Flags field.
 const (
 	FlagFrameCount = 0x0001
@@ -113,22 +148,6 @@ func (i *Info) TotalPadding() int {
 // ErrNoXingHeader is returned when no Xing/Info header is found.
 var ErrNoXingHeader = errors.New("lameinfo: no Xing/Info header found")
 
-// sideInfoSize returns the size of the side information based on
-// MPEG version and channel mode.
-func sideInfoSize(mpegVersion int, mono bool) int {
-	if mpegVersion == 1 { // MPEG1
-		if mono {
-			return 17
-		}
-		return 32
-	}
-	// MPEG2 or MPEG2.5
-	if mono {
-		return 9
-	}
-	return 17
-}
-
 // Parse reads an MP3 frame and extracts LAME/Xing header information.
 // The frame should be the first audio frame of the MP3 file (after any ID3 tags).
 //
@@ -142,42 +161,18 @@ func Parse(frame []byte) (*Info, error) {
 	}
 
 	// Parse frame header to determine side info size
-	header := binary.BigEndian.Uint32(frame[0:4])
+	h := frameheader.Header(binary.BigEndian.Uint32(frame[0:4]))
 
 	// Check sync word (11 bits)
-	if (header & 0xFFE00000) != 0xFFE00000 {
+	if (h & 0xFFE00000) != 0xFFE00000 {
 		return nil, ErrNoXingHeader
 	}
-
-	// Extract MPEG version (bits 19-20)
-	mpegVersion := int((header >> 19) & 0x03)
-	if mpegVersion == 1 { // Reserved
+	if h.Version() == frameheader.VersionReserved {
 		return nil, ErrNoXingHeader
 	}
-	// Convert: 0=2.5, 2=2, 3=1
-	var version int
-	switch mpegVersion {
-	case 0:
-		version = 25 // MPEG 2.5
-	case 2:
-		version = 2 // MPEG 2
-	case 3:
-		version = 1 // MPEG 1
-	}
-
-	// Extract channel mode (bits 6-7)
-	channelMode := (header >> 6) & 0x03
-	mono := channelMode == 3 // 3 = single channel (mono)
 
 	// Calculate offset to Xing tag
-	var sideInfo int
-	if version == 1 {
-		sideInfo = sideInfoSize(1, mono)
-	} else {
-		sideInfo = sideInfoSize(2, mono)
-	}
-
-	offset := 4 + sideInfo // 4-byte header + side info
+	offset := frameheader.Size + h.SideInfoSize()
 
 	// Check for Xing or Info tag
 	if len(frame) < offset+4 {
@@ -242,6 +237,9 @@ func Parse(frame []byte) (*Info, error) {
 		if isLAMEVersion(version) {
 			info.LAMEVersion = version
 			pos += 9
+			if len(frame) > pos {
+				info.VBRMethod = frame[pos] & 0x0F
+			}
 
 			// Skip to encoder delay/padding (21 bytes after version string)
 			// Layout after version:
@@ -263,6 +261,23 @@ func Parse(frame []byte) (*Info, error) {
 				info.EncoderDelay = uint16(frame[delayOffset])<<4 | uint16(frame[delayOffset+1])>>4
 				info.EncoderPadding = uint16(frame[delayOffset+1]&0x0F)<<8 | uint16(frame[delayOffset+2])
 			}
+
+			// After delay/padding:
+			// 1 byte: misc
+			// 1 byte: MP3 gain
+			// 2 bytes: preset and surround info
+			// then 4 bytes for the music length and 2 for the music CRC
+			musicOffset := delayOffset + 7
+			if len(frame) >= musicOffset+6 {
+				info.MusicLength = binary.BigEndian.Uint32(frame[musicOffset : musicOffset+4])
+				info.MusicCRC = binary.BigEndian.Uint16(frame[musicOffset+4 : musicOffset+6])
+			}
+
+			// The tag ends with the CRC of the bytes of the frame before it.
+			crcOffset := musicOffset + 6
+			if len(frame) >= crcOffset+2 {
+				info.Valid = binary.BigEndian.Uint16(frame[crcOffset:crcOffset+2]) == UpdateCRC(0, frame[:crcOffset])
+			}
 		}
 	}
 
@@ -293,27 +308,8 @@ func ParseFromReader(r io.Reader) (*Info, error) {
 	}
 
 	// Parse header to get frame size
-	h := binary.BigEndian.Uint32(header)
-
-	// Check sync word
-	if (h & 0xFFE00000) != 0xFFE00000 {
-		return nil, ErrNoXingHeader
-	}
-
-	// Extract fields needed to calculate frame size
-	mpegVersion := (h >> 19) & 0x03
-	layer := (h >> 17) & 0x03
-	bitrateIndex := (h >> 12) & 0x0F
-	samplingRateIndex := (h >> 10) & 0x03
-	padding := (h >> 9) & 0x01
-
-	if mpegVersion == 1 || layer == 0 || bitrateIndex == 0 || bitrateIndex == 15 || samplingRateIndex == 3 {
-		return nil, ErrNoXingHeader
-	}
-
-	// Calculate frame size
-	frameSize := calculateFrameSize(mpegVersion, layer, bitrateIndex, samplingRateIndex, padding)
-	if frameSize < 4 {
+	frameSize := frameheader.Header(binary.BigEndian.Uint32(header)).FrameSize()
+	if frameSize < frameheader.Size {
 		return nil, ErrNoXingHeader
 	}
 
@@ -326,59 +322,3 @@ func ParseFromReader(r io.Reader) (*Info, error) {
 
 	return Parse(frame)
 }
-
-// Bitrate tables for frame size calculation
-var bitrateTable = [4][4][16]int{
-	// MPEG 2.5 (index 0)
-	{
-		{0}, // Reserved
-		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},      // Layer III
-		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},      // Layer II
-		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0}, // Layer I
-	},
-	// Reserved (index 1)
-	{},
-	// MPEG 2 (index 2)
-	{
-		{0}, // Reserved
-		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},      // Layer III
-		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},      // Layer II
-		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0}, // Layer I
-	},
-	// MPEG 1 (index 3)
-	{
-		{0}, // Reserved
-		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},     // Layer III
-		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 0},    // Layer II
-		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, 0}, // Layer I
-	},
-}
-
-var samplingRateTable = [4][4]int{
-	{11025, 12000, 8000, 0},  // MPEG 2.5
-	{0, 0, 0, 0},             // Reserved
-	{22050, 24000, 16000, 0}, // MPEG 2
-	{44100, 48000, 32000, 0}, // MPEG 1
-}
-
-func calculateFrameSize(mpegVersion, layer, bitrateIndex, samplingRateIndex, padding uint32) int {
-	bitrate := bitrateTable[mpegVersion][layer][bitrateIndex] * 1000
-	samplingRate := samplingRateTable[mpegVersion][samplingRateIndex]
-
-	if bitrate == 0 || samplingRate == 0 {
-		return 0
-	}
-
-	var frameSize int
-	if layer == 3 { // Layer I
-		frameSize = (12*bitrate/samplingRate + int(padding)) * 4
-	} else { // Layer II or III
-		if mpegVersion == 3 { // MPEG 1
-			frameSize = 144*bitrate/samplingRate + int(padding)
-		} else { // MPEG 2 or 2.5
-			frameSize = 72*bitrate/samplingRate + int(padding)
-		}
-	}
-
-	return frameSize
-}
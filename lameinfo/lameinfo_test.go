@@ -92,6 +92,7 @@ func buildTestFrame(opts testFrameOptions) []byte {
 
 		// LAME info fields (12 bytes before delay/padding)
 		lameInfo := make([]byte, 12)
+		lameInfo[0] = opts.vbrMethod
 		frame = append(frame, lameInfo...)
 
 		// Encoder delay and padding (3 bytes, 12 bits each)
@@ -122,6 +123,7 @@ type testFrameOptions struct {
 	byteCount      uint32
 	vbrScale       uint32
 	lameVersion    string
+	vbrMethod      uint8
 	encoderDelay   uint16
 	encoderPadding uint16
 }
@@ -249,6 +251,25 @@ func TestParse_LAMEInfo(t *testing.T) {
 	}
 }
 
+func TestParse_VBRMethod(t *testing.T) {
+	for _, method := range []uint8{VBRMethodCBR, VBRMethodABR, VBRMethodVBROld} {
+		frame := buildTestFrame(testFrameOptions{
+			flags:       FlagFrameCount,
+			frameCount:  10,
+			lameVersion: "LAME3.100",
+			// Revision 1 in the upper nibble.
+			vbrMethod: 0x10 | method,
+		})
+		info, err := Parse(frame)
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if info.VBRMethod != method {
+			t.Errorf("VBRMethod = %d, want %d", info.VBRMethod, method)
+		}
+	}
+}
+
 func TestParse_TotalDelay(t *testing.T) {
 	// Without LAME info
 	info := &Info{}
@@ -525,6 +546,11 @@ func TestParse_RealLAMEFile(t *testing.T) {
 		t.Errorf("LAMEVersion = %q, want %q", info.LAMEVersion, "LAME3.100")
 	}
 
+	// -V2 uses the default VBR method of LAME 3.100.
+	if info.VBRMethod != VBRMethodVBRMTRH {
+		t.Errorf("VBRMethod = %d, want %d", info.VBRMethod, VBRMethodVBRMTRH)
+	}
+
 	// Verify encoder delay is typical LAME value (576)
 	if info.EncoderDelay != 576 {
 		t.Errorf("EncoderDelay = %d, want 576", info.EncoderDelay)
@@ -586,3 +612,38 @@ func TestParse_RealMPEG2File(t *testing.T) {
 		t.Errorf("ParseFromReader() error = %v, want ErrNoXingHeader", err)
 	}
 }
+
+func TestParse_TagCRC(t *testing.T) {
+	path := filepath.Join("..", "example", "classic_lame.mp3")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Skipf("Test file not found: %v", err)
+	}
+	info, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !info.Valid {
+		t.Error("Valid = false for the tag written by LAME, want true")
+	}
+
+	// Change the encoder delay, as a broken tool might.
+	edited := bytes.Clone(data)
+	edited[156+21]++
+	info, err = Parse(edited)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if info.Valid {
+		t.Error("Valid = true for an edited tag, want false")
+	}
+
+	// A tag without its CRC.
+	info, err = Parse(buildTestFrame(testFrameOptions{lameVersion: "LAME3.100", encoderDelay: 576}))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if info.Valid {
+		t.Error("Valid = true for a tag without CRC, want false")
+	}
+}
@@ -0,0 +1,140 @@
+package lameinfo
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/llehouerou/go-mp3/frameheader"
+)
+
+// lameTagSize is the size of the LAME tag, from the version string to the
+// tag CRC.
+const lameTagSize = 36
+
+// Build returns a Xing header frame holding info, to be written before the
+// audio frames of a stream whose first frame has the header h. The fields
+// selected by info.Flags are written, and, if info.LAMEVersion is set, a
+// LAME tag with its version, VBR method, encoder delay and padding, music
+// length and CRC, and tag CRC; info.Valid is ignored. The tag is "Xing" if
+// info.IsXing is set and "Info" otherwise.
+//
+// The frame has the version, sample rate and mode of h, without CRC or
+// padding, and, like LAME does, a higher bitrate than h if needed to hold
+// the header. ByteCount should count the bytes of the returned frame, and
+// FrameCount should not.
+//
+// Build returns an error if h is not a valid Layer III header.
+func Build(h frameheader.Header, info *Info) ([]byte, error) {
+	if !h.Valid() || h.Layer() != 3 || h.Bitrate() == 0 {
+		return nil, errors.New("lameinfo: invalid Layer III frame header")
+	}
+	need := frameheader.Size + h.SideInfoSize() + 8
+	if info.HasFrameCount() {
+		need += 4
+	}
+	if info.HasByteCount() {
+		need += 4
+	}
+	if info.HasTOC() {
+		need += 100
+	}
+	if info.HasVBRScale() {
+		need += 4
+	}
+	if info.HasLAMEInfo() {
+		need += lameTagSize
+	}
+	h = h&^(1<<9) | 1<<16
+	for h.FrameSize() < need {
+		if h>>12&0xf == 14 {
+			return nil, errors.New("lameinfo: header does not fit in a frame")
+		}
+		h += 1 << 12
+	}
+
+	b := make([]byte, h.FrameSize())
+	binary.BigEndian.PutUint32(b, uint32(h))
+	pos := frameheader.Size + h.SideInfoSize()
+	if info.IsXing {
+		copy(b[pos:], "Xing")
+	} else {
+		copy(b[pos:], "Info")
+	}
+	binary.BigEndian.PutUint32(b[pos+4:], info.Flags&0xf)
+	pos += 8
+	if info.HasFrameCount() {
+		binary.BigEndian.PutUint32(b[pos:], info.FrameCount)
+		pos += 4
+	}
+	if info.HasByteCount() {
+		binary.BigEndian.PutUint32(b[pos:], info.ByteCount)
+		pos += 4
+	}
+	if info.HasTOC() {
+		copy(b[pos:], info.TOC[:])
+		pos += 100
+	}
+	if info.HasVBRScale() {
+		binary.BigEndian.PutUint32(b[pos:], info.VBRScale)
+		pos += 4
+	}
+	if info.HasLAMEInfo() {
+		// The version string is padded with spaces to 9 bytes.
+		copy(b[pos:pos+9], "         ")
+		copy(b[pos:pos+9], info.LAMEVersion)
+		b[pos+9] = info.VBRMethod & 0x0f
+		delay, padding := min(info.EncoderDelay, 0xfff), min(info.EncoderPadding, 0xfff)
+		b[pos+21] = byte(delay >> 4)
+		b[pos+22] = byte(delay<<4) | byte(padding>>8)
+		b[pos+23] = byte(padding)
+		binary.BigEndian.PutUint32(b[pos+28:], info.MusicLength)
+		binary.BigEndian.PutUint16(b[pos+32:], info.MusicCRC)
+		// The tag ends with the CRC of the bytes of the frame before it.
+		binary.BigEndian.PutUint16(b[pos+34:], UpdateCRC(0, b[:pos+34]))
+	}
+	return b, nil
+}
+
+// Write writes the Xing header frame returned by Build to w.
+func Write(w io.Writer, h frameheader.Header, info *Info) error {
+	b, err := Build(h, info)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// BuildTOC returns the seek table of a stream whose audio frames start at
+// offsets, counted from the start of its Xing header frame, and which is
+// size bytes long including that frame, as stored in ByteCount. Entry i is
+// the position of the frame at i percent of the audio, in 1/256 of size.
+func BuildTOC(offsets []int64, size int64) [100]byte {
+	var toc [100]byte
+	if len(offsets) == 0 || size <= 0 {
+		return toc
+	}
+	for i := range toc {
+		off := offsets[i*len(offsets)/100]
+		toc[i] = byte(min(off*256/size, 255))
+	}
+	return toc
+}
+
+// UpdateCRC returns the result of adding the bytes in p to crc, a CRC-16
+// with the polynomial 0x8005, reflected, as used by the music and tag CRCs
+// of the LAME tag. The CRC of a whole stream starts at 0.
+func UpdateCRC(crc uint16, p []byte) uint16 {
+	for _, v := range p {
+		crc ^= uint16(v)
+		for range 8 {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xa001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
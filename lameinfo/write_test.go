@@ -0,0 +1,166 @@
+package lameinfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/frameheader"
+)
+
+// mpeg1Header is a 128 kbps MPEG-1 Layer III joint stereo header at
+// 44.1 kHz, with padding and CRC.
+const mpeg1Header = frameheader.Header(0xfffa9240)
+
+func TestBuild_RoundTrip(t *testing.T) {
+	var toc [100]byte
+	for i := range toc {
+		toc[i] = byte(i * 2)
+	}
+	in := &Info{
+		IsXing:         true,
+		Flags:          FlagFrameCount | FlagByteCount | FlagTOC | FlagVBRScale,
+		FrameCount:     1234,
+		ByteCount:      567890,
+		TOC:            toc,
+		VBRScale:       78,
+		LAMEVersion:    "LAME3.100",
+		VBRMethod:      VBRMethodVBRMTRH,
+		EncoderDelay:   576,
+		EncoderPadding: 1234,
+		MusicLength:    567890,
+		MusicCRC:       0xbeef,
+		Valid:          true,
+	}
+	b, err := Build(mpeg1Header, in)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	h, err := frameheader.Parse(b)
+	if err != nil {
+		t.Fatalf("frameheader.Parse() error = %v", err)
+	}
+	if h.FrameSize() != len(b) {
+		t.Errorf("len = %d, want frame size %d", len(b), h.FrameSize())
+	}
+	if h.Padding() || h.Protected() {
+		t.Errorf("Padding() = %v, Protected() = %v, want false", h.Padding(), h.Protected())
+	}
+	if h.SampleRate() != mpeg1Header.SampleRate() || h.Mode() != mpeg1Header.Mode() {
+		t.Errorf("format = %d Hz mode %d, want that of the stream", h.SampleRate(), h.Mode())
+	}
+
+	out, err := Parse(b)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if *out != *in {
+		t.Errorf("Parse(Build()) = %+v, want %+v", *out, *in)
+	}
+}
+
+func TestBuild_Fields(t *testing.T) {
+	b, err := Build(mpeg1Header, &Info{Flags: FlagFrameCount, FrameCount: 42})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	info, err := Parse(b)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if info.IsXing || info.Flags != FlagFrameCount || info.FrameCount != 42 {
+		t.Errorf("Parse() = %+v, want an Info tag with only the frame count", *info)
+	}
+	if info.HasLAMEInfo() {
+		t.Error("HasLAMEInfo() = true, want false")
+	}
+}
+
+func TestBuild_RaisesBitrate(t *testing.T) {
+	// 8 kbps MPEG-2.5 frames at 8 kHz are 72 bytes long, too small for a
+	// TOC.
+	low := frameheader.Header(0xffe318c0)
+	b, err := Build(low, &Info{Flags: FlagFrameCount | FlagByteCount | FlagTOC})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	h, _ := frameheader.Parse(b)
+	if h.Bitrate() <= low.Bitrate() {
+		t.Errorf("Bitrate() = %d, want more than %d", h.Bitrate(), low.Bitrate())
+	}
+	if _, err := Parse(b); err != nil {
+		t.Errorf("Parse() error = %v", err)
+	}
+}
+
+func TestBuild_InvalidHeader(t *testing.T) {
+	if _, err := Build(0, &Info{}); err == nil {
+		t.Error("Build(0) error = nil, want an error")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	info := &Info{Flags: FlagByteCount, ByteCount: 1000}
+	want, _ := Build(mpeg1Header, info)
+	var buf bytes.Buffer
+	if err := Write(&buf, mpeg1Header, info); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Error("Write() did not write the frame of Build()")
+	}
+}
+
+func TestBuildTOC(t *testing.T) {
+	// 1000 frames of 100 bytes after a Xing frame of 100 bytes.
+	offsets := make([]int64, 1000)
+	for i := range offsets {
+		offsets[i] = int64(100 + 100*i)
+	}
+	toc := BuildTOC(offsets, 100+100*1000)
+	for i, v := range toc {
+		// The entry must not point past the frame at i percent.
+		off := offsets[i*10]
+		if pos := int64(v) * 100100 / 256; pos > off || off-pos > 100100/256+1 {
+			t.Errorf("toc[%d] = %d (byte %d), want the frame at byte %d", i, v, pos, off)
+		}
+	}
+	if BuildTOC(nil, 0) != [100]byte{} {
+		t.Error("BuildTOC(nil) is not empty")
+	}
+}
+
+func TestUpdateCRC_RealLAMEFile(t *testing.T) {
+	path := filepath.Join("..", "example", "classic_lame.mp3")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Skipf("Test file not found: %v", err)
+	}
+	h, err := frameheader.Parse(data)
+	if err != nil {
+		t.Fatalf("frameheader.Parse() error = %v", err)
+	}
+	// LAME stores the CRC of the first 190 bytes of the frame at byte 190.
+	frame := data[:h.FrameSize()]
+	want := binary.BigEndian.Uint16(frame[190:])
+	if got := UpdateCRC(0, frame[:190]); got != want {
+		t.Errorf("UpdateCRC() = %#04x, want %#04x", got, want)
+	}
+
+	info, err := Parse(frame)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if int(info.MusicLength) != len(data) {
+		t.Errorf("MusicLength = %d, want the file size %d", info.MusicLength, len(data))
+	}
+	b, err := Build(h, info)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if got := binary.BigEndian.Uint16(b[190:]); got != UpdateCRC(0, b[:190]) {
+		t.Errorf("tag CRC = %#04x, want %#04x", got, UpdateCRC(0, b[:190]))
+	}
+}
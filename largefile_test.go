@@ -0,0 +1,112 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+// repeatReader is an io.ReadSeeker of size bytes repeating period, standing
+// in for files too large to hold in memory.
+type repeatReader struct {
+	period    []byte
+	size, pos int64
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+	p = p[:min(int64(len(p)), r.size-r.pos)]
+	n := 0
+	for n < len(p) {
+		n += copy(p[n:], r.period[(r.pos+int64(n))%int64(len(r.period)):])
+	}
+	r.pos += int64(n)
+	return n, nil
+}
+
+func (r *repeatReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.size
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+func TestDuration_SixHours320kbps(t *testing.T) {
+	if testing.Short() {
+		t.Skip("scans 860 MB of frames")
+	}
+	// At 320 kbps and 44100 Hz the padding of the frames repeats every 49
+	// frames, and 6 hours are 16875 such periods.
+	const periods = 16875
+	period, err := testsupport.Generate(testsupport.Options{Bitrate: 320, Frames: 49})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &repeatReader{period: period, size: int64(len(period)) * periods}
+	d, err := NewDecoder(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const frames = 49 * periods
+	if got, want := d.Length(), int64(frames*1152*4); got != want {
+		t.Errorf("Length() = %d, want %d", got, want)
+	}
+	if got, want := d.Duration(), 6*time.Hour; got != want {
+		t.Errorf("Duration() = %v, want %v", got, want)
+	}
+	if err := d.SeekToTime(5 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if got := d.Position(); got < 5*time.Hour-time.Second/10 || got > 5*time.Hour {
+		t.Errorf("Position() after SeekToTime(5h) = %v", got)
+	}
+}
+
+func TestBytesToDuration_Long(t *testing.T) {
+	for _, rate := range []int{8000, 44100, 48000} {
+		d := &Decoder{sampleRate: rate}
+		for _, dur := range []time.Duration{6 * time.Hour, 24 * time.Hour, 1000 * time.Hour} {
+			bytes := int64(dur/time.Second) * int64(rate) * 4
+			if got := d.durationToBytes(dur); got != bytes {
+				t.Errorf("%d Hz: durationToBytes(%v) = %d, want %d", rate, dur, got, bytes)
+			}
+			if got := d.bytesToDuration(bytes); got != dur {
+				t.Errorf("%d Hz: bytesToDuration(%d) = %v, want %v", rate, bytes, got, dur)
+			}
+		}
+	}
+}
+
+func TestXingByteCount(t *testing.T) {
+	tests := []struct {
+		n    uint32
+		size int64
+		want int64
+	}{
+		{1000, 1000, 1000},
+		{1000, 1400, 1000},
+		{0xFFFFFF00, 0xFFFFFF00, 0xFFFFFF00},
+		// Wrapped past 4 GB.
+		{100, 1<<32 + 100, 1<<32 + 100},
+		{100, 3<<32 + 500, 3<<32 + 100},
+		// Xing header frame excluded from the count.
+		{0xFFFFFF00, 1<<32 + 200, 0xFFFFFF00},
+	}
+	for _, tt := range tests {
+		if got := xingByteCount(tt.n, tt.size); got != tt.want {
+			t.Errorf("xingByteCount(%d, %d) = %d, want %d", tt.n, tt.size, got, tt.want)
+		}
+	}
+}
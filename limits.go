@@ -0,0 +1,64 @@
+package mp3
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Limits bounds the memory a Decoder allocates on behalf of its input, so
+// that a crafted file cannot make a server allocate without bound. A zero
+// field means no limit.
+type Limits struct {
+	// MaxFrames is the largest number of frames indexed when the source is
+	// seekable. The index takes a few dozen bytes per frame.
+	MaxFrames int
+
+	// MaxReservoir is the largest size in bytes of the main data buffer,
+	// which holds the bit reservoir of the previous frames and the main data
+	// of the current frame. It stays under 2 KB for valid streams but grows
+	// with every frame referring to missing reservoir bytes.
+	MaxReservoir int
+
+	// MaxTagSize is the largest size in bytes of the ID3v2 tags read at the
+	// start of the stream, including the bytes past their declared size
+	// examined to fix sizes miscomputed by faulty taggers.
+	MaxTagSize int
+
+	// MaxBufferedPCM is the largest number of bytes of PCM buffered by the
+	// decoder. It bounds the block size of WithBlockSize, and Read with
+	// 8-bit output formats decodes at most this much at a time.
+	MaxBufferedPCM int
+}
+
+// WithLimits sets limits on the memory allocated for the input. Exceeding a
+// limit makes NewDecoder or the failing method return a *LimitError.
+func WithLimits(l Limits) Option {
+	return func(c *config) {
+		c.limits = l
+	}
+}
+
+// ErrLimitExceeded matches, with errors.Is, the errors returned when a limit
+// set with WithLimits is exceeded.
+var ErrLimitExceeded = errors.New("mp3: limit exceeded")
+
+// LimitError is returned when the input exceeds a limit set with
+// WithLimits.
+type LimitError struct {
+	// Limit is the name of the exceeded field of Limits, and Max its value.
+	Limit string
+	Max   int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("mp3: %s limit of %d exceeded", e.Limit, e.Max)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// exceeds reports whether v exceeds limit, 0 meaning no limit.
+func exceeds(v, limit int) bool {
+	return limit > 0 && v > limit
+}
@@ -0,0 +1,94 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+// checkLimitError checks that err is a *LimitError for limit.
+func checkLimitError(t *testing.T, err error, limit string) {
+	t.Helper()
+	var le *LimitError
+	if !errors.As(err, &le) || le.Limit != limit || !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("error = %v, want a LimitError for %s", err, limit)
+	}
+}
+
+func TestWithLimits_MaxFrames(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{Frames: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewDecoder(bytes.NewReader(data), WithLimits(Limits{MaxFrames: 9}))
+	checkLimitError(t, err, "MaxFrames")
+	if _, err := NewDecoder(bytes.NewReader(data), WithLimits(Limits{MaxFrames: 10})); err != nil {
+		t.Errorf("NewDecoder() with exactly MaxFrames frames: %v", err)
+	}
+}
+
+func TestWithLimits_MaxTagSize(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{
+		Tags: map[string]string{"TIT2": strings.Repeat("x", 1000)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewDecoder(bytes.NewReader(data), WithLimits(Limits{MaxTagSize: 500}))
+	checkLimitError(t, err, "MaxTagSize")
+	d, err := NewDecoder(bytes.NewReader(data), WithLimits(Limits{MaxTagSize: 2000}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Metadata() == nil {
+		t.Error("tag within the limit not read")
+	}
+}
+
+func TestWithLimits_MaxReservoir(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{Frames: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Make every frame refer to the largest bit reservoir.
+	for i := range 10 {
+		off := frameOffset(data, i) + 4
+		data[off] = 0xff
+		data[off+1] |= 0x80
+	}
+	d, err := NewDecoder(bytes.NewReader(data), WithLimits(Limits{MaxReservoir: 500}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = io.ReadAll(d)
+	checkLimitError(t, err, "MaxReservoir")
+
+	d, err = NewDecoder(bytes.NewReader(data), WithLimits(Limits{MaxReservoir: 2048}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(d); err != nil {
+		t.Errorf("ReadAll() within the limit: %v", err)
+	}
+}
+
+func TestWithLimits_MaxBufferedPCM(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{Frames: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewDecoder(bytes.NewReader(data), WithBlockSize(1152), WithLimits(Limits{MaxBufferedPCM: 4096}))
+	checkLimitError(t, err, "MaxBufferedPCM")
+
+	d, err := NewDecoder(bytes.NewReader(data), WithOutputFormat(OutputU8), WithLimits(Limits{MaxBufferedPCM: 1000}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := d.Read(make([]byte, 4000)); err != nil || n != 500 {
+		t.Errorf("Read() = %d, %v, want 500 bytes", n, err)
+	}
+}
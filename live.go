@@ -0,0 +1,66 @@
+package mp3
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+const (
+	liveMinBackoff = 10 * time.Millisecond
+	liveMaxBackoff = time.Second
+)
+
+// WithLiveSource makes the decoder treat io.EOF from the source as a
+// temporary stall, as happens with endless live streams when the server
+// briefly runs out of data. Instead of ending the stream, reads are retried
+// with an exponential backoff until data arrives again.
+//
+// The retries stop when ctx is done; Read then returns ctx.Err(). This is
+// the only way a live stream ends.
+//
+// A live source is never seeked, even if it implements io.Seeker, so Length
+// and Duration report -1.
+func WithLiveSource(ctx context.Context) Option {
+	return func(c *config) {
+		c.liveContext = ctx
+	}
+}
+
+// liveReader retries reads that hit io.EOF until its context is done.
+type liveReader struct {
+	ctx    context.Context
+	reader io.Reader
+
+	// err is an error returned by reader along with data, kept for the
+	// next Read.
+	err error
+}
+
+func (r *liveReader) Read(buf []byte) (int, error) {
+	if err := r.err; err != nil {
+		r.err = nil
+		return 0, err
+	}
+	backoff := liveMinBackoff
+	for {
+		n, err := r.reader.Read(buf)
+		if n > 0 {
+			if err != io.EOF {
+				r.err = err
+			}
+			return n, nil
+		}
+		if err != io.EOF {
+			return n, err
+		}
+		t := time.NewTimer(backoff)
+		select {
+		case <-r.ctx.Done():
+			t.Stop()
+			return 0, r.ctx.Err()
+		case <-t.C:
+		}
+		backoff = min(2*backoff, liveMaxBackoff)
+	}
+}
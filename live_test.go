@@ -0,0 +1,90 @@
+package mp3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+// stallingReader serves data in small chunks and reports io.EOF before
+// every chunk, like a live stream whose server keeps running dry.
+type stallingReader struct {
+	data    []byte
+	stalled bool
+}
+
+func (r *stallingReader) Read(buf []byte) (int, error) {
+	if !r.stalled || len(r.data) == 0 {
+		r.stalled = true
+		return 0, io.EOF
+	}
+	r.stalled = false
+	n := copy(buf, r.data[:min(len(r.data), 1000)])
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestWithLiveSource(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	ref, err := NewDecoder(bytes.NewReader(data[:len(data)/4]))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	want, err := io.ReadAll(io.LimitReader(ref, 64*1024))
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d, err := NewDecoder(&stallingReader{data: data[:len(data)/4]}, WithLiveSource(ctx))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	if d.Length() != -1 {
+		t.Errorf("Length() = %d, want -1 for a live source", d.Length())
+	}
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(d, got); err != nil {
+		t.Fatalf("ReadFull failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("output of a stalling live source differs from the plain decode")
+	}
+
+	// Once the data is exhausted, Read waits until the context ends.
+	cancel()
+	if _, err := io.ReadAll(d); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReadAll after cancel = %v, want context.Canceled", err)
+	}
+}
+
+// dataErrReader returns its data along with err.
+type dataErrReader struct {
+	data []byte
+	err  error
+}
+
+func (r *dataErrReader) Read(buf []byte) (int, error) {
+	n := copy(buf, r.data)
+	r.data = r.data[n:]
+	return n, r.err
+}
+
+func TestLiveReader_KeepsErrorWithData(t *testing.T) {
+	errDropped := errors.New("connection dropped")
+	r := &liveReader{ctx: context.Background(), reader: &dataErrReader{data: []byte("abc"), err: errDropped}}
+	buf := make([]byte, 10)
+	if n, err := r.Read(buf); n != 3 || err != nil {
+		t.Fatalf("Read = %d, %v, want 3, nil", n, err)
+	}
+	if n, err := r.Read(buf); n != 0 || !errors.Is(err, errDropped) {
+		t.Errorf("next Read = %d, %v, want 0 and the error returned with the data", n, err)
+	}
+}
@@ -0,0 +1,52 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// SetLoop makes Read and DecodeFrame repeat the audio between start and
+// end: once the position reaches end, the next Read continues from start,
+// so that the loop plays without a gap. The jump decodes the warmup frames
+// before start like Seek, so the samples at the loop point are accurate.
+// A position already past end also jumps back to start.
+//
+// SetLoop(0, 0) removes the loop. Otherwise start must be before end, which
+// is clamped to Duration. SetLoop returns an error when the source is not
+// io.Seeker. Reset removes the loop.
+func (d *Decoder) SetLoop(start, end time.Duration) error {
+	if start == 0 && end == 0 {
+		d.loopStart, d.loopEnd = 0, 0
+		return nil
+	}
+	if d.length == invalidLength {
+		return errors.New("mp3: loop not supported on non-seekable source")
+	}
+	if d.resampler != nil {
+		return errors.New("mp3: loop not supported with resampling")
+	}
+	if start < 0 || start >= end {
+		return errors.New("mp3: loop start must be before its end")
+	}
+	s, e := d.posAt(start)&^3, d.posAt(end)&^3
+	if s >= e {
+		return errors.New("mp3: loop start must be before its end")
+	}
+	d.loopStart, d.loopEnd = s, e
+	return nil
+}
+
+// loopBuf returns the part of buf to read before the loop end, after
+// jumping back to the loop start if the position is at the end.
+func (d *Decoder) loopBuf(buf []byte) ([]byte, error) {
+	if d.loopEnd == 0 {
+		return buf, nil
+	}
+	if d.pos >= d.loopEnd {
+		if _, err := d.seek(d.loopStart, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	return buf[:min(int64(len(buf)), d.loopEnd-d.pos)], nil
+}
@@ -0,0 +1,103 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSetLoop(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, want := decodeFresh(t, data)
+
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetLoop(2*time.Second, 3*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	start, end := 2*44100*4, 3*44100*4
+	// An odd buffer size makes reads straddle the loop end.
+	got := make([]byte, end+2*(end-start))
+	buf := make([]byte, 10001*4)
+	for n := 0; n < len(got); {
+		m, err := d.Read(buf[:min(len(buf), len(got)-n)])
+		if err != nil {
+			t.Fatal(err)
+		}
+		n += copy(got[n:], buf[:m])
+	}
+	loop := want[start:end]
+	if !bytes.Equal(got, slices.Concat(want[:end], loop, loop)) {
+		t.Error("looped PCM differs from the decoded region")
+	}
+
+	// Removing the loop plays on past its end.
+	if err := d.SetLoop(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rest, want[end:]) {
+		t.Error("PCM after removing the loop differs")
+	}
+}
+
+func TestSetLoop_DecodeFrame(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, want := decodeFresh(t, data)
+
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetLoop(time.Second, 2*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	start, end := 44100*4, 2*44100*4
+	var got []byte
+	for len(got) < end+2*(end-start) {
+		_, pcm, err := d.DecodeFrame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, pcm...)
+	}
+	loop := want[start:end]
+	if !bytes.HasPrefix(got, slices.Concat(want[:end], loop, loop)) {
+		t.Error("looped PCM of DecodeFrame differs from the decoded region")
+	}
+}
+
+func TestSetLoop_Errors(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetLoop(3*time.Second, 2*time.Second); err == nil {
+		t.Error("SetLoop() with start after end succeeded")
+	}
+	d, err = NewDecoder(&nonSeekableReader{r: bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetLoop(time.Second, 2*time.Second); err == nil {
+		t.Error("SetLoop() on a non-seekable source succeeded")
+	}
+}
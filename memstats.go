@@ -0,0 +1,56 @@
+package mp3
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/llehouerou/go-mp3/internal/frameheader"
+)
+
+// MemStats describes the memory held by a Decoder for its input, for
+// embedders on constrained devices monitoring and tuning its footprint.
+// Sizes are in bytes and count the allocated capacity of the buffers.
+type MemStats struct {
+	// Frames is the number of frames in the frame index, and IndexBytes the
+	// size of the index. The index is built for seekable sources only.
+	Frames     int
+	IndexBytes int
+
+	// PCMBytes is the size of the buffers of decoded PCM, including the
+	// partial block of WithBlockSize and the PCM converted to 8-bit output
+	// formats.
+	PCMBytes int
+
+	// InputBytes is the size of the buffers of input read ahead of the
+	// decoding.
+	InputBytes int
+
+	// ReservoirBytes is the size of the main data of the current frame
+	// along with the bit reservoir of the previous frames.
+	ReservoirBytes int
+}
+
+// MemStats returns the memory currently held by d. Limits on most of it can
+// be set with WithLimits.
+func (d *Decoder) MemStats() MemStats {
+	s := MemStats{
+		Frames: len(d.frameStarts),
+		IndexBytes: cap(d.frameStarts)*int(unsafe.Sizeof(int64(0))) +
+			cap(d.frameHeaders)*int(unsafe.Sizeof(frameheader.FrameHeader(0))) +
+			cap(d.frameOffsets)*int(unsafe.Sizeof(int64(0))) +
+			cap(d.frameTimes)*int(unsafe.Sizeof(time.Duration(0))) +
+			cap(d.rateSegments)*int(unsafe.Sizeof(rateSegment{})),
+		PCMBytes:   len(d.buf.data) + cap(d.blockTail) + cap(d.converted),
+		InputBytes: cap(d.source.buf) + cap(d.source.record),
+	}
+	if r := d.resampler; r != nil {
+		s.PCMBytes += cap(r.x)*int(unsafe.Sizeof(r.x[0])) + len(r.raw)
+	}
+	if d.async != nil {
+		s.InputBytes += cap(d.async.data)
+	}
+	if d.frame != nil {
+		s.ReservoirBytes = d.frame.MainDataSize()
+	}
+	return s
+}
@@ -0,0 +1,45 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestMemStats(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(d, make([]byte, 10000)); err != nil {
+		t.Fatal(err)
+	}
+	s := d.MemStats()
+	if s.Frames != d.FrameCount() {
+		t.Errorf("Frames = %d, want %d", s.Frames, d.FrameCount())
+	}
+	// Each frame takes at least its start, header and offset.
+	if s.IndexBytes < s.Frames*20 {
+		t.Errorf("IndexBytes = %d, want at least %d", s.IndexBytes, s.Frames*20)
+	}
+	if s.PCMBytes < 4*1152 {
+		t.Errorf("PCMBytes = %d, want at least a frame", s.PCMBytes)
+	}
+	if s.ReservoirBytes <= 0 || s.ReservoirBytes > 2048 {
+		t.Errorf("ReservoirBytes = %d, want up to 2 KB", s.ReservoirBytes)
+	}
+
+	// A non-seekable source has no index.
+	d, err = NewDecoder(io.MultiReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.MemStats(); s.Frames != 0 || s.IndexBytes != 0 {
+		t.Errorf("MemStats() = %+v, want no index", s)
+	}
+}
@@ -0,0 +1,95 @@
+package mp3
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/llehouerou/go-mp3/id3v2"
+)
+
+// ErrChapterNotFound is returned by SeekToChapter when the stream has no
+// chapter with the requested ID.
+var ErrChapterNotFound = errors.New("mp3: chapter not found")
+
+// Metadata returns the ID3v2 tag found at the start of the stream, or nil if
+// there is none or it could not be parsed. When the stream starts with
+// several tags, the first one is returned.
+func (d *Decoder) Metadata() *id3v2.Tag {
+	return d.metadata
+}
+
+func (d *Decoder) parseMetadata(tag []byte) {
+	if d.metadata != nil {
+		return
+	}
+	t, err := id3v2.Parse(tag)
+	if err != nil {
+		d.logger.Warn("mp3: failed to parse ID3v2 tag", slog.Any("error", err))
+		return
+	}
+	d.metadata = t
+}
+
+// Chapters returns the ID3v2 chapters (CHAP frames) of the stream in
+// playback order, or nil if there are none.
+func (d *Decoder) Chapters() []id3v2.Chapter {
+	if d.metadata == nil {
+		return nil
+	}
+	return d.metadata.Chapters()
+}
+
+// SeekToChapter seeks to the start of the chapter with the given element ID.
+// It returns ErrChapterNotFound if there is no such chapter, and an error if
+// seeking is not supported.
+func (d *Decoder) SeekToChapter(id string) error {
+	if d.metadata == nil {
+		return ErrChapterNotFound
+	}
+	c := d.metadata.Chapter(id)
+	if c == nil {
+		return ErrChapterNotFound
+	}
+	return d.SeekToTime(c.Start + d.bytesToDuration(d.priming))
+}
+
+// CurrentLyric returns the line of the synchronised lyrics (SYLT) of the
+// stream that is being sung at Position. ok is false if the stream has no
+// synchronised lyrics or the first line has not started yet.
+//
+// When the tag holds several SYLT frames, lyrics (content type 1) are
+// preferred over other kinds of text.
+func (d *Decoder) CurrentLyric() (line id3v2.LyricLine, ok bool) {
+	s := d.syncedLyrics()
+	if s == nil {
+		return id3v2.LyricLine{}, false
+	}
+	pos := d.bytesToDuration(d.playedPos() - d.priming)
+	i := s.LineAt(pos, d.bytesToDuration(d.bytesPerFrame))
+	if i < 0 {
+		return id3v2.LyricLine{}, false
+	}
+	return s.Lines[i], true
+}
+
+// syncedLyrics returns the synchronised lyrics of CurrentLyric, or nil. The
+// SYLT frames are parsed by the first call only, as CurrentLyric is polled
+// during playback.
+func (d *Decoder) syncedLyrics() *id3v2.SyncedLyrics {
+	if d.lyricsParsed || d.metadata == nil {
+		return d.lyrics
+	}
+	d.lyricsParsed = true
+	all := d.metadata.SyncedLyrics()
+	if len(all) == 0 {
+		return nil
+	}
+	d.lyrics = &all[0]
+	for i, l := range all {
+		if l.ContentType == 1 {
+			d.lyrics = &all[i]
+			break
+		}
+	}
+	return d.lyrics
+}
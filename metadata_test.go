@@ -0,0 +1,152 @@
+package mp3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// createID3v2TagWithFrames creates an ID3v2.4 tag holding the given frames,
+// each given as an ID followed by its content.
+func createID3v2TagWithFrames(frames ...[]byte) []byte {
+	var body []byte
+	for _, f := range frames {
+		body = append(body, f...)
+	}
+	tag := createID3v2Tag(4, len(body))
+	copy(tag[10:], body)
+	return tag
+}
+
+// createID3v2Frame creates an ID3v2.4 frame.
+func createID3v2Frame(id string, data []byte) []byte {
+	n := len(data)
+	b := append([]byte(id), byte(n>>21&0x7f), byte(n>>14&0x7f), byte(n>>7&0x7f), byte(n&0x7f), 0, 0)
+	return append(b, data...)
+}
+
+// createChapterFrame creates a CHAP frame with a TIT2 title.
+func createChapterFrame(id string, start, end time.Duration, title string) []byte {
+	b := append([]byte(id), 0)
+	b = binary.BigEndian.AppendUint32(b, uint32(start.Milliseconds()))
+	b = binary.BigEndian.AppendUint32(b, uint32(end.Milliseconds()))
+	b = binary.BigEndian.AppendUint32(b, 0xFFFFFFFF)
+	b = binary.BigEndian.AppendUint32(b, 0xFFFFFFFF)
+	b = append(b, createID3v2Frame("TIT2", append([]byte{3}, title...))...)
+	return createID3v2Frame("CHAP", b)
+}
+
+func TestMetadata_RealFile(t *testing.T) {
+	f, err := os.Open("example/classic.mp3")
+	if err != nil {
+		t.Fatalf("failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	d, err := NewDecoder(f)
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	m := d.Metadata()
+	if m == nil {
+		t.Fatal("Metadata() returned nil for a tagged file")
+	}
+	if got, want := m.Title(), "Mozart - Eine Kleine Nachtmusik allegro"; got != want {
+		t.Errorf("Title() = %q, want %q", got, want)
+	}
+	if got, want := m.Artist(), "Advent Chamber Orchestra"; got != want {
+		t.Errorf("Artist() = %q, want %q", got, want)
+	}
+	if got, want := m.Track(), "4"; got != want {
+		t.Errorf("Track() = %q, want %q", got, want)
+	}
+}
+
+func TestMetadata_Untagged(t *testing.T) {
+	d, err := NewDecoder(bytes.NewReader(createMinimalMP3Frame()))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	if d.Metadata() != nil {
+		t.Error("Metadata() should be nil for an untagged stream")
+	}
+}
+
+func TestChapters_SeekToChapter(t *testing.T) {
+	audio, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	tag := createID3v2TagWithFrames(
+		createChapterFrame("intro", 0, 2*time.Second, "Intro"),
+		createChapterFrame("main", 2*time.Second, 5*time.Second, "Main"),
+	)
+	d, err := NewDecoder(bytes.NewReader(append(tag, audio...)))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+
+	chapters := d.Chapters()
+	if len(chapters) != 2 {
+		t.Fatalf("got %d chapters, want 2", len(chapters))
+	}
+	if chapters[1].Title() != "Main" {
+		t.Errorf("second chapter title = %q, want Main", chapters[1].Title())
+	}
+
+	if err := d.SeekToChapter("main"); err != nil {
+		t.Fatalf("SeekToChapter failed: %v", err)
+	}
+	if got := d.Position(); got < 2*time.Second-time.Millisecond || got > 2*time.Second {
+		t.Errorf("Position() after SeekToChapter = %v, want 2s", got)
+	}
+
+	if err := d.SeekToChapter("missing"); !errors.Is(err, ErrChapterNotFound) {
+		t.Errorf("SeekToChapter(missing) = %v, want ErrChapterNotFound", err)
+	}
+}
+
+func TestCurrentLyric(t *testing.T) {
+	audio, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	sylt := []byte{3, 'e', 'n', 'g', 2, 1, 0}
+	for _, l := range []struct {
+		text string
+		ms   uint32
+	}{{"Hello", 1000}, {"world", 3000}} {
+		sylt = append(sylt, l.text...)
+		sylt = append(sylt, 0)
+		sylt = binary.BigEndian.AppendUint32(sylt, l.ms)
+	}
+	tag := createID3v2TagWithFrames(createID3v2Frame("SYLT", sylt))
+	d, err := NewDecoder(bytes.NewReader(append(tag, audio...)))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+
+	if _, ok := d.CurrentLyric(); ok {
+		t.Error("CurrentLyric() at the start should report no line")
+	}
+	if err := d.SeekToTime(2 * time.Second); err != nil {
+		t.Fatalf("SeekToTime failed: %v", err)
+	}
+	if line, ok := d.CurrentLyric(); !ok || line.Text != "Hello" {
+		t.Errorf("CurrentLyric() at 2s = %q, %v, want Hello", line.Text, ok)
+	}
+	if err := d.SeekToTime(4 * time.Second); err != nil {
+		t.Fatalf("SeekToTime failed: %v", err)
+	}
+	if line, ok := d.CurrentLyric(); !ok || line.Text != "world" {
+		t.Errorf("CurrentLyric() at 4s = %q, %v, want world", line.Text, ok)
+	}
+
+	// The lyrics are parsed once, so polling does not allocate.
+	if allocs := testing.AllocsPerRun(100, func() { d.CurrentLyric() }); allocs != 0 {
+		t.Errorf("CurrentLyric allocates %v times", allocs)
+	}
+}
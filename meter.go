@@ -0,0 +1,50 @@
+package mp3
+
+import "math"
+
+// A FrameLevel holds the levels of the PCM of a decoded frame, on a scale
+// where full-scale samples have a level of 1.
+type FrameLevel struct {
+	// Sample is the index of the first sample of the frame in the decoded
+	// stream, and Samples the number of samples per channel.
+	Sample  int64
+	Samples int
+
+	// RMS and Peak are the root mean square and the largest absolute value
+	// of the samples of each channel. They are measured before the
+	// conversion to integers, so a Peak above 1 means that samples clip.
+	RMS  [2]float64
+	Peak [2]float64
+}
+
+// A MeterFunc receives the levels of the frames decoded by a Decoder.
+type MeterFunc func(l FrameLevel)
+
+// SetMeterFunc sets a function called with the levels of each frame decoded
+// by Read and DecodeFrame, so that level meters and silence detectors can be
+// driven without a second pass over the PCM. The levels are those of the
+// output, after gain, karaoke and channel muting. The warmup frames decoded
+// by Seek are not reported. A nil fn removes the function.
+//
+// Like SetChannelEnabled, the setting applies from the next decoded frame.
+func (d *Decoder) SetMeterFunc(fn MeterFunc) {
+	d.meter = fn
+}
+
+// meterFrame calls the MeterFunc with the levels of the n samples of d.pcm.
+func (d *Decoder) meterFrame(n int) {
+	l := FrameLevel{Sample: d.frameSample, Samples: n}
+	for ch := range d.pcm {
+		var sum, peak float64
+		for _, v := range d.pcm[ch][:n] {
+			s := float64(v)
+			sum += s * s
+			peak = max(peak, math.Abs(s))
+		}
+		if n > 0 {
+			l.RMS[ch] = math.Sqrt(sum / float64(n))
+		}
+		l.Peak[ch] = peak
+	}
+	d.meter(l)
+}
@@ -0,0 +1,58 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"os"
+	"testing"
+)
+
+func TestSetMeterFunc(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, pcm := decodeFresh(t, data)
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var levels []FrameLevel
+	d.SetMeterFunc(func(l FrameLevel) { levels = append(levels, l) })
+	d.SetChannelEnabled(1, false)
+	if _, err := io.Copy(io.Discard, d); err != nil {
+		t.Fatal(err)
+	}
+	if len(levels) != d.FrameCount() {
+		t.Fatalf("got %d levels, want %d", len(levels), d.FrameCount())
+	}
+	for i, l := range levels {
+		if l.Sample != int64(i)*1152 || l.Samples != 1152 {
+			t.Fatalf("frame %d: sample %d, %d samples", i, l.Sample, l.Samples)
+		}
+		var sum float64
+		peak := 0
+		for j := l.Sample * 4; j < (l.Sample+1152)*4; j += 4 {
+			s := int(int16(uint16(pcm[j]) | uint16(pcm[j+1])<<8))
+			sum += float64(s * s)
+			peak = max(peak, abs(s))
+		}
+		rms := math.Sqrt(sum/1152) / 32767
+		if math.Abs(l.RMS[0]-rms) > 1e-3 || abs(int(math.Min(l.Peak[0], 1)*32767)-peak) > 1 {
+			t.Fatalf("frame %d: RMS %g, peak %g, want %g and %g", i, l.RMS[0], l.Peak[0], rms, float64(peak)/32767)
+		}
+		if l.RMS[1] != 0 || l.Peak[1] != 0 {
+			t.Fatalf("frame %d: muted channel has RMS %g, peak %g", i, l.RMS[1], l.Peak[1])
+		}
+	}
+
+	// Warmup frames of Seek are not reported.
+	levels = levels[:0]
+	if _, err := d.Seek(100*d.BytesPerFrame(), io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if len(levels) != 1 || levels[0].Sample != 100*1152 {
+		t.Errorf("Seek reported %d levels, want those of frame 100", len(levels))
+	}
+}
@@ -0,0 +1,292 @@
+package mp3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"time"
+
+	"github.com/llehouerou/go-mp3/internal/frame"
+	"github.com/llehouerou/go-mp3/internal/frameheader"
+)
+
+// A MonitorEventType identifies the kind of a MonitorEvent.
+type MonitorEventType int
+
+const (
+	// EventSilence is emitted once when the stream has stayed below the
+	// silence threshold for longer than the configured silence duration.
+	EventSilence MonitorEventType = iota
+
+	// EventBitrateDropped is emitted when the frame bitrate falls below the
+	// configured minimum bitrate. It is emitted again only after the bitrate
+	// has recovered.
+	EventBitrateDropped
+
+	// EventSyncLost is emitted when bytes had to be skipped to find the next
+	// frame header.
+	EventSyncLost
+
+	// EventFormatChanged is emitted when the sample rate, channel count or
+	// MPEG version differs from the previous frame.
+	EventFormatChanged
+)
+
+// String returns the name of the event type.
+func (t MonitorEventType) String() string {
+	switch t {
+	case EventSilence:
+		return "silence"
+	case EventBitrateDropped:
+		return "bitrate dropped"
+	case EventSyncLost:
+		return "sync lost"
+	case EventFormatChanged:
+		return "format changed"
+	}
+	return "unknown"
+}
+
+// A MonitorEvent describes a notable condition detected by a Monitor.
+type MonitorEvent struct {
+	Type MonitorEventType
+
+	// At is the stream time at which the event was detected, counted from
+	// the first frame the Monitor saw.
+	At time.Duration
+
+	// Offset is the byte offset in the monitored stream of the frame that
+	// triggered the event.
+	Offset int64
+
+	// Bitrate, SampleRate and Channels describe the frame that triggered
+	// the event.
+	Bitrate    int
+	SampleRate int
+	Channels   int
+
+	// Silence is the length of the silent stretch for EventSilence.
+	Silence time.Duration
+
+	// Skipped is the number of bytes skipped for EventSyncLost.
+	Skipped int64
+}
+
+// MonitorConfig configures a Monitor. Zero fields take their defaults.
+type MonitorConfig struct {
+	// SilenceThreshold is the peak level in dBFS below which a frame is
+	// considered silent. The default is -60.
+	SilenceThreshold float64
+
+	// SilenceDuration is how long the stream must stay silent before
+	// EventSilence is emitted. The default is 5 seconds.
+	SilenceDuration time.Duration
+
+	// MinBitrate is the bitrate in bits per second below which
+	// EventBitrateDropped is emitted. Zero disables the check.
+	MinBitrate int
+
+	// OnEvent receives events as they are detected. It is called
+	// synchronously from Write and Run.
+	OnEvent func(MonitorEvent)
+}
+
+// MonitorStats summarizes the frames seen by a Monitor so far.
+type MonitorStats struct {
+	Frames     int64
+	Bytes      int64
+	Skipped    int64
+	MinBitrate int
+	MaxBitrate int
+	Elapsed    time.Duration
+}
+
+// A Monitor consumes a live MP3 stream and reports events such as long
+// silences, bitrate drops, lost sync and stream parameter changes.
+//
+// Frames are decoded only to measure their level; no PCM is retained.
+// A Monitor is not safe for concurrent use.
+type Monitor struct {
+	cfg    MonitorConfig
+	framer *Framer
+	prev   *frame.Frame
+
+	silenceLevel  float64
+	silence       time.Duration
+	silenceSent   bool
+	bitrateLow    bool
+	lastHeader    frameheader.FrameHeader
+	lastSkipped   int64
+	offset        int64
+	stats         MonitorStats
+	statsHasFrame bool
+}
+
+// NewMonitor returns a Monitor using cfg.
+func NewMonitor(cfg MonitorConfig) *Monitor {
+	if cfg.SilenceThreshold == 0 {
+		cfg.SilenceThreshold = -60
+	}
+	if cfg.SilenceDuration == 0 {
+		cfg.SilenceDuration = 5 * time.Second
+	}
+	return &Monitor{
+		cfg:          cfg,
+		framer:       NewFramer(),
+		silenceLevel: 32767 * math.Pow(10, cfg.SilenceThreshold/20),
+	}
+}
+
+// Write feeds stream data to the Monitor and processes every frame that
+// becomes complete. Write always consumes all of p and never returns an
+// error.
+func (m *Monitor) Write(p []byte) (int, error) {
+	_, _ = m.framer.Write(p)
+	m.processFrames()
+	return len(p), nil
+}
+
+// processFrames processes the frames available from the framer.
+func (m *Monitor) processFrames() {
+	for {
+		raw, ok := m.framer.Next()
+		if !ok {
+			return
+		}
+		m.process(raw)
+	}
+}
+
+// Run reads r until EOF or until ctx is done, feeding everything to the
+// Monitor. It returns nil at EOF.
+func (m *Monitor) Run(ctx context.Context, r io.Reader) error {
+	buf := make([]byte, 32*1024)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := r.Read(buf)
+		if n > 0 {
+			_, _ = m.Write(buf[:n])
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				// Process the last frame.
+				_ = m.framer.Close()
+				m.processFrames()
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Stats returns statistics about the frames processed so far.
+func (m *Monitor) Stats() MonitorStats {
+	s := m.stats
+	s.Skipped = m.framer.Skipped()
+	return s
+}
+
+func (m *Monitor) process(raw []byte) {
+	h := headerFromBytes(raw)
+	sampleRate, _ := h.SamplingFrequencyValue()
+	ev := MonitorEvent{
+		At:         m.stats.Elapsed,
+		Bitrate:    h.Bitrate(),
+		SampleRate: sampleRate,
+		Channels:   h.NumberOfChannels(),
+	}
+
+	skipped := m.framer.Skipped()
+	m.offset += skipped - m.lastSkipped
+	ev.Offset = m.offset
+	if skipped > m.lastSkipped && m.statsHasFrame {
+		e := ev
+		e.Type = EventSyncLost
+		e.Skipped = skipped - m.lastSkipped
+		m.emit(e)
+	}
+	m.lastSkipped = skipped
+
+	if m.statsHasFrame && formatChanged(m.lastHeader, h) {
+		e := ev
+		e.Type = EventFormatChanged
+		m.emit(e)
+		// The reservoir and filterbank state of the old format are useless.
+		m.prev = nil
+	}
+
+	if m.cfg.MinBitrate > 0 {
+		if h.Bitrate() < m.cfg.MinBitrate {
+			if !m.bitrateLow {
+				e := ev
+				e.Type = EventBitrateDropped
+				m.emit(e)
+				m.bitrateLow = true
+			}
+		} else {
+			m.bitrateLow = false
+		}
+	}
+
+	f, pcm, err := decodeRawFrame(raw, m.prev)
+	if err == nil {
+		m.prev = f
+		if peakLevel(pcm) <= m.silenceLevel {
+			m.silence += h.FrameDuration()
+			if m.silence > m.cfg.SilenceDuration && !m.silenceSent {
+				e := ev
+				e.Type = EventSilence
+				e.Silence = m.silence
+				m.emit(e)
+				m.silenceSent = true
+			}
+		} else {
+			m.silence = 0
+			m.silenceSent = false
+		}
+	}
+
+	m.lastHeader = h
+	m.offset += int64(len(raw))
+	m.stats.Frames++
+	m.stats.Bytes += int64(len(raw))
+	m.stats.Elapsed += h.FrameDuration()
+	if !m.statsHasFrame || h.Bitrate() < m.stats.MinBitrate {
+		m.stats.MinBitrate = h.Bitrate()
+	}
+	if h.Bitrate() > m.stats.MaxBitrate {
+		m.stats.MaxBitrate = h.Bitrate()
+	}
+	m.statsHasFrame = true
+}
+
+func (m *Monitor) emit(e MonitorEvent) {
+	if m.cfg.OnEvent != nil {
+		m.cfg.OnEvent(e)
+	}
+}
+
+// formatChanged reports whether b describes a stream with different output
+// parameters than a.
+func formatChanged(a, b frameheader.FrameHeader) bool {
+	return a.ID() != b.ID() ||
+		a.SamplingFrequency() != b.SamplingFrequency() ||
+		a.NumberOfChannels() != b.NumberOfChannels()
+}
+
+// peakLevel returns the highest absolute sample value in 16-bit little
+// endian PCM.
+func peakLevel(pcm []byte) float64 {
+	peak := 0
+	for i := 0; i+1 < len(pcm); i += 2 {
+		s := int(int16(uint16(pcm[i]) | uint16(pcm[i+1])<<8))
+		if s < 0 {
+			s = -s
+		}
+		peak = max(peak, s)
+	}
+	return float64(peak)
+}
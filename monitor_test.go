@@ -0,0 +1,138 @@
+package mp3
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// createSilentFrame creates a silent MPEG1 Layer3 stereo frame whose third
+// header byte (bitrate, sampling frequency, padding) is b2.
+func createSilentFrame(t *testing.T, b2 byte) []byte {
+	t.Helper()
+	h := []byte{0xFF, 0xFB, b2, 0x44}
+	size := framerFrameSize(headerFromBytes(h))
+	if size == 0 {
+		t.Fatalf("invalid header %x", h)
+	}
+	frame := make([]byte, size)
+	copy(frame, h)
+	return frame
+}
+
+func collectEvents(m *MonitorConfig) *[]MonitorEvent {
+	var events []MonitorEvent
+	m.OnEvent = func(e MonitorEvent) {
+		events = append(events, e)
+	}
+	return &events
+}
+
+func countEvents(events []MonitorEvent, typ MonitorEventType) int {
+	n := 0
+	for _, e := range events {
+		if e.Type == typ {
+			n++
+		}
+	}
+	return n
+}
+
+func TestMonitor_Silence(t *testing.T) {
+	cfg := MonitorConfig{SilenceDuration: time.Second}
+	events := collectEvents(&cfg)
+	m := NewMonitor(cfg)
+	frame := createMinimalMP3Frame()
+	// About 2.6 seconds of silence.
+	for range 100 {
+		_, _ = m.Write(frame)
+	}
+	if got := countEvents(*events, EventSilence); got != 1 {
+		t.Fatalf("got %d silence events, want 1", got)
+	}
+	for _, e := range *events {
+		if e.Type == EventSilence && e.Silence <= time.Second {
+			t.Errorf("silence event reported %v, want more than 1s", e.Silence)
+		}
+	}
+}
+
+func TestMonitor_NoSilenceOnMusic(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	cfg := MonitorConfig{SilenceDuration: time.Second}
+	events := collectEvents(&cfg)
+	m := NewMonitor(cfg)
+	if err := m.Run(context.Background(), bytes.NewReader(data)); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if got := countEvents(*events, EventSilence); got != 0 {
+		t.Errorf("got %d silence events on music, want 0", got)
+	}
+	if got := countEvents(*events, EventFormatChanged); got != 0 {
+		t.Errorf("got %d format change events, want 0", got)
+	}
+	st := m.Stats()
+	if st.Frames == 0 || st.Bytes == 0 {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+}
+
+func TestMonitor_SyncLostAndFormatChange(t *testing.T) {
+	cfg := MonitorConfig{}
+	events := collectEvents(&cfg)
+	m := NewMonitor(cfg)
+
+	frame44k := createSilentFrame(t, 0x90)
+	frame48k := createSilentFrame(t, 0x94)
+	_, _ = m.Write(frame44k)
+	_, _ = m.Write(frame44k)
+	_, _ = m.Write([]byte("junk"))
+	_, _ = m.Write(frame44k)
+	_, _ = m.Write(frame48k)
+
+	if got := countEvents(*events, EventSyncLost); got != 1 {
+		t.Errorf("got %d sync lost events, want 1", got)
+	}
+	if got := countEvents(*events, EventFormatChanged); got != 1 {
+		t.Errorf("got %d format change events, want 1", got)
+	}
+	for _, e := range *events {
+		switch e.Type {
+		case EventSyncLost:
+			if e.Skipped != 4 {
+				t.Errorf("sync lost skipped = %d, want 4", e.Skipped)
+			}
+			if want := int64(2*len(frame44k) + 4); e.Offset != want {
+				t.Errorf("sync lost offset = %d, want %d", e.Offset, want)
+			}
+		case EventFormatChanged:
+			if e.SampleRate != 48000 {
+				t.Errorf("format change sample rate = %d, want 48000", e.SampleRate)
+			}
+		}
+	}
+}
+
+func TestMonitor_BitrateDropped(t *testing.T) {
+	cfg := MonitorConfig{MinBitrate: 96000}
+	events := collectEvents(&cfg)
+	m := NewMonitor(cfg)
+
+	high := createSilentFrame(t, 0x90) // 128 kbps
+	low := createSilentFrame(t, 0x50)  // 64 kbps
+	for _, f := range [][]byte{high, low, low, high, low} {
+		_, _ = m.Write(f)
+	}
+	if got := countEvents(*events, EventBitrateDropped); got != 2 {
+		t.Errorf("got %d bitrate events, want 2", got)
+	}
+	st := m.Stats()
+	if st.MinBitrate != 64000 || st.MaxBitrate != 128000 {
+		t.Errorf("bitrate range = %d-%d, want 64000-128000", st.MinBitrate, st.MaxBitrate)
+	}
+}
@@ -0,0 +1,80 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+
+	"github.com/llehouerou/go-mp3/lameinfo"
+)
+
+var (
+	// ErrNoMusicCRC is returned by VerifyMusicCRC when the stream has no
+	// LAME tag.
+	ErrNoMusicCRC = errors.New("mp3: no LAME tag with a music CRC")
+
+	// ErrMusicCRCMismatch is returned by VerifyMusicCRC when the frames of
+	// the stream do not match the music CRC of its LAME tag.
+	ErrMusicCRCMismatch = errors.New("mp3: music CRC mismatch")
+)
+
+// VerifyMusicCRC checks the frames of the stream against the music CRC that
+// LAME stores in its tag, so that archivists can detect files damaged since
+// they were encoded. The CRC covers the encoded frames after the Xing header
+// frame, not the decoded audio, so they are read without being decoded; tags
+// are not covered and can be edited freely.
+//
+// VerifyMusicCRC returns nil if the frames match, ErrMusicCRCMismatch if
+// they do not or the stream is shorter than the music length of the tag, and
+// ErrNoMusicCRC if the stream has no LAME tag. It returns an error if the
+// source is not io.Seeker. The position of the decoder is left unchanged.
+func (d *Decoder) VerifyMusicCRC() error {
+	if d.length == invalidLength {
+		return errors.New("mp3: VerifyMusicCRC requires a seekable source")
+	}
+	pos := d.source.pos
+	defer func() {
+		_, _ = d.source.Seek(pos, io.SeekStart)
+	}()
+	info := d.xing
+	if info == nil || !info.HasLAMEInfo() {
+		return ErrNoMusicCRC
+	}
+
+	start := d.frameStarts[0]
+	xingSize, err := d.frameHeaders[0].FrameSize()
+	if err != nil {
+		return err
+	}
+	end := start + int64(info.MusicLength)
+	if info.MusicLength == 0 {
+		// The length is unknown: check up to the end of the last frame.
+		last := len(d.frameStarts) - 1
+		size, err := d.frameHeaders[last].FrameSize()
+		if err != nil {
+			return err
+		}
+		end = d.frameStarts[last] + int64(size)
+	}
+	if _, err := d.source.Seek(start+int64(xingSize), io.SeekStart); err != nil {
+		return err
+	}
+	var crc crcWriter
+	if _, err := io.CopyN(&crc, d.source.reader, end-start-int64(xingSize)); err != nil {
+		if errors.Is(err, io.EOF) {
+			return ErrMusicCRCMismatch
+		}
+		return err
+	}
+	if uint16(crc) != info.MusicCRC {
+		return ErrMusicCRCMismatch
+	}
+	return nil
+}
+
+// A crcWriter computes the CRC of the LAME tag of the bytes written to it.
+type crcWriter uint16
+
+func (c *crcWriter) Write(p []byte) (int, error) {
+	*c = crcWriter(lameinfo.UpdateCRC(uint16(*c), p))
+	return len(p), nil
+}
@@ -0,0 +1,74 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestVerifyMusicCRC(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.VerifyMusicCRC(); err != nil {
+		t.Errorf("VerifyMusicCRC() = %v, want nil", err)
+	}
+	// The position is left unchanged.
+	pcm, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(pcm)) != d.Length() {
+		t.Errorf("read %d bytes after VerifyMusicCRC, want %d", len(pcm), d.Length())
+	}
+}
+
+func TestVerifyMusicCRC_Damaged(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"flipped bit", func() []byte {
+			b := bytes.Clone(data)
+			b[len(b)/2] ^= 0x10
+			return b
+		}()},
+		{"truncated", data[:len(data)-1000]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDecoder(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := d.VerifyMusicCRC(); !errors.Is(err, ErrMusicCRCMismatch) {
+				t.Errorf("VerifyMusicCRC() = %v, want ErrMusicCRCMismatch", err)
+			}
+		})
+	}
+}
+
+func TestVerifyMusicCRC_NoLAMETag(t *testing.T) {
+	data, err := os.ReadFile("example/classic.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.VerifyMusicCRC(); !errors.Is(err, ErrNoMusicCRC) {
+		t.Errorf("VerifyMusicCRC() = %v, want ErrNoMusicCRC", err)
+	}
+}
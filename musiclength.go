@@ -0,0 +1,29 @@
+package mp3
+
+import "log/slog"
+
+// boundByMusicLength ends the audio of a seekable source where the music
+// length of the LAME tag of the first frame, found at pos, says the frames
+// end, so that data after them is never taken for frames. The tag is only
+// trusted if its CRC matches.
+func (d *Decoder) boundByMusicLength(pos int64) {
+	info := d.xing
+	if info == nil || !info.HasLAMEInfo() || !info.Valid || info.MusicLength == 0 {
+		return
+	}
+	d.musicEnd = pos + int64(info.MusicLength)
+	if d.audioEnd < 0 || d.musicEnd < d.audioEnd {
+		d.audioEnd = d.musicEnd
+	}
+}
+
+// checkMusicLength reports to the logger when the frames do not end where
+// the music length of the LAME tag says, as when the file was cut or edited
+// after encoding.
+func (d *Decoder) checkMusicLength() {
+	if d.musicEnd == 0 || d.framesEnd == d.musicEnd {
+		return
+	}
+	d.logger.Warn("mp3: frames do not end at the music length of the LAME tag",
+		slog.Int64("framesEnd", d.framesEnd), slog.Int64("musicEnd", d.musicEnd))
+}
@@ -0,0 +1,58 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMusicLength_BoundsFrames(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, want := decodeFresh(t, data)
+
+	// Garbage made of frames after the audio would otherwise be decoded.
+	garbage := data[d.frameStarts[10]:d.frameStarts[20]]
+	data = append(data[:len(data):len(data)], garbage...)
+
+	var logs bytes.Buffer
+	d, err = NewDecoder(bytes.NewReader(data), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if d.Length() != int64(len(want)) {
+		t.Errorf("Length = %d, want %d", d.Length(), len(want))
+	}
+	got, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got %d bytes, want %d", len(got), len(want))
+	}
+	if logs.Len() > 0 {
+		t.Errorf("unexpected warnings: %q", logs.String())
+	}
+}
+
+func TestMusicLength_WarnsOnMismatch(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, _ := decodeFresh(t, data)
+	data = data[:d.frameStarts[len(d.frameStarts)-1]]
+
+	var logs bytes.Buffer
+	if _, err := NewDecoder(bytes.NewReader(data), WithLogger(slog.New(slog.NewTextHandler(&logs, nil)))); err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if !strings.Contains(logs.String(), "music length") {
+		t.Errorf("expected a music length warning, got logs: %q", logs.String())
+	}
+}
@@ -0,0 +1,86 @@
+package mp3
+
+import (
+	"context"
+	"log/slog"
+)
+
+// An Option configures optional behavior of a Decoder created by NewDecoder.
+type Option func(*config)
+
+type config struct {
+	logger         *slog.Logger
+	reservoirFree  bool
+	liveContext    context.Context
+	primingSamples int
+	blockSamples   int
+	contentLength  int64
+	transform      TransformFunc
+	seekWarmup     int
+	seekMode       SeekMode
+	outFormat      OutputFormat
+	gain           float64
+	dither         Dither
+	halfRate       bool
+	skipSilence    bool
+	silenceLevel   float64
+	limits         Limits
+	outRate        int
+	tagFunc        TagFunc
+	truncatedFrame TruncatedFrame
+	paddingTrim    bool
+	scanProgress   ScanProgressFunc
+	deferFirst     bool
+
+	rateChangeError bool
+}
+
+func newConfig(opts []Option) config {
+	var c config
+	for _, o := range opts {
+		o(&c)
+	}
+	if c.logger == nil {
+		c.logger = slog.New(slog.DiscardHandler)
+	}
+	return c
+}
+
+// WithLogger sets the logger that receives recoverable problems the decoder
+// otherwise handles silently, such as a truncated last frame or trailing
+// data in which no frame header could be found.
+//
+// These conditions are reported at the Warn level. By default nothing is
+// logged.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
+
+// WithReservoirFree makes the decoder decode every frame using only the data
+// stored inside that frame, ignoring the bit reservoir shared with earlier
+// frames.
+//
+// This trades quality for random access: seeking no longer decodes the
+// preceding frame, and the spectral data of a frame does not depend on which
+// frames were decoded before it. The output is only an approximation of the real
+// audio, since granules whose data begins in an earlier frame are replaced by
+// silence. It is meant for scrubbing previews and waveform overviews, not for
+// playback.
+func WithReservoirFree() Option {
+	return func(c *config) {
+		c.reservoirFree = true
+	}
+}
+
+// WithPrimingSilence makes the decoder output the given number of samples
+// (per channel) of silence before the audio, for output pipelines that need
+// time to warm up. The silence is part of the stream: Length, Duration and
+// Position include it, and seeking into it is possible. Chapter and lyric
+// times stay relative to the audio.
+func WithPrimingSilence(samples int) Option {
+	return func(c *config) {
+		c.primingSamples = max(samples, 0)
+	}
+}
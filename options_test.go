@@ -0,0 +1,183 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWithLogger_ReportsTruncatedFrame(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	// Cut the file in the middle of a frame.
+	data = data[:len(data)/2+7]
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	d, err := NewDecoder(bytes.NewReader(data), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	if _, err := io.ReadAll(d); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	if !strings.Contains(logs.String(), "truncated frame") {
+		t.Errorf("expected a truncated frame warning, got logs: %q", logs.String())
+	}
+}
+
+func TestWithLogger_ReportsTrailingGarbage(t *testing.T) {
+	var data []byte
+	for range 10 {
+		data = append(data, createMinimalMP3Frame()...)
+	}
+	data = append(data, bytes.Repeat([]byte{0x55}, 100*1024)...)
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	d, err := NewDecoder(bytes.NewReader(data), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	if _, err := io.ReadAll(d); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	if !strings.Contains(logs.String(), "no frame header found") {
+		t.Errorf("expected a sync search warning, got logs: %q", logs.String())
+	}
+}
+
+func TestWithReservoirFree_ApproximatesOutput(t *testing.T) {
+	data, err := os.ReadFile("example/classic.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	data = data[:1<<20]
+
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	want, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	d, err = NewDecoder(bytes.NewReader(data), WithReservoirFree())
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	got, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("output length = %d, want %d", len(got), len(want))
+	}
+	if bytes.Equal(got, want) {
+		t.Error("reservoir-free output is identical to normal output; reservoir was not ignored")
+	}
+	if peakLevel(got) < 1000 {
+		t.Error("reservoir-free output is essentially silent")
+	}
+}
+
+func TestWithReservoirFree_SeekDoesNotDecodePreviousFrame(t *testing.T) {
+	f, err := os.Open("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	d, err := NewDecoder(f, WithReservoirFree())
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	target := 10*d.BytesPerFrame() + 400
+	if _, err := d.Seek(target, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	if got, want := int64(d.buf.Len()), d.BytesPerFrame()-400; got != want {
+		t.Errorf("buffered %d bytes after seek, want %d (a single frame)", got, want)
+	}
+	buf := make([]byte, 4096)
+	if _, err := io.ReadFull(d, buf); err != nil {
+		t.Fatalf("Read after seek failed: %v", err)
+	}
+	if pos, _ := d.Seek(0, io.SeekCurrent); pos != target+4096 {
+		t.Errorf("position = %d, want %d", pos, target+4096)
+	}
+}
+
+func TestWithPrimingSilence(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	ref, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	want, err := io.ReadAll(ref)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	const samples = 1000
+	const priming = 4 * samples
+	d, err := NewDecoder(bytes.NewReader(data), WithPrimingSilence(samples))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	if d.Length() != ref.Length()+priming {
+		t.Errorf("Length() = %d, want %d", d.Length(), ref.Length()+priming)
+	}
+	got, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(got[:priming], make([]byte, priming)) {
+		t.Error("output does not start with silence")
+	}
+	if !bytes.Equal(got[priming:], want) {
+		t.Error("audio after the silence differs from the plain decode")
+	}
+
+	// Seeking into the silence.
+	if _, err := d.Seek(priming/2, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	buf := make([]byte, 8192)
+	if _, err := io.ReadFull(d, buf); err != nil {
+		t.Fatalf("ReadFull failed: %v", err)
+	}
+	if !bytes.Equal(buf, got[priming/2:priming/2+len(buf)]) {
+		t.Error("output after seeking into the silence differs")
+	}
+
+	// Seeking into the audio matches seeking without silence.
+	if _, err := d.Seek(priming+100000, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	if _, err := ref.Seek(100000, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	refBuf := make([]byte, len(buf))
+	if _, err := io.ReadFull(d, buf); err != nil {
+		t.Fatalf("ReadFull failed: %v", err)
+	}
+	if _, err := io.ReadFull(ref, refBuf); err != nil {
+		t.Fatalf("ReadFull failed: %v", err)
+	}
+	if !bytes.Equal(buf, refBuf) {
+		t.Error("output after seeking into the audio differs")
+	}
+}
@@ -0,0 +1,172 @@
+package mp3
+
+import (
+	"encoding/binary"
+	"slices"
+)
+
+// An OutputFormat is the sample encoding of the PCM returned by Read and
+// DecodeFrame. Whatever the format, the output is interleaved stereo.
+type OutputFormat int
+
+const (
+	// OutputS16LE is 16-bit signed little-endian PCM. It is the default.
+	OutputS16LE OutputFormat = iota
+
+	// OutputU8 is 8-bit unsigned PCM, with silence at 128.
+	OutputU8
+
+	// OutputS8 is 8-bit signed PCM.
+	OutputS8
+
+	// OutputALaw is G.711 A-law, as used by European telephony.
+	OutputALaw
+
+	// OutputMuLaw is G.711 μ-law, as used by North American and Japanese
+	// telephony.
+	OutputMuLaw
+)
+
+// String returns the name of the format, such as "u8" or "alaw".
+func (f OutputFormat) String() string {
+	switch f {
+	case OutputS16LE:
+		return "s16le"
+	case OutputU8:
+		return "u8"
+	case OutputS8:
+		return "s8"
+	case OutputALaw:
+		return "alaw"
+	case OutputMuLaw:
+		return "mulaw"
+	}
+	return "unknown"
+}
+
+// BytesPerSample returns the size in bytes of a sample of one channel.
+func (f OutputFormat) BytesPerSample() int {
+	if f == OutputS16LE {
+		return 2
+	}
+	return 1
+}
+
+// WithOutputFormat sets the encoding of the PCM returned by Read and
+// DecodeFrame, such as the 8-bit G.711 codecs that telephony backends feed
+// into SIP stacks. Byte counts and offsets of the Decoder, such as those of
+// Length, BytesPerFrame and Seek, are in the output format.
+//
+// The 8-bit formats are converted from the 16-bit output, so they need no
+// extra decoding work. The sample rate is unchanged: telephony usually needs
+// the audio resampled to 8000 Hz as well.
+func WithOutputFormat(f OutputFormat) Option {
+	return func(c *config) {
+		c.outFormat = f
+	}
+}
+
+// OutputFormat returns the encoding of the PCM returned by Read, as set with
+// WithOutputFormat.
+func (d *Decoder) OutputFormat() OutputFormat {
+	return d.outFormat
+}
+
+// s16Bytes converts a byte count in the output format to 16-bit PCM.
+func (d *Decoder) s16Bytes(n int64) int64 {
+	return n * 2 / int64(d.outFormat.BytesPerSample())
+}
+
+// outputBytes converts a byte count in 16-bit PCM to the output format.
+func (d *Decoder) outputBytes(n int64) int64 {
+	if d.resampler != nil {
+		n = 4 * d.resampler.outSamples(n/4)
+	}
+	return n * int64(d.outFormat.BytesPerSample()) / 2
+}
+
+// readConverted implements Read for 8-bit output formats.
+func (d *Decoder) readConverted(buf []byte) (int, error) {
+	if exceeds(2*len(buf), d.limits.MaxBufferedPCM) {
+		buf = buf[:max(d.limits.MaxBufferedPCM/2, 1)]
+	}
+	d.converted = slices.Grow(d.converted[:0], 2*len(buf))[:2*len(buf)]
+	n, err := d.readS16(d.converted)
+	return encodeS16(buf, d.converted[:n], d.outFormat), err
+}
+
+// convertPCM returns 16-bit PCM in the output format. The result is only
+// valid until the next conversion.
+func (d *Decoder) convertPCM(pcm []byte) []byte {
+	if d.outFormat == OutputS16LE {
+		return pcm
+	}
+	// pcm can share its array with d.converted, so convert into a new one
+	// when it is too short.
+	out := d.converted
+	if cap(out) < len(pcm)/2 {
+		out = make([]byte, len(pcm)/2)
+	}
+	n := encodeS16(out[:len(pcm)/2], pcm, d.outFormat)
+	d.converted = out
+	return out[:n]
+}
+
+// encodeS16 encodes the 16-bit little-endian samples of src into dst in
+// format f and returns the number of bytes written.
+func encodeS16(dst, src []byte, f OutputFormat) int {
+	n := len(src) / 2
+	for i := range n {
+		s := int16(binary.LittleEndian.Uint16(src[2*i:]))
+		switch f {
+		case OutputU8:
+			dst[i] = byte(s>>8) + 128
+		case OutputS8:
+			dst[i] = byte(s >> 8)
+		case OutputALaw:
+			dst[i] = aLaw(s)
+		case OutputMuLaw:
+			dst[i] = muLaw(s)
+		}
+	}
+	return n
+}
+
+// muLaw encodes a sample in G.711 μ-law.
+func muLaw(s int16) byte {
+	const bias, clip = 0x84, 32635
+	sign := byte(0)
+	v := int(s)
+	if v < 0 {
+		v = -v
+		sign = 0x80
+	}
+	v = min(v, clip) + bias
+	exp := 7
+	for mask := 0x4000; v&mask == 0 && exp > 0; mask >>= 1 {
+		exp--
+	}
+	mantissa := byte(v>>(exp+3)) & 0x0f
+	return ^(sign | byte(exp)<<4 | mantissa)
+}
+
+// aLaw encodes a sample in G.711 A-law.
+func aLaw(s int16) byte {
+	sign := byte(0x80)
+	v := int(s) >> 3 // A-law encodes 13 bits
+	if v < 0 {
+		v = -v - 1
+		sign = 0
+	}
+	var b byte
+	if v < 32 {
+		b = byte(v >> 1)
+	} else {
+		exp := 1
+		for v >= 64<<(exp-1) && exp < 7 {
+			exp++
+		}
+		b = byte(exp)<<4 | byte(v>>exp)&0x0f
+	}
+	return (sign | b) ^ 0x55
+}
@@ -0,0 +1,141 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+// muLawDecode and aLawDecode are the G.711 reference expansions.
+func muLawDecode(u byte) int {
+	u = ^u
+	t := (int(u&0x0f)<<3 + 0x84) << (u & 0x70 >> 4)
+	if u&0x80 != 0 {
+		return 0x84 - t
+	}
+	return t - 0x84
+}
+
+func aLawDecode(a byte) int {
+	a ^= 0x55
+	t := int(a&0x0f) << 4
+	switch seg := int(a & 0x70 >> 4); seg {
+	case 0:
+		t += 8
+	case 1:
+		t += 0x108
+	default:
+		t = (t + 0x108) << (seg - 1)
+	}
+	if a&0x80 != 0 {
+		return t
+	}
+	return -t
+}
+
+func TestG711_KnownValues(t *testing.T) {
+	for _, tt := range []struct {
+		s           int16
+		mu, a       byte
+		description string
+	}{
+		{0, 0xff, 0xd5, "silence"},
+		{32767, 0x80, 0xaa, "positive full scale"},
+		{-32768, 0x00, 0x2a, "negative full scale"},
+	} {
+		if got := muLaw(tt.s); got != tt.mu {
+			t.Errorf("muLaw(%s) = %#02x, want %#02x", tt.description, got, tt.mu)
+		}
+		if got := aLaw(tt.s); got != tt.a {
+			t.Errorf("aLaw(%s) = %#02x, want %#02x", tt.description, got, tt.a)
+		}
+	}
+}
+
+func TestG711_RoundTrip(t *testing.T) {
+	for s := -32768; s <= 32767; s += 7 {
+		limit := abs(s)/16 + 64
+		if got := muLawDecode(muLaw(int16(s))); abs(got-s) > limit {
+			t.Fatalf("μ-law round trip of %d = %d", s, got)
+		}
+		if got := aLawDecode(aLaw(int16(s))); abs(got-s) > limit {
+			t.Fatalf("A-law round trip of %d = %d", s, got)
+		}
+	}
+}
+
+func TestWithOutputFormat(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	ref, pcm := decodeFresh(t, data)
+
+	for _, format := range []OutputFormat{OutputU8, OutputS8, OutputALaw, OutputMuLaw} {
+		want := make([]byte, len(pcm)/2)
+		encodeS16(want, pcm, format)
+
+		d, err := NewDecoder(bytes.NewReader(data), WithOutputFormat(format))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Length() != ref.Length()/2 || d.BytesPerFrame() != ref.BytesPerFrame()/2 {
+			t.Errorf("%v: Length %d, BytesPerFrame %d", format, d.Length(), d.BytesPerFrame())
+		}
+		got, err := io.ReadAll(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%v: output differs from the converted 16-bit PCM", format)
+		}
+
+		// The offset is rounded down to the start of its 2-byte sample.
+		pos, err := d.Seek(100001, io.SeekStart)
+		if err != nil || pos != 100000 {
+			t.Fatalf("%v: Seek = %d, %v", format, pos, err)
+		}
+		buf := make([]byte, 999)
+		if _, err := io.ReadFull(d, buf); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf, want[100000:100999]) {
+			t.Errorf("%v: output after Seek differs", format)
+		}
+	}
+}
+
+func TestWithOutputFormat_DecodeFrameAndPriming(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data), WithOutputFormat(OutputU8), WithPrimingSilence(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, pcm, err := d.DecodeFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Offset != -1 || !bytes.Equal(pcm, bytes.Repeat([]byte{128}, 20)) {
+		t.Errorf("priming silence = %v, want 20 bytes of 128", pcm)
+	}
+	_, pcm, err = d.DecodeFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pcm) != 1152*2 {
+		t.Errorf("DecodeFrame returned %d bytes, want %d", len(pcm), 1152*2)
+	}
+}
+
+func TestOutputFormat_String(t *testing.T) {
+	if got := OutputMuLaw.String(); got != "mulaw" {
+		t.Errorf("OutputMuLaw.String() = %q", got)
+	}
+	if OutputS16LE.BytesPerSample() != 2 || OutputALaw.BytesPerSample() != 1 {
+		t.Error("unexpected BytesPerSample")
+	}
+}
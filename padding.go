@@ -0,0 +1,38 @@
+package mp3
+
+// WithPaddingTrim makes the decoder drop the padding the encoder added at
+// the end of the stream, as given by the LAME tag, so that the audio ends
+// with its last real sample instead of up to two thousand samples of
+// silence, which would otherwise break loop points. Unlike gapless
+// playback, the encoder delay at the start is kept. Length, Duration and
+// Seek exclude the trimmed samples.
+//
+// Streams without a LAME tag are not trimmed. For sources that are not
+// io.Seeker, the padding is trimmed only when the Xing header gives the
+// frame count.
+func WithPaddingTrim() Option {
+	return func(c *config) {
+		c.paddingTrim = true
+	}
+}
+
+// trimPadding ends the output before the encoder padding.
+func (d *Decoder) trimPadding() {
+	if d.xing == nil || !d.xing.HasLAMEInfo() {
+		return
+	}
+	samples := int64(d.xing.TotalPadding())
+	if d.halfRate {
+		// Half-rate decoding outputs half the samples.
+		samples /= 2
+	}
+	padding := 4 * samples
+	switch {
+	case d.length != invalidLength:
+		d.length = max(d.length-padding, d.priming)
+		d.trimEnd = d.length
+	case d.xing.HasFrameCount():
+		d.estimate.padding = padding
+		d.trimEnd = d.estimate.length() + d.priming
+	}
+}
@@ -0,0 +1,155 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/lameinfo"
+)
+
+func TestWithPaddingTrim(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	info, err := lameinfo.ParseFromReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseFromReader failed: %v", err)
+	}
+	_, full := decodeFresh(t, data)
+	want := full[:len(full)-4*info.TotalPadding()]
+
+	d, err := NewDecoder(bytes.NewReader(data), WithPaddingTrim())
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if d.Length() != int64(len(want)) {
+		t.Errorf("Length = %d, want %d", d.Length(), len(want))
+	}
+	got, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got %d bytes, want the first %d bytes of the untrimmed audio", len(got), len(want))
+	}
+
+	// Seeking near the end stops at the trimmed end too.
+	if _, err := d.Seek(-400, io.SeekEnd); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	got, err = io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll after Seek failed: %v", err)
+	}
+	if !bytes.Equal(got, want[len(want)-400:]) {
+		t.Errorf("got %d bytes after seeking, want the last 400", len(got))
+	}
+
+	d, err = NewDecoder(&nonSeekableReader{bytes.NewReader(data)}, WithPaddingTrim())
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	got, err = io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("non-seekable source: got %d bytes, want %d", len(got), len(want))
+	}
+}
+
+func TestWithPaddingTrim_NoLAMETag(t *testing.T) {
+	data, err := os.ReadFile("example/mpeg2.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, want := decodeFresh(t, data)
+
+	d, err := NewDecoder(bytes.NewReader(data), WithPaddingTrim())
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	got, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d bytes, want %d", len(got), len(want))
+	}
+}
+
+func TestWithPaddingTrim_DecodeFrame(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data), WithPaddingTrim())
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	want, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	d, err = NewDecoder(bytes.NewReader(data), WithPaddingTrim())
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	var got []byte
+	for {
+		_, pcm, err := d.DecodeFrame()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("DecodeFrame failed: %v", err)
+		}
+		got = append(got, pcm...)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("DecodeFrame returned %d bytes, want the %d bytes of Read", len(got), len(want))
+	}
+	if d.Position() != d.Duration() {
+		t.Errorf("Position = %v at the end, want Duration %v", d.Position(), d.Duration())
+	}
+}
+
+func TestWithPaddingTrim_HalfRate(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	info, err := lameinfo.ParseFromReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseFromReader failed: %v", err)
+	}
+	ref, err := NewDecoder(bytes.NewReader(data), WithHalfRate())
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	full, err := io.ReadAll(ref)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	// The padding is halved, in whole samples.
+	want := full[:len(full)-4*(info.TotalPadding()/2)]
+
+	for _, r := range []io.Reader{bytes.NewReader(data), &nonSeekableReader{bytes.NewReader(data)}} {
+		d, err := NewDecoder(r, WithHalfRate(), WithPaddingTrim())
+		if err != nil {
+			t.Fatalf("NewDecoder failed: %v", err)
+		}
+		got, err := io.ReadAll(d)
+		if err != nil {
+			t.Fatalf("ReadAll failed: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("got %d bytes, want the first %d bytes of the untrimmed audio", len(got), len(want))
+		}
+	}
+}
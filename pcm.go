@@ -0,0 +1,91 @@
+package mp3
+
+import (
+	"math"
+
+	"github.com/llehouerou/go-mp3/internal/frame"
+)
+
+// WithGain applies a gain, in dB, to the decoded samples before they are
+// converted to 16-bit integers, so that players can set the volume without
+// post-processing the PCM and losing resolution. Samples that the gain
+// pushes beyond full scale are clipped.
+func WithGain(dB float64) Option {
+	return func(c *config) {
+		c.gain = dB
+	}
+}
+
+// gainFactor returns the linear factor of a gain in dB.
+func gainFactor(dB float64) float32 {
+	return float32(math.Pow(10, dB/20))
+}
+
+// decodeFrame decodes the current frame and writes its PCM to d.buf.
+func (d *Decoder) decodeFrame() {
+	s := frame.Synthesis{Equalizer: d.eq, HalfRate: d.halfRate}
+	if d.spectrum != nil && !d.warmup {
+		s.Lines = d.spectrumHook
+	}
+	n := d.frame.DecodeFloatWith(&d.pcm, s)
+	if d.frame.Header().NumberOfChannels() == 1 {
+		// We always run in stereo mode and duplicate channels here for mono.
+		d.pcm[1] = d.pcm[0]
+	}
+	d.stereo.add(&d.pcm, n)
+	if d.karaoke {
+		for i := range n {
+			side := d.pcm[0][i] - d.pcm[1][i]
+			d.pcm[0][i] = side
+			d.pcm[1][i] = side
+		}
+	}
+	if d.gain != 1 {
+		for ch := range d.pcm {
+			for i := range n {
+				d.pcm[ch][i] *= d.gain
+			}
+		}
+	}
+	for ch, muted := range d.muted {
+		if muted {
+			clear(d.pcm[ch][:n])
+		}
+	}
+	if d.meter != nil && !d.warmup {
+		d.meterFrame(n)
+	}
+	if d.peaks != nil {
+		d.peaks.add(&d.pcm, n)
+	}
+	out := d.buf.grow(4 * n)[:0]
+	if d.dither != nil {
+		d.dither.appendS16(out, &d.pcm, n)
+		return
+	}
+	frame.AppendS16(out, &d.pcm, n)
+}
+
+// SetChannelEnabled enables or disables an output channel: 0 for left and
+// 1 for right. A disabled channel is output as silence while the other keeps
+// playing, which allows muting or soloing a channel without a downstream
+// mixer. Other values of ch are ignored.
+//
+// The setting applies from the next decoded frame; PCM that is already
+// buffered is not affected.
+func (d *Decoder) SetChannelEnabled(ch int, enabled bool) {
+	if ch < 0 || ch >= len(d.muted) {
+		return
+	}
+	d.muted[ch] = !enabled
+}
+
+// SetKaraoke enables or disables the karaoke mode, in which both channels
+// output the side signal L−R. Center-panned sources, usually the lead vocals,
+// cancel out while most of the accompaniment remains. Mono frames decode as
+// silence in this mode.
+//
+// Like SetChannelEnabled, the setting applies from the next decoded frame.
+func (d *Decoder) SetKaraoke(enabled bool) {
+	d.karaoke = enabled
+}
@@ -0,0 +1,153 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func decodeAll(t *testing.T, path string, setup func(d *Decoder), opts ...Option) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data), opts...)
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	if setup != nil {
+		setup(d)
+		// Drop the PCM decoded by NewDecoder so the setting applies to
+		// the whole output.
+		if _, err := d.Seek(0, io.SeekStart); err != nil {
+			t.Fatalf("Seek failed: %v", err)
+		}
+	}
+	out, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	return out
+}
+
+func TestSetChannelEnabled(t *testing.T) {
+	full := decodeAll(t, "example/classic_lame.mp3", nil)
+	left := decodeAll(t, "example/classic_lame.mp3", func(d *Decoder) {
+		d.SetChannelEnabled(1, false)
+	})
+	if len(left) != len(full) {
+		t.Fatalf("output length = %d, want %d", len(left), len(full))
+	}
+	for i := 0; i < len(full); i += 4 {
+		if left[i] != full[i] || left[i+1] != full[i+1] {
+			t.Fatalf("left channel changed at byte %d", i)
+		}
+		if left[i+2] != 0 || left[i+3] != 0 {
+			t.Fatalf("right channel not muted at byte %d", i)
+		}
+	}
+}
+
+func TestSetChannelEnabled_ReEnable(t *testing.T) {
+	full := decodeAll(t, "example/classic_lame.mp3", nil)
+	got := decodeAll(t, "example/classic_lame.mp3", func(d *Decoder) {
+		d.SetChannelEnabled(0, false)
+		d.SetChannelEnabled(0, true)
+		d.SetChannelEnabled(5, false) // ignored
+	})
+	if !bytes.Equal(got, full) {
+		t.Error("re-enabled channel output differs from default output")
+	}
+}
+
+func TestSetKaraoke(t *testing.T) {
+	full := decodeAll(t, "example/classic_lame.mp3", nil)
+	got := decodeAll(t, "example/classic_lame.mp3", func(d *Decoder) {
+		d.SetKaraoke(true)
+	})
+	if len(got) != len(full) {
+		t.Fatalf("output length = %d, want %d", len(got), len(full))
+	}
+	var fullEnergy, sideEnergy float64
+	for i := 0; i < len(full); i += 4 {
+		l := int(int16(uint16(full[i]) | uint16(full[i+1])<<8))
+		r := int(int16(uint16(full[i+2]) | uint16(full[i+3])<<8))
+		side := int(int16(uint16(got[i]) | uint16(got[i+1])<<8))
+		if got[i] != got[i+2] || got[i+1] != got[i+3] {
+			t.Fatalf("channels differ at byte %d", i)
+		}
+		if want := max(min(l-r, 32767), -32767); abs(side-want) > 2 {
+			t.Fatalf("sample at byte %d = %d, want L-R = %d", i, side, want)
+		}
+		fullEnergy += float64(l*l + r*r)
+		sideEnergy += float64(2 * side * side)
+	}
+	// Most of the energy of a music mix is in the center.
+	if sideEnergy >= fullEnergy {
+		t.Errorf("karaoke output has energy %g, not less than %g", sideEnergy, fullEnergy)
+	}
+}
+
+func TestWithGain(t *testing.T) {
+	full := decodeAll(t, "example/classic_lame.mp3", nil)
+	quiet := decodeAll(t, "example/classic_lame.mp3", nil, WithGain(-6.0206)) // Half amplitude
+	loud := decodeAll(t, "example/classic_lame.mp3", nil, WithGain(40))
+	if len(quiet) != len(full) || len(loud) != len(full) {
+		t.Fatalf("output lengths %d and %d, want %d", len(quiet), len(loud), len(full))
+	}
+	clipped := 0
+	for i := 0; i < len(full); i += 2 {
+		s := int(int16(uint16(full[i]) | uint16(full[i+1])<<8))
+		q := int(int16(uint16(quiet[i]) | uint16(quiet[i+1])<<8))
+		l := int(int16(uint16(loud[i]) | uint16(loud[i+1])<<8))
+		if abs(2*q-s) > 2 {
+			t.Fatalf("sample at byte %d = %d at -6 dB, want about %d", i, q, s/2)
+		}
+		if l > 32767 || l < -32767 {
+			t.Fatalf("sample at byte %d = %d at +40 dB, not clipped", i, l)
+		}
+		if abs(l) == 32767 {
+			clipped++
+		}
+	}
+	if clipped == 0 {
+		t.Error("no sample clipped at +40 dB")
+	}
+	if got := decodeAll(t, "example/classic_lame.mp3", nil, WithGain(0)); !bytes.Equal(got, full) {
+		t.Error("output at 0 dB differs from default output")
+	}
+}
+
+func TestRead_ReusesBuffers(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1152*4)
+	read := func() {
+		if _, err := io.ReadFull(d, buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range 10 {
+		read()
+	}
+	// A frame is read at a time; the PCM buffer and the frame with its side
+	// info, main data and synthesis state are reused.
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for range 100 {
+		read()
+	}
+	runtime.ReadMemStats(&after)
+	if perFrame := (after.TotalAlloc - before.TotalAlloc) / 100; perFrame > 64 {
+		t.Errorf("%d bytes allocated per frame, want at most 64", perFrame)
+	}
+}
@@ -0,0 +1,64 @@
+package mp3
+
+import "encoding/binary"
+
+// PCMFormat describes the samples of a PCMBuffer.
+type PCMFormat struct {
+	NumChannels int
+	SampleRate  int
+}
+
+// A PCMBuffer holds interleaved integer samples along with their format,
+// laid out like the IntBuffer of github.com/go-audio/audio so that decoded
+// audio flows into its DSP and encoding packages by copying the fields:
+//
+//	ib := &audio.IntBuffer{
+//		Format:         &audio.Format{NumChannels: b.Format.NumChannels, SampleRate: b.Format.SampleRate},
+//		Data:           b.Data,
+//		SourceBitDepth: b.SourceBitDepth,
+//	}
+type PCMBuffer struct {
+	Format PCMFormat
+	Data   []int
+
+	// SourceBitDepth is the number of bits of the samples in Data.
+	SourceBitDepth int
+}
+
+// NumFrames returns the number of samples per channel in the buffer.
+func (b *PCMBuffer) NumFrames() int {
+	if b.Format.NumChannels == 0 {
+		return 0
+	}
+	return len(b.Data) / b.Format.NumChannels
+}
+
+// FloatData returns the samples of the buffer scaled to [-1, 1], as in the
+// FloatBuffer of github.com/go-audio/audio.
+func (b *PCMBuffer) FloatData() []float64 {
+	scale := float64(int(1) << max(b.SourceBitDepth-1, 0))
+	f := make([]float64, len(b.Data))
+	for i, v := range b.Data {
+		f[i] = float64(v) / scale
+	}
+	return f
+}
+
+// ReadPCMBuffer reads decoded audio into buf.Data, like the PCMBuffer method
+// of the go-audio WAV and AIFF decoders: it fills up to len(buf.Data)
+// values with whole stereo samples, sets the format of buf and returns the
+// number of values read. The samples are always 16-bit, whatever the output
+// format, and ReadPCMBuffer can be mixed with Read.
+func (d *Decoder) ReadPCMBuffer(buf *PCMBuffer) (int, error) {
+	buf.Format = PCMFormat{NumChannels: 2, SampleRate: d.SampleRate()}
+	buf.SourceBitDepth = 16
+	n := len(buf.Data) / 2
+	if n == 0 {
+		return 0, nil
+	}
+	pcm, err := d.readSamples(n)
+	for i := range len(pcm) / 2 {
+		buf.Data[i] = int(int16(binary.LittleEndian.Uint16(pcm[2*i:])))
+	}
+	return len(pcm) / 2, err
+}
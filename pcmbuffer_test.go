@@ -0,0 +1,96 @@
+package mp3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestReadPCMBuffer(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, want := decodeFresh(t, data)
+	d, err := NewDecoderFromFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	// An odd length leaves a value unused.
+	buf := &PCMBuffer{Data: make([]int, 4097)}
+	pos := 0
+	for {
+		n, err := d.ReadPCMBuffer(buf)
+		if n%2 != 0 {
+			t.Fatalf("read %d values, not whole samples", n)
+		}
+		for _, v := range buf.Data[:n] {
+			if w := int(int16(binary.LittleEndian.Uint16(want[pos:]))); v != w {
+				t.Fatalf("value at byte %d = %d, want %d", pos, v, w)
+			}
+			pos += 2
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if pos != len(want) {
+		t.Errorf("read up to byte %d, want %d", pos, len(want))
+	}
+	if buf.Format != (PCMFormat{NumChannels: 2, SampleRate: 44100}) || buf.SourceBitDepth != 16 {
+		t.Errorf("format %+v, bit depth %d", buf.Format, buf.SourceBitDepth)
+	}
+	if buf.NumFrames() != 2048 {
+		t.Errorf("NumFrames() = %d, want 2048", buf.NumFrames())
+	}
+}
+
+func TestReadPCMBuffer_Resampled(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	ref, err := NewDecoder(bytes.NewReader(data), WithOutputSampleRate(48000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]byte, 4096)
+	if _, err := io.ReadFull(ref, want); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewDecoder(bytes.NewReader(data), WithOutputSampleRate(48000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &PCMBuffer{Data: make([]int, 2048)}
+	if n, err := d.ReadPCMBuffer(buf); n != 2048 || err != nil {
+		t.Fatalf("ReadPCMBuffer = %d, %v", n, err)
+	}
+	if buf.Format.SampleRate != 48000 {
+		t.Errorf("SampleRate = %d, want the output rate 48000", buf.Format.SampleRate)
+	}
+	for i, v := range buf.Data {
+		if w := int(int16(binary.LittleEndian.Uint16(want[2*i:]))); v != w {
+			t.Fatalf("value %d = %d, want %d", i, v, w)
+		}
+	}
+}
+
+func TestPCMBuffer_FloatData(t *testing.T) {
+	b := &PCMBuffer{Data: []int{-32768, 0, 16384}, SourceBitDepth: 16}
+	got := b.FloatData()
+	for i, want := range []float64{-1, 0, 0.5} {
+		if got[i] != want {
+			t.Errorf("FloatData()[%d] = %v, want %v", i, got[i], want)
+		}
+	}
+}
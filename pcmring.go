@@ -0,0 +1,83 @@
+package mp3
+
+import "github.com/llehouerou/go-mp3/internal/frame"
+
+// pcmRingSize is the size of the 16-bit stereo PCM of the largest frame.
+const pcmRingSize = 4 * frame.MaxSamples
+
+// A pcmRing holds the decoded PCM not returned yet. Its fixed size of one
+// frame is enough since a frame is decoded only once the PCM of the
+// previous one is used up, so decoding does not allocate and large Reads do
+// not leave a large buffer behind.
+type pcmRing struct {
+	data  [pcmRingSize]byte
+	start int // index of the first unread byte
+	n     int // number of unread bytes
+}
+
+// Len returns the number of unread bytes.
+func (r *pcmRing) Len() int {
+	return r.n
+}
+
+// Reset drops the unread bytes.
+func (r *pcmRing) Reset() {
+	r.start = 0
+	r.n = 0
+}
+
+// Discard drops the next n unread bytes, at most Len.
+func (r *pcmRing) Discard(n int) {
+	n = min(n, r.n)
+	r.start = (r.start + n) % pcmRingSize
+	r.n -= n
+	if r.n == 0 {
+		r.start = 0
+	}
+}
+
+// Read copies unread bytes into p and returns their number.
+func (r *pcmRing) Read(p []byte) int {
+	n := copy(p, r.data[r.start:min(r.start+r.n, pcmRingSize)])
+	if n < len(p) && n < r.n {
+		// The unread bytes wrap around.
+		n += copy(p[n:], r.data[:r.n-n])
+	}
+	r.Discard(n)
+	return n
+}
+
+// Bytes returns the unread bytes, which stay valid until the next write.
+// It does not consume them.
+func (r *pcmRing) Bytes() []byte {
+	if r.start+r.n > pcmRingSize {
+		r.linearize()
+	}
+	return r.data[r.start : r.start+r.n]
+}
+
+// grow returns the n bytes following the unread bytes, which the caller
+// fills before any other call. It panics if n exceeds the free space.
+func (r *pcmRing) grow(n int) []byte {
+	if r.n+n > pcmRingSize {
+		panic("mp3: PCM ring buffer overflow")
+	}
+	end := r.start + r.n
+	if end < pcmRingSize && end+n > pcmRingSize {
+		// Keep the new bytes contiguous.
+		r.linearize()
+		end = r.n
+	}
+	end %= pcmRingSize
+	r.n += n
+	return r.data[end : end+n : end+n]
+}
+
+// linearize moves the unread bytes to the start of the buffer.
+func (r *pcmRing) linearize() {
+	var tmp [pcmRingSize]byte
+	n := r.Read(tmp[:])
+	copy(r.data[:], tmp[:n])
+	r.start = 0
+	r.n = n
+}
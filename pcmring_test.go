@@ -0,0 +1,70 @@
+package mp3
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPCMRing(t *testing.T) {
+	var r pcmRing
+	var want []byte
+	next := byte(0)
+	write := func(n int) {
+		w := r.grow(n)
+		for i := range w {
+			w[i] = next
+			next++
+		}
+		want = append(want, w...)
+	}
+
+	write(pcmRingSize - 100)
+	got := make([]byte, pcmRingSize-300)
+	if n := r.Read(got); n != len(got) || !bytes.Equal(got, want[:n]) {
+		t.Fatalf("Read() = %d bytes, want the %d bytes written first", n, len(got))
+	}
+	want = want[len(got):]
+	// The free space at the end is too small: the unread bytes move to the
+	// start.
+	write(250)
+	if r.Len() != len(want) || !bytes.Equal(r.Bytes(), want) {
+		t.Fatalf("Bytes() differs from the written bytes")
+	}
+	// Fill the buffer up to its end, free space at its start and write to
+	// it: the unread bytes wrap around.
+	r.Discard(300)
+	want = want[300:]
+	write(pcmRingSize - r.start - r.Len())
+	r.Discard(100)
+	want = want[100:]
+	write(100)
+	if r.start+r.n <= pcmRingSize {
+		t.Fatal("the unread bytes do not wrap around")
+	}
+	got = make([]byte, 50)
+	if n := r.Read(got); n != len(got) || !bytes.Equal(got, want[:n]) {
+		t.Fatalf("Read() = %d bytes, want the %d next bytes", n, len(got))
+	}
+	want = want[len(got):]
+	if !bytes.Equal(r.Bytes(), want) {
+		t.Fatal("Bytes() differs from the bytes across the end of the buffer")
+	}
+	got = make([]byte, pcmRingSize)
+	if n := r.Read(got); n != len(want) || !bytes.Equal(got[:n], want) {
+		t.Fatalf("Read() = %d bytes, want the %d bytes left", n, len(want))
+	}
+	if r.Len() != 0 || r.start != 0 {
+		t.Errorf("Len() = %d, start = %d after reading everything, want 0, 0", r.Len(), r.start)
+	}
+}
+
+func TestPCMRing_Overflow(t *testing.T) {
+	var r pcmRing
+	r.grow(pcmRingSize - 4)
+	defer func() {
+		if recover() == nil {
+			t.Error("grow beyond the free space did not panic")
+		}
+	}()
+	r.grow(8)
+}
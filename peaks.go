@@ -0,0 +1,120 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"math"
+
+	"github.com/llehouerou/go-mp3/internal/frame"
+)
+
+// Peaks holds the peak levels of a stream, per channel, on a scale where
+// full-scale samples have a level of 1. Levels above 1 mean that the decoded
+// samples clip.
+type Peaks struct {
+	// Sample is the largest absolute value of the decoded samples.
+	Sample [2]float64
+
+	// True is the true peak: the largest absolute value of the signal
+	// reconstructed between the samples, estimated by oversampling 4 times
+	// as specified by ITU-R BS.1770-4.
+	True [2]float64
+}
+
+// ScanPeaks decodes the MP3 stream of r and returns its sample and true
+// peaks, as needed for loudness normalization. The peaks are measured on the
+// decoded samples before they are converted to 16-bit integers, so that
+// clipping is accounted for.
+func ScanPeaks(r io.Reader) (Peaks, error) {
+	d, err := NewDecoder(r)
+	if err != nil {
+		return Peaks{}, err
+	}
+	d.peaks = &peakMeter{}
+	buf := make([]byte, 16*1024)
+	for {
+		if _, err := d.Read(buf); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return Peaks{}, err
+		}
+	}
+	return d.peaks.flush(), nil
+}
+
+// truePeakTaps is the length of the phases of the interpolation filter.
+const truePeakTaps = 12
+
+// truePeakFilter holds the phases of the interpolation filter for 4 times
+// oversampling given in ITU-R BS.1770-4, Annex 2.
+var truePeakFilter = [4][truePeakTaps]float64{
+	{
+		0.0017089843750, 0.0109863281250, -0.0196533203125, 0.0332031250000,
+		-0.0594482421875, 0.1373291015625, 0.9721679687500, -0.1022949218750,
+		0.0476074218750, -0.0266113281250, 0.0148925781250, -0.0083007812500,
+	},
+	{
+		-0.0291748046875, 0.0292968750000, -0.0517578125000, 0.0891113281250,
+		-0.1665039062500, 0.4650878906250, 0.7797851562500, -0.2003173828125,
+		0.1015625000000, -0.0582275390625, 0.0330810546875, -0.0189208984375,
+	},
+	{
+		-0.0189208984375, 0.0330810546875, -0.0582275390625, 0.1015625000000,
+		-0.2003173828125, 0.7797851562500, 0.4650878906250, -0.1665039062500,
+		0.0891113281250, -0.0517578125000, 0.0292968750000, -0.0291748046875,
+	},
+	{
+		-0.0083007812500, 0.0148925781250, -0.0266113281250, 0.0476074218750,
+		-0.1022949218750, 0.9721679687500, 0.1373291015625, -0.0594482421875,
+		0.0332031250000, -0.0196533203125, 0.0109863281250, 0.0017089843750,
+	},
+}
+
+// A peakMeter measures the sample and true peaks of decoded PCM.
+type peakMeter struct {
+	peaks Peaks
+
+	// hist holds the last samples of each channel, oldest first.
+	hist [2][truePeakTaps]float64
+}
+
+// add measures the first n samples of pcm.
+func (m *peakMeter) add(pcm *frame.PCM, n int) {
+	for ch := range pcm {
+		for _, v := range pcm[ch][:n] {
+			m.push(ch, float64(v))
+		}
+	}
+}
+
+// push adds sample s of channel ch.
+func (m *peakMeter) push(ch int, s float64) {
+	m.peaks.Sample[ch] = max(m.peaks.Sample[ch], math.Abs(s))
+	h := &m.hist[ch]
+	copy(h[:], h[1:])
+	h[truePeakTaps-1] = s
+	for _, phase := range truePeakFilter {
+		var y float64
+		for k, c := range phase {
+			y += c * h[k]
+		}
+		m.peaks.True[ch] = max(m.peaks.True[ch], math.Abs(y))
+	}
+}
+
+// flush feeds the samples still in the filter through it and returns the
+// peaks.
+func (m *peakMeter) flush() Peaks {
+	for ch := range m.hist {
+		for range truePeakTaps {
+			m.push(ch, 0)
+		}
+	}
+	// The true peak is at least the sample peak, which the filter may
+	// slightly miss.
+	for ch := range m.peaks.True {
+		m.peaks.True[ch] = max(m.peaks.True[ch], m.peaks.Sample[ch])
+	}
+	return m.peaks
+}
@@ -0,0 +1,55 @@
+package mp3
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/internal/frame"
+)
+
+func TestScanPeaks(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, pcm := decodeFresh(t, data)
+	var want [2]int
+	for i := 0; i < len(pcm); i += 2 {
+		s := int(int16(uint16(pcm[i]) | uint16(pcm[i+1])<<8))
+		want[i/2%2] = max(want[i/2%2], abs(s))
+	}
+	p, err := ScanPeaks(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ch := range 2 {
+		if got := int(math.Round(math.Min(p.Sample[ch], 1) * 32767)); abs(got-want[ch]) > 1 {
+			t.Errorf("channel %d: sample peak %g, want %g", ch, p.Sample[ch], float64(want[ch])/32767)
+		}
+		if p.True[ch] < p.Sample[ch] || p.True[ch] > 2*p.Sample[ch] {
+			t.Errorf("channel %d: true peak %g, sample peak %g", ch, p.True[ch], p.Sample[ch])
+		}
+	}
+}
+
+func TestPeakMeter_TruePeak(t *testing.T) {
+	// A sine at a quarter of the sample rate sampled 45° off its peaks.
+	var pcm frame.PCM
+	for i := range frame.MaxSamples {
+		pcm[0][i] = float32(math.Sin(math.Pi/2*float64(i) + math.Pi/4))
+	}
+	var m peakMeter
+	m.add(&pcm, frame.MaxSamples)
+	p := m.flush()
+	if math.Abs(p.Sample[0]-math.Sqrt2/2) > 1e-6 {
+		t.Errorf("sample peak %g, want %g", p.Sample[0], math.Sqrt2/2)
+	}
+	if math.Abs(p.True[0]-1) > 0.02 {
+		t.Errorf("true peak %g, want about 1", p.True[0])
+	}
+	if p.Sample[1] != 0 || p.True[1] != 0 {
+		t.Errorf("silent channel has peaks %g and %g", p.Sample[1], p.True[1])
+	}
+}
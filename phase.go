@@ -0,0 +1,123 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"math"
+)
+
+// PhaseInversionThreshold is the correlation below which stereo audio is
+// considered out of phase by DetectPhaseInversion.
+const PhaseInversionThreshold = -0.5
+
+// A PhaseReport describes the phase relationship between the two channels
+// of a stream.
+type PhaseReport struct {
+	// Correlation is the normalized correlation between the left and right
+	// channels over the whole stream, from -1 (one channel is the inverse
+	// of the other) to 1 (identical channels). It is 0 for silence.
+	Correlation float64
+
+	// InvertedRatio is the fraction of one-second windows, among those
+	// that are not silent, whose correlation is below
+	// PhaseInversionThreshold.
+	InvertedRatio float64
+
+	// Inverted reports whether the stream as a whole is out of phase.
+	// Such audio largely cancels itself out when downmixed to mono.
+	Inverted bool
+}
+
+// phaseAccumulator accumulates the sums needed for the correlation of two
+// channels.
+type phaseAccumulator struct {
+	lr, ll, rr float64
+}
+
+func (a *phaseAccumulator) add(l, r float64) {
+	a.lr += l * r
+	a.ll += l * l
+	a.rr += r * r
+}
+
+func (a *phaseAccumulator) correlation() float64 {
+	den := math.Sqrt(a.ll * a.rr)
+	if den == 0 {
+		return 0
+	}
+	return a.lr / den
+}
+
+// phaseAnalyzer computes a PhaseReport from a sequence of stereo samples.
+type phaseAnalyzer struct {
+	total, window phaseAccumulator
+	windowLen     int
+	inWindow      int
+	windows       int
+	inverted      int
+}
+
+func newPhaseAnalyzer(sampleRate int) *phaseAnalyzer {
+	return &phaseAnalyzer{windowLen: sampleRate}
+}
+
+func (p *phaseAnalyzer) add(l, r float64) {
+	p.total.add(l, r)
+	p.window.add(l, r)
+	p.inWindow++
+	if p.inWindow == p.windowLen {
+		p.closeWindow()
+	}
+}
+
+func (p *phaseAnalyzer) closeWindow() {
+	if p.window.ll > 0 && p.window.rr > 0 {
+		p.windows++
+		if p.window.correlation() < PhaseInversionThreshold {
+			p.inverted++
+		}
+	}
+	p.window = phaseAccumulator{}
+	p.inWindow = 0
+}
+
+func (p *phaseAnalyzer) report() *PhaseReport {
+	if p.inWindow > 0 {
+		p.closeWindow()
+	}
+	rep := &PhaseReport{
+		Correlation: p.total.correlation(),
+	}
+	if p.windows > 0 {
+		rep.InvertedRatio = float64(p.inverted) / float64(p.windows)
+	}
+	rep.Inverted = rep.Correlation < PhaseInversionThreshold || rep.InvertedRatio > 0.5
+	return rep
+}
+
+// DetectPhaseInversion decodes r and reports whether its channels are out
+// of phase, as happens when one channel of a recording has its polarity
+// flipped.
+func DetectPhaseInversion(r io.Reader) (*PhaseReport, error) {
+	d, err := NewDecoder(r)
+	if err != nil {
+		return nil, err
+	}
+	p := newPhaseAnalyzer(d.SampleRate())
+	buf := make([]byte, 16*1024)
+	for {
+		n, err := d.Read(buf)
+		for i := 0; i+3 < n; i += 4 {
+			l := float64(int16(uint16(buf[i]) | uint16(buf[i+1])<<8))
+			r := float64(int16(uint16(buf[i+2]) | uint16(buf[i+3])<<8))
+			p.add(l, r)
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+	}
+	return p.report(), nil
+}
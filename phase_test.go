@@ -0,0 +1,66 @@
+package mp3
+
+import (
+	"math"
+	"os"
+	"testing"
+)
+
+func TestPhaseAnalyzer_Inverted(t *testing.T) {
+	p := newPhaseAnalyzer(1000)
+	for i := range 5000 {
+		s := math.Sin(float64(i) * 0.05)
+		p.add(s, -s*0.9)
+	}
+	rep := p.report()
+	if !rep.Inverted {
+		t.Errorf("inverted stereo not detected: %+v", rep)
+	}
+	if rep.Correlation > -0.99 {
+		t.Errorf("Correlation = %v, want about -1", rep.Correlation)
+	}
+	if rep.InvertedRatio != 1 {
+		t.Errorf("InvertedRatio = %v, want 1", rep.InvertedRatio)
+	}
+}
+
+func TestPhaseAnalyzer_InPhaseAndSilence(t *testing.T) {
+	p := newPhaseAnalyzer(1000)
+	for i := range 3000 {
+		s := math.Sin(float64(i) * 0.05)
+		p.add(s, s)
+	}
+	// A silent stretch must not count as a window.
+	for range 2000 {
+		p.add(0, 0)
+	}
+	rep := p.report()
+	if rep.Inverted {
+		t.Errorf("in-phase stereo reported as inverted: %+v", rep)
+	}
+	if math.Abs(rep.Correlation-1) > 1e-9 {
+		t.Errorf("Correlation = %v, want 1", rep.Correlation)
+	}
+	if p.windows != 3 {
+		t.Errorf("counted %d windows, want 3", p.windows)
+	}
+}
+
+func TestDetectPhaseInversion_RealFile(t *testing.T) {
+	f, err := os.Open("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	rep, err := DetectPhaseInversion(f)
+	if err != nil {
+		t.Fatalf("DetectPhaseInversion failed: %v", err)
+	}
+	if rep.Inverted {
+		t.Errorf("regular recording reported as inverted: %+v", rep)
+	}
+	if rep.Correlation <= 0 {
+		t.Errorf("Correlation = %v, want positive for a regular recording", rep.Correlation)
+	}
+}
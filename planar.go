@@ -0,0 +1,40 @@
+package mp3
+
+import (
+	"encoding/binary"
+	"slices"
+)
+
+// ReadPlanar reads decoded audio as separate left and right channels, for
+// DSP libraries and audio graphs that work on planar data. It reads up to
+// min(len(left), len(right)) samples per channel and returns the number of
+// samples read into each.
+//
+// ReadPlanar is like Read, except for the layout of the samples, and the
+// two can be mixed. The samples are always 16-bit, whatever the output format.
+func (d *Decoder) ReadPlanar(left, right []int16) (int, error) {
+	n := min(len(left), len(right))
+	if n == 0 {
+		return 0, nil
+	}
+	pcm, err := d.readSamples(n)
+	for i := range len(pcm) / 4 {
+		left[i] = int16(binary.LittleEndian.Uint16(pcm[4*i:]))
+		right[i] = int16(binary.LittleEndian.Uint16(pcm[4*i+2:]))
+	}
+	return len(pcm) / 4, err
+}
+
+// readSamples reads up to n stereo samples of 16-bit PCM, whatever the
+// output format. The result is only valid until the next read.
+func (d *Decoder) readSamples(n int) ([]byte, error) {
+	d.converted = slices.Grow(d.converted[:0], 4*n)[:4*n]
+	m, err := d.readS16(d.converted)
+	// Complete the last sample when reading from an unaligned position.
+	for m%4 != 0 && err == nil {
+		var k int
+		k, err = d.readS16(d.converted[m : m+4-m%4])
+		m += k
+	}
+	return d.converted[:m/4*4], err
+}
@@ -0,0 +1,92 @@
+package mp3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestReadPlanar(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, pcm := decodeFresh(t, data)
+
+	// The last block is padded with silence.
+	samples := len(pcm) / 4
+	padded := (samples + 440) / 441 * 441
+	pcm = append(pcm, make([]byte, 4*(padded-samples))...)
+
+	for _, tt := range []struct {
+		opts []Option
+		want int
+	}{
+		{nil, samples},
+		{[]Option{WithBlockSize(441)}, padded},
+		{[]Option{WithOutputFormat(OutputMuLaw)}, samples},
+	} {
+		d, err := NewDecoder(bytes.NewReader(data), tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		left := make([]int16, 1000)
+		right := make([]int16, 1200)
+		total := 0
+		for {
+			n, err := d.ReadPlanar(left, right)
+			for i := range n {
+				off := 4 * (total + i)
+				l := int16(binary.LittleEndian.Uint16(pcm[off:]))
+				r := int16(binary.LittleEndian.Uint16(pcm[off+2:]))
+				if left[i] != l || right[i] != r {
+					t.Fatalf("sample %d = (%d, %d), want (%d, %d)", total+i, left[i], right[i], l, r)
+				}
+			}
+			total += n
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if total != tt.want {
+			t.Errorf("read %d samples, want %d", total, tt.want)
+		}
+	}
+}
+
+func TestReadPlanar_UnalignedPosition(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, pcm := decodeFresh(t, data)
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Seek(40000, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(d, make([]byte, 2)); err != nil {
+		t.Fatal(err)
+	}
+	left := make([]int16, 10)
+	right := make([]int16, 10)
+	n, err := d.ReadPlanar(left, right)
+	if err != nil || n != 10 {
+		t.Fatalf("ReadPlanar = %d, %v", n, err)
+	}
+	// The channels are swapped, as the position starts on a right sample.
+	if want := int16(binary.LittleEndian.Uint16(pcm[40002:])); left[0] != want {
+		t.Errorf("left[0] = %d, want %d", left[0], want)
+	}
+	if d.pos != 40042 {
+		t.Errorf("position = %d, want 40042", d.pos)
+	}
+}
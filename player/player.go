@@ -0,0 +1,137 @@
+// Package player adapts an mp3.Decoder to the interfaces of common Go audio
+// players, so that wiring playback takes a few lines.
+//
+// With oto:
+//
+//	o, err := player.Oto(d)
+//	ctx, ready, err := oto.NewContext(&oto.NewContextOptions{
+//		SampleRate: o.SampleRate, ChannelCount: o.ChannelCount, Format: oto.Format(o.Format)})
+//	<-ready
+//	ctx.NewPlayer(d).Play()
+//
+// With beep:
+//
+//	s, f, err := player.Beep(d)
+//	speaker.Init(beep.SampleRate(f.SampleRate), f.SampleRate/10)
+//	speaker.Play(s)
+//
+// The package does not import the players, whose types are mirrored by
+// plain values.
+package player
+
+import (
+	"errors"
+	"io"
+
+	"github.com/llehouerou/go-mp3"
+)
+
+// Values of oto.Format, as of oto v3.
+const (
+	OtoFormatFloat32LE     = 0
+	OtoFormatUnsignedInt8  = 1
+	OtoFormatSignedInt16LE = 2
+)
+
+// OtoOptions holds the fields of oto.NewContextOptions describing the PCM
+// read from a Decoder.
+type OtoOptions struct {
+	SampleRate   int
+	ChannelCount int
+	Format       int
+}
+
+// ErrUnsupportedFormat is returned when the output format of the decoder
+// cannot be played by the player.
+var ErrUnsupportedFormat = errors.New("player: unsupported output format")
+
+// Oto returns the options of an oto context playing the PCM read from d,
+// which is itself the io.Reader given to oto's NewPlayer. It returns
+// ErrUnsupportedFormat for output formats oto does not play.
+func Oto(d *mp3.Decoder) (OtoOptions, error) {
+	o := OtoOptions{SampleRate: d.SampleRate(), ChannelCount: 2}
+	switch d.OutputFormat() {
+	case mp3.OutputS16LE:
+		o.Format = OtoFormatSignedInt16LE
+	case mp3.OutputU8:
+		o.Format = OtoFormatUnsignedInt8
+	default:
+		return OtoOptions{}, ErrUnsupportedFormat
+	}
+	return o, nil
+}
+
+// BeepFormat holds the fields of beep.Format describing the stream of a
+// BeepStreamer.
+type BeepFormat struct {
+	SampleRate  int
+	NumChannels int
+	Precision   int
+}
+
+// A BeepStreamer streams the PCM of a Decoder as the float samples of beep.
+// It implements beep.StreamSeekCloser.
+type BeepStreamer struct {
+	d   *mp3.Decoder
+	buf []byte
+	err error
+}
+
+// Beep returns a beep streamer of d and its format. The decoder must output
+// 16-bit PCM, the default, otherwise Beep returns ErrUnsupportedFormat.
+func Beep(d *mp3.Decoder) (*BeepStreamer, BeepFormat, error) {
+	if d.OutputFormat() != mp3.OutputS16LE {
+		return nil, BeepFormat{}, ErrUnsupportedFormat
+	}
+	f := BeepFormat{SampleRate: d.SampleRate(), NumChannels: 2, Precision: 2}
+	return &BeepStreamer{d: d}, f, nil
+}
+
+// Stream fills samples with the next samples of the decoder. It returns
+// false once the decoder is drained or fails, the error being reported by
+// Err.
+func (s *BeepStreamer) Stream(samples [][2]float64) (int, bool) {
+	if s.err != nil {
+		return 0, false
+	}
+	if cap(s.buf) < 4*len(samples) {
+		s.buf = make([]byte, 4*len(samples))
+	}
+	buf := s.buf[:4*len(samples)]
+	n, err := io.ReadFull(s.d, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		s.err = err
+	}
+	for i := range n / 4 {
+		b := buf[4*i:]
+		samples[i][0] = float64(int16(uint16(b[0])|uint16(b[1])<<8)) / (1 << 15)
+		samples[i][1] = float64(int16(uint16(b[2])|uint16(b[3])<<8)) / (1 << 15)
+	}
+	return n / 4, n > 0
+}
+
+// Err returns the error that stopped the stream, if any.
+func (s *BeepStreamer) Err() error {
+	return s.err
+}
+
+// Len returns the number of samples of the stream, or -1 if it is unknown.
+func (s *BeepStreamer) Len() int {
+	return int(s.d.SampleCount())
+}
+
+// Position returns the index of the next sample streamed.
+func (s *BeepStreamer) Position() int {
+	return int(s.d.SamplePosition())
+}
+
+// Seek moves to sample p. It fails if the source of the decoder is not
+// io.Seeker.
+func (s *BeepStreamer) Seek(p int) error {
+	return s.d.SeekToSample(int64(p))
+}
+
+// Close closes the decoder.
+func (s *BeepStreamer) Close() error {
+	return s.d.Close()
+}
@@ -0,0 +1,91 @@
+package player
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/llehouerou/go-mp3"
+)
+
+func newDecoder(t *testing.T, opts ...mp3.Option) *mp3.Decoder {
+	t.Helper()
+	data, err := os.ReadFile("../example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := mp3.NewDecoder(bytes.NewReader(data), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestOto(t *testing.T) {
+	o, err := Oto(newDecoder(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (OtoOptions{SampleRate: 44100, ChannelCount: 2, Format: OtoFormatSignedInt16LE}); o != want {
+		t.Errorf("Oto() = %+v, want %+v", o, want)
+	}
+	o, err = Oto(newDecoder(t, mp3.WithOutputFormat(mp3.OutputU8)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Format != OtoFormatUnsignedInt8 {
+		t.Errorf("Oto() format = %d for u8 output", o.Format)
+	}
+	if _, err := Oto(newDecoder(t, mp3.WithOutputFormat(mp3.OutputALaw))); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Oto() with A-law output: %v", err)
+	}
+}
+
+func TestBeep(t *testing.T) {
+	pcm, err := io.ReadAll(newDecoder(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, f, err := Beep(newDecoder(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (BeepFormat{SampleRate: 44100, NumChannels: 2, Precision: 2}); f != want {
+		t.Errorf("Beep() format = %+v, want %+v", f, want)
+	}
+	if s.Len() != len(pcm)/4 {
+		t.Errorf("Len() = %d, want %d", s.Len(), len(pcm)/4)
+	}
+	if err := s.Seek(1000); err != nil {
+		t.Fatal(err)
+	}
+	samples := make([][2]float64, 4096)
+	i := 1000
+	for {
+		n, ok := s.Stream(samples)
+		if !ok {
+			break
+		}
+		for _, v := range samples[:n] {
+			for ch := range v {
+				want := int16(binary.LittleEndian.Uint16(pcm[4*i+2*ch:]))
+				if got := v[ch] * (1 << 15); got != float64(want) {
+					t.Fatalf("sample %d channel %d = %v, want %d", i, ch, got, want)
+				}
+			}
+			i++
+		}
+	}
+	if s.Err() != nil {
+		t.Fatal(s.Err())
+	}
+	if i != len(pcm)/4 || s.Position() != i {
+		t.Errorf("streamed up to sample %d, position %d, want %d", i, s.Position(), len(pcm)/4)
+	}
+	if _, _, err := Beep(newDecoder(t, mp3.WithOutputFormat(mp3.OutputU8))); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Beep() with u8 output: %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// Range seeks to start and returns a reader of the PCM from start to end,
+// which returns io.EOF at end, for extracting clips and previews. Both
+// times are clamped to [0, Duration] and aligned to samples like
+// SeekToTime.
+//
+// The reader reads from d, which must not be used until the range is read.
+// Range returns an error if the source is not io.Seeker or the range is
+// empty.
+func (d *Decoder) Range(start, end time.Duration) (io.Reader, error) {
+	if d.length == invalidLength {
+		return nil, errors.New("mp3: Range requires a seekable source")
+	}
+	spos := d.posAt(max(start, 0))
+	epos := d.posAt(max(end, 0))
+	if epos <= spos {
+		return nil, errors.New("mp3: empty range")
+	}
+	if _, err := d.seek(spos, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.LimitReader(d, d.outputBytes(epos)-d.outputBytes(spos)), nil
+}
@@ -0,0 +1,50 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDecoder_Range(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, want := decodeFresh(t, data)
+
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := d.Range(1500*time.Millisecond, 4*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want[66150*4:4*44100*4]) {
+		t.Errorf("got %d bytes of PCM differing from the range", len(got))
+	}
+
+	// The end is clamped to the duration.
+	r, err = d.Range(9*time.Second, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want[9*44100*4:]) {
+		t.Errorf("got %d bytes of PCM differing from the range to the end", len(got))
+	}
+
+	if _, err := d.Range(2*time.Second, time.Second); err == nil {
+		t.Error("Range() with end before start succeeded")
+	}
+}
@@ -0,0 +1,69 @@
+package mp3
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WithSampleRateChangeError makes Read return a *SampleRateChangeError when
+// it reaches a frame whose sample rate differs from the rate of the audio
+// returned before, as in internet radio streams spliced from several
+// sources, so that the caller can rebuild its pipeline for the new rate.
+// Without it, the PCM of the frames is returned as is, and plays at the
+// wrong speed once the rate changes.
+//
+// The error is returned once per change, when all the PCM at the previous
+// rate has been read; the next Read returns the PCM at the new rate. The
+// rate starts as SampleRate, and a seek into frames of another rate counts
+// as a change. The rates are those of the frames, before
+// WithOutputSampleRate.
+func WithSampleRateChangeError() Option {
+	return func(c *config) {
+		c.rateChangeError = true
+	}
+}
+
+// ErrSampleRateChanged matches, with errors.Is, the error returned with
+// WithSampleRateChangeError when the sample rate changes.
+var ErrSampleRateChanged = errors.New("mp3: sample rate changed")
+
+// SampleRateChangeError is returned by Read with WithSampleRateChangeError
+// when the sample rate of the stream changes.
+type SampleRateChangeError struct {
+	// Offset is the input offset of the first frame at the new rate.
+	Offset int64
+
+	// From is the sample rate of the audio read before, and To the one of
+	// the audio read next.
+	From, To int
+}
+
+func (e *SampleRateChangeError) Error() string {
+	return fmt.Sprintf("mp3: sample rate changed from %d to %d Hz at offset %d", e.From, e.To, e.Offset)
+}
+
+func (e *SampleRateChangeError) Unwrap() error {
+	return ErrSampleRateChanged
+}
+
+// checkSampleRate returns a *SampleRateChangeError when the current frame,
+// whose PCM is read next, has another sample rate than the audio read
+// before, and takes its rate as the current one.
+func (d *Decoder) checkSampleRate() error {
+	if d.frame == nil {
+		return nil
+	}
+	rate, err := d.frame.Header().SamplingFrequencyValue()
+	if err != nil {
+		return nil
+	}
+	if d.halfRate {
+		rate /= 2
+	}
+	if rate == d.currentRate {
+		return nil
+	}
+	from := d.currentRate
+	d.currentRate = rate
+	return &SampleRateChangeError{Offset: d.frameOffset, From: from, To: rate}
+}
@@ -0,0 +1,73 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+func TestWithSampleRateChangeError(t *testing.T) {
+	plain, err := testsupport.Generate(testsupport.Options{Frames: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := testsupport.Generate(testsupport.Options{Frames: 5, SampleRate: 48000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := slices.Concat(plain, other)
+
+	for _, r := range []io.Reader{bytes.NewReader(data), &nonSeekableReader{bytes.NewReader(data)}} {
+		d, err := NewDecoder(r, WithSampleRateChangeError())
+		if err != nil {
+			t.Fatalf("NewDecoder failed: %v", err)
+		}
+		first, err := io.ReadAll(d)
+		var rerr *SampleRateChangeError
+		if !errors.As(err, &rerr) || !errors.Is(err, ErrSampleRateChanged) {
+			t.Fatalf("ReadAll error = %v, want a *SampleRateChangeError", err)
+		}
+		want := SampleRateChangeError{Offset: int64(len(plain)), From: 44100, To: 48000}
+		if *rerr != want {
+			t.Errorf("error = %+v, want %+v", *rerr, want)
+		}
+		if len(first) != 10*1152*4 {
+			t.Errorf("read %d bytes before the change, want %d", len(first), 10*1152*4)
+		}
+
+		// Reading continues at the new rate.
+		rest, err := io.ReadAll(d)
+		if err != nil {
+			t.Fatalf("ReadAll after the change failed: %v", err)
+		}
+		if len(rest) != 5*1152*4 {
+			t.Errorf("read %d bytes after the change, want %d", len(rest), 5*1152*4)
+		}
+	}
+
+	// A seek into the frames of another rate is a change too.
+	d, err := NewDecoder(bytes.NewReader(data), WithSampleRateChangeError())
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if _, err := d.Seek(12*1152*4, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	if _, err := d.Read(make([]byte, 4)); !errors.Is(err, ErrSampleRateChanged) {
+		t.Errorf("Read after Seek error = %v, want ErrSampleRateChanged", err)
+	}
+
+	// Without the option the PCM is returned as is.
+	d, err = NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	pcm, err := io.ReadAll(d)
+	if err != nil || len(pcm) != 15*1152*4 {
+		t.Errorf("ReadAll = %d bytes, %v", len(pcm), err)
+	}
+}
@@ -0,0 +1,63 @@
+package mp3
+
+import (
+	"io"
+
+	"github.com/llehouerou/go-mp3/internal/frame"
+)
+
+// bytesSource adapts an in-memory frame to the FullReader interface used by
+// the internal decoding packages.
+type bytesSource struct {
+	b []byte
+}
+
+func (s *bytesSource) ReadFull(buf []byte) (int, error) {
+	n := copy(buf, s.b)
+	s.b = s.b[n:]
+	if n < len(buf) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// decodeRawFrame decodes a single complete frame held in raw. prev is the
+// previously decoded frame of the same stream, if any, and supplies the bit
+// reservoir and the synthesis filterbank state.
+func decodeRawFrame(raw []byte, prev *frame.Frame) (*frame.Frame, []byte, error) {
+	f, _, err := frame.Read(&bytesSource{b: raw}, 0, prev)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f.Decode(), nil
+}
+
+// A FrameState holds the decoding state carried from one frame to the next:
+// the bit reservoir and the synthesis filterbank memory. It is produced by
+// DecodeSingleFrame and is never modified after it is returned, so it can be
+// handed to another goroutine or reused freely.
+type FrameState struct {
+	frame *frame.Frame
+}
+
+// DecodeSingleFrame decodes one complete MP3 frame, including its 4-byte
+// header, and returns its PCM in the same format as Decoder.Read together
+// with the state needed to decode the following frame.
+//
+// prevState is the state returned for the preceding frame of the same
+// stream, or nil at the start of a stream. Without it, frames that borrow
+// bits from the reservoir of earlier frames decode incorrectly and the
+// filterbank starts from silence, so a worker decoding a shard of frames
+// should decode one or more frames before its shard and discard their PCM,
+// as Decoder.Seek does.
+func DecodeSingleFrame(raw []byte, prevState *FrameState) ([]byte, *FrameState, error) {
+	var prev *frame.Frame
+	if prevState != nil {
+		prev = prevState.frame.State()
+	}
+	f, pcm, err := decodeRawFrame(raw, prev)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pcm, &FrameState{frame: f.State()}, nil
+}
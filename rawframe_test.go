@@ -0,0 +1,79 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+func readFrames(t *testing.T, data []byte) [][]byte {
+	t.Helper()
+	f := NewFramer()
+	_, _ = f.Write(data)
+	var frames [][]byte
+	for {
+		frame, ok := f.Next()
+		if !ok {
+			break
+		}
+		frames = append(frames, bytes.Clone(frame))
+	}
+	return frames
+}
+
+func TestDecodeSingleFrame_MatchesDecoder(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	want, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	var got []byte
+	var state *FrameState
+	for i, frame := range readFrames(t, data) {
+		pcm, next, err := DecodeSingleFrame(frame, state)
+		if err != nil {
+			t.Fatalf("frame %d: DecodeSingleFrame failed: %v", i, err)
+		}
+		got = append(got, pcm...)
+		state = next
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("frame-by-frame output (%d bytes) differs from Decoder output (%d bytes)", len(got), len(want))
+	}
+}
+
+func TestDecodeSingleFrame_StateIsReusable(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	frames := readFrames(t, data)
+
+	var state *FrameState
+	for _, frame := range frames[:10] {
+		_, state, err = DecodeSingleFrame(frame, state)
+		if err != nil {
+			t.Fatalf("DecodeSingleFrame failed: %v", err)
+		}
+	}
+	first, _, err := DecodeSingleFrame(frames[10], state)
+	if err != nil {
+		t.Fatalf("DecodeSingleFrame failed: %v", err)
+	}
+	second, _, err := DecodeSingleFrame(frames[10], state)
+	if err != nil {
+		t.Fatalf("DecodeSingleFrame failed: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("decoding twice from the same state produced different output")
+	}
+}
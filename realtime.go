@@ -0,0 +1,48 @@
+package mp3
+
+import "time"
+
+// A RealtimeReader reads the PCM of a Decoder no faster than it plays, to
+// simulate a live stream, feed pipelines expecting realtime input or test
+// playback code without an audio device.
+//
+// Each Read returns once the audio it returns would have finished playing,
+// counting from the first Read. Seeking the decoder restarts the clock at
+// the new position.
+type RealtimeReader struct {
+	d *Decoder
+
+	// start is the time at which reading started at the current position,
+	// read the number of bytes returned since, and last the position after
+	// the previous Read.
+	start time.Time
+	read  int64
+	last  time.Duration
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewRealtimeReader returns a RealtimeReader reading from d.
+func NewRealtimeReader(d *Decoder) *RealtimeReader {
+	return &RealtimeReader{d: d, last: -1, now: time.Now, sleep: time.Sleep}
+}
+
+// Read reads PCM from the decoder into buf, then waits until it has played.
+// The playing time is that of the samples returned at SampleRate, so that
+// it follows SetSpeed.
+func (r *RealtimeReader) Read(buf []byte) (int, error) {
+	if pos := r.d.Position(); pos != r.last {
+		r.start = r.now()
+		r.read = 0
+	}
+	n, err := r.d.Read(buf)
+	r.last = r.d.Position()
+	r.read += int64(n)
+	samples := r.read / int64(2*r.d.outFormat.BytesPerSample())
+	played := time.Duration(samples) * time.Second / time.Duration(r.d.SampleRate())
+	if wait := r.start.Add(played).Sub(r.now()); wait > 0 {
+		r.sleep(wait)
+	}
+	return n, err
+}
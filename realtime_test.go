@@ -0,0 +1,87 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRealtimeReader(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRealtimeReader(d)
+	clock := time.Unix(0, 0)
+	r.now = func() time.Time { return clock }
+	r.sleep = func(wait time.Duration) { clock = clock.Add(wait) }
+
+	buf := make([]byte, 4410*4)
+	for range 10 {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := clock.Sub(time.Unix(0, 0)); elapsed != time.Second {
+		t.Errorf("reading 1s of audio took %v", elapsed)
+	}
+
+	// Time spent elsewhere counts towards the playback.
+	clock = clock.Add(300 * time.Millisecond)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := clock.Sub(time.Unix(0, 0)); elapsed != 1300*time.Millisecond {
+		t.Errorf("elapsed %v after a late read, want 1.3s", elapsed)
+	}
+
+	// Seeking restarts the clock.
+	if err := d.SeekToTime(5 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	from := clock
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := clock.Sub(from); elapsed != 100*time.Millisecond {
+		t.Errorf("reading 100ms after a seek took %v", elapsed)
+	}
+}
+
+func TestRealtimeReader_Speed(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetSpeed(2); err != nil {
+		t.Fatal(err)
+	}
+	r := NewRealtimeReader(d)
+	clock := time.Unix(0, 0)
+	r.now = func() time.Time { return clock }
+	r.sleep = func(wait time.Duration) { clock = clock.Add(wait) }
+
+	// A second of output holds two seconds of the stream.
+	buf := make([]byte, 4410*4)
+	for range 10 {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := clock.Sub(time.Unix(0, 0)); elapsed != time.Second {
+		t.Errorf("reading 1s of output took %v", elapsed)
+	}
+	if pos := d.Position(); pos < 1900*time.Millisecond || pos > 2100*time.Millisecond {
+		t.Errorf("Position() = %v, want about 2s", pos)
+	}
+}
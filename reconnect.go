@@ -0,0 +1,67 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+)
+
+// SetSource replaces the source of the decoder, so that a client can
+// reconnect a dropped network stream, for example with an HTTP range request,
+// and continue decoding with the same Decoder. Position, Length, Duration,
+// metadata and decoded PCM not yet read are kept.
+//
+// resumeAtByte is the offset in the input of the first byte r delivers,
+// typically the number of bytes received before the connection dropped.
+// Bytes the decoder has already received are not used twice, so any offset
+// up to that count continues the stream seamlessly. When resumeAtByte is
+// past the received data, the missing bytes are skipped and decoding starts
+// again at the next frame header, without the bit reservoir of earlier
+// frames.
+//
+// Options that wrap the source, WithLiveSource and WithTransform, apply to r
+// as well, and so does a read deadline set with SetReadDeadline.
+func (d *Decoder) SetSource(r io.Reader, resumeAtByte int64) error {
+	if resumeAtByte < 0 {
+		return errors.New("mp3: negative resume offset")
+	}
+	if d.transform != nil {
+		r = newTransformReader(r, d.transform, resumeAtByte)
+	}
+	s := d.source
+	buffered := s.buf
+	if d.async != nil {
+		buffered = append(buffered, d.async.data...)
+	}
+	switch {
+	case resumeAtByte < s.pos:
+		// Skip the bytes that were already decoded.
+		n, err := io.CopyN(io.Discard, r, s.pos-resumeAtByte)
+		s.consumed += n
+		if err != nil {
+			return err
+		}
+		buffered = nil
+	case resumeAtByte <= s.pos+int64(len(buffered)):
+		buffered = buffered[:resumeAtByte-s.pos]
+	default:
+		buffered = nil
+		s.pos = resumeAtByte
+		d.decodePending()
+		d.frame = nil
+		d.firstHeader = 0
+	}
+
+	if d.liveContext != nil {
+		r = &liveReader{ctx: d.liveContext, reader: r}
+	}
+	if d.async != nil {
+		d.async = &asyncReader{reader: r}
+		r = d.async
+	}
+	s.reader = r
+	s.buf = nil
+	if len(buffered) > 0 {
+		s.buf = append([]byte(nil), buffered...)
+	}
+	return nil
+}
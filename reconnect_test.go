@@ -0,0 +1,123 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+var errConnectionReset = errors.New("connection reset")
+
+// droppingReader delivers the first n bytes of data, then fails.
+type droppingReader struct {
+	data []byte
+	n    int
+}
+
+func (r *droppingReader) Read(buf []byte) (int, error) {
+	if r.n == 0 {
+		return 0, errConnectionReset
+	}
+	k := copy(buf, r.data[:min(r.n, len(r.data))])
+	r.data = r.data[k:]
+	r.n -= k
+	return k, nil
+}
+
+// readUntilError reads d until it fails and returns the PCM read.
+func readUntilError(t *testing.T, d *Decoder) ([]byte, error) {
+	t.Helper()
+	var pcm []byte
+	buf := make([]byte, 4096)
+	for {
+		n, err := d.Read(buf)
+		pcm = append(pcm, buf[:n]...)
+		if err != nil {
+			return pcm, err
+		}
+	}
+}
+
+func TestSetSource(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	ref, err := NewDecoder(nonSeekable{bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := io.ReadAll(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The connection drops in the middle of a frame.
+	const received = 100001
+	tests := []struct {
+		name   string
+		resume int64
+	}{
+		{"at received count", received},
+		{"before received count", received - 20000},
+		{"within unread data", received - 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDecoder(&droppingReader{data: data, n: received})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := readUntilError(t, d)
+			if !errors.Is(err, errConnectionReset) {
+				t.Fatalf("Read error = %v, want the connection error", err)
+			}
+			if err := d.SetSource(nonSeekable{bytes.NewReader(data[tt.resume:])}, tt.resume); err != nil {
+				t.Fatalf("SetSource failed: %v", err)
+			}
+			rest, err := readUntilError(t, d)
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("Read error = %v, want io.EOF", err)
+			}
+			got = append(got, rest...)
+			if !bytes.Equal(got, want) {
+				t.Errorf("decoded %d bytes differing from the uninterrupted %d bytes", len(got), len(want))
+			}
+		})
+	}
+}
+
+func TestSetSource_Gap(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(&droppingReader{data: data, n: 100001})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := readUntilError(t, d)
+	if !errors.Is(err, errConnectionReset) {
+		t.Fatalf("Read error = %v, want the connection error", err)
+	}
+	const resume = 150000
+	if err := d.SetSource(nonSeekable{bytes.NewReader(data[resume:])}, resume); err != nil {
+		t.Fatalf("SetSource failed: %v", err)
+	}
+	rest, err := readUntilError(t, d)
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("Read error = %v, want io.EOF", err)
+	}
+	if len(rest) == 0 {
+		t.Fatal("no audio decoded after the gap")
+	}
+	if pos, _ := d.Seek(0, io.SeekCurrent); pos != int64(len(first)+len(rest)) {
+		t.Errorf("position = %d, want %d", pos, len(first)+len(rest))
+	}
+
+	if err := d.SetSource(bytes.NewReader(nil), -1); err == nil {
+		t.Error("SetSource with a negative offset succeeded")
+	}
+}
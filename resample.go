@@ -0,0 +1,320 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"math"
+	"slices"
+)
+
+// Parameters of the windowed-sinc kernel of the resampler.
+const (
+	// resampleZeros is the number of zero crossings of the sinc on each side
+	// of the kernel.
+	resampleZeros = 16
+
+	// resamplePhases is the number of kernel values tabulated per source
+	// sample; values in between are interpolated linearly.
+	resamplePhases = 256
+
+	// speedScale is the denominator of the playback speed of SetSpeed.
+	speedScale = 1000
+)
+
+// WithOutputSampleRate makes Read return the audio resampled to rate, such
+// as the 48000 Hz many audio devices require, with a windowed-sinc
+// resampler. SampleRate returns rate, and the byte and sample counts and
+// positions of the Decoder, such as those of Length, Seek and
+// SamplePosition, are in the resampled output. A rate of 0 or of the stream
+// itself disables resampling.
+//
+// The rate of the stream is that of the first frame. The analysis methods
+// are not affected, and DecodeFrame and SetLoop cannot be used along with
+// resampling.
+func WithOutputSampleRate(rate int) Option {
+	return func(c *config) {
+		c.outRate = rate
+	}
+}
+
+// A resampler converts stereo PCM from one sample rate to another, played at
+// a speed in thousandths. At a constant speed s, output sample g is the
+// source signal interpolated at source time g*in/out*s, so the output does
+// not depend on where reading started.
+type resampler struct {
+	in, out int64
+	speed   int64
+
+	// half is the number of source samples used on each side of the
+	// interpolated time, and kernel the values of the kernel at every
+	// 1/resamplePhases source sample from 0 to half.
+	half   int
+	kernel []float32
+
+	// x holds the source samples from index start on, t is the source time
+	// of the next output sample in units of 1/(out*speedScale) samples, and
+	// pending holds the bytes of an output sample partially returned.
+	x       [][2]float32
+	start   int64
+	t       int64
+	pending []byte
+	eof     bool
+
+	// raw holds source PCM read, rawN bytes of which are a partial sample.
+	raw  [4096]byte
+	rawN int
+}
+
+func newResampler(in, out int, speed int64) *resampler {
+	r := &resampler{in: int64(in), out: int64(out)}
+	r.setSpeed(speed)
+	return r
+}
+
+// setSpeed sets the speed and computes the kernel for it.
+func (r *resampler) setSpeed(speed int64) {
+	r.speed = speed
+	// The cutoff is a little under the lower of the Nyquist frequencies,
+	// relative to that of the source.
+	cutoff := 0.95 * min(1, float64(r.out*speedScale)/float64(r.in*speed))
+	half := int(math.Ceil(resampleZeros / cutoff))
+	kernel := slices.Grow(r.kernel[:0], half*resamplePhases+2)[:half*resamplePhases+2]
+	for k := range kernel {
+		u := float64(k) / resamplePhases
+		if u >= float64(half) {
+			kernel[k] = 0
+			continue
+		}
+		v := cutoff
+		if u > 0 {
+			v = math.Sin(math.Pi*cutoff*u) / (math.Pi * u)
+		}
+		// Blackman window.
+		w := u / float64(half)
+		v *= 0.42 + 0.5*math.Cos(math.Pi*w) + 0.08*math.Cos(2*math.Pi*w)
+		kernel[k] = float32(v)
+	}
+	r.half, r.kernel = half, kernel
+}
+
+// outSamples returns the number of output samples before source sample n.
+func (r *resampler) outSamples(n int64) int64 {
+	return (n*r.out + r.in - 1) / r.in
+}
+
+// timeOf returns the source time of output sample g at speed 1.
+func (r *resampler) timeOf(g int64) int64 {
+	return g * r.in * speedScale
+}
+
+// reset restarts the resampler at source time t, the source being read from
+// sample start.
+func (r *resampler) reset(t, start int64) {
+	r.t = t
+	r.start = start
+	r.x = r.x[:0]
+	r.pending = nil
+	r.eof = false
+	r.rawN = 0
+}
+
+// kernelAt returns the value of the kernel at u source samples.
+func (r *resampler) kernelAt(u float64) float32 {
+	a := math.Abs(u) * resamplePhases
+	k := int(a)
+	if k >= len(r.kernel)-1 {
+		return 0
+	}
+	f := float32(a - float64(k))
+	return r.kernel[k] + (r.kernel[k+1]-r.kernel[k])*f
+}
+
+// fill reads source samples from read until x holds sample n or the source
+// ends.
+func (r *resampler) fill(n int64, read func([]byte) (int, error)) error {
+	// Drop the samples no longer needed.
+	if drop := int(min(n-int64(2*r.half)-r.start, int64(len(r.x)))); drop > len(r.x)/2 {
+		r.x = r.x[:copy(r.x, r.x[drop:])]
+		r.start += int64(drop)
+	}
+	for !r.eof && r.start+int64(len(r.x)) <= n {
+		m, err := read(r.raw[r.rawN:])
+		m += r.rawN
+		for i := 0; i+4 <= m; i += 4 {
+			r.x = append(r.x, [2]float32{
+				float32(int16(uint16(r.raw[i]) | uint16(r.raw[i+1])<<8)),
+				float32(int16(uint16(r.raw[i+2]) | uint16(r.raw[i+3])<<8)),
+			})
+		}
+		r.rawN = copy(r.raw[:], r.raw[m/4*4:m])
+		if errors.Is(err, io.EOF) {
+			r.eof = true
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// next computes the output sample at time t into b. It returns false at the
+// end of the source.
+func (r *resampler) next(b []byte, read func([]byte) (int, error)) (bool, error) {
+	den := r.out * speedScale
+	i := r.t / den
+	frac := float64(r.t%den) / float64(den)
+	if err := r.fill(i+int64(r.half), read); err != nil {
+		return false, err
+	}
+	if i >= r.start+int64(len(r.x)) {
+		return false, nil
+	}
+	var v [2]float32
+	if frac == 0 && r.in*r.speed == den {
+		// Neither the rate nor the speed changes: copy the samples.
+		v = r.x[i-r.start]
+	} else {
+		v = r.interpolate(i, frac)
+	}
+	for ch := range v {
+		s := int16(min(max(math.Round(float64(v[ch])), math.MinInt16), math.MaxInt16))
+		b[2*ch] = byte(s)
+		b[2*ch+1] = byte(uint16(s) >> 8)
+	}
+	r.t += r.in * r.speed
+	return true, nil
+}
+
+// interpolate returns the source signal at time i+frac.
+func (r *resampler) interpolate(i int64, frac float64) [2]float32 {
+	var v [2]float32
+	for j := max(i-int64(r.half)+1, r.start); j <= i+int64(r.half) && j < r.start+int64(len(r.x)); j++ {
+		h := r.kernelAt(float64(i-j) + frac)
+		s := r.x[j-r.start]
+		v[0] += h * s[0]
+		v[1] += h * s[1]
+	}
+	return v
+}
+
+// read reads resampled 16-bit PCM into buf, reading the source with read.
+func (r *resampler) read(buf []byte, read func([]byte) (int, error)) (int, error) {
+	n := copy(buf, r.pending)
+	r.pending = r.pending[n:]
+	var sample [4]byte
+	for n < len(buf) {
+		b := buf[n:]
+		if len(b) < 4 {
+			b = sample[:]
+		}
+		ok, err := r.next(b, read)
+		if err != nil || !ok {
+			if n > 0 {
+				return n, nil
+			}
+			if err == nil {
+				err = io.EOF
+			}
+			return 0, err
+		}
+		if len(buf)-n < 4 {
+			m := copy(buf[n:], sample[:])
+			r.pending = append(r.pending[:0], sample[m:]...)
+			return len(buf), nil
+		}
+		n += 4
+	}
+	return n, nil
+}
+
+// readPCM reads 16-bit PCM into buf, resampled if needed.
+func (d *Decoder) readPCM(buf []byte) (int, error) {
+	if d.resampler == nil {
+		return d.read(buf)
+	}
+	return d.resampler.read(buf, d.read)
+}
+
+// playedTime returns the source time of the next sample returned, in the
+// units of resampler.t.
+func (d *Decoder) playedTime() int64 {
+	r := d.resampler
+	// The bytes not returned yet were resampled at the current speed.
+	return r.t - int64(len(r.pending)+len(d.blockTail))*r.in*r.speed/4
+}
+
+// outPos returns the position in bytes of 16-bit PCM in the resampled
+// output, at speed 1.
+func (d *Decoder) outPos() int64 {
+	return 4 * d.playedTime() / (d.resampler.in * speedScale)
+}
+
+// playedPos returns the position in the source PCM of the next sample
+// returned.
+func (d *Decoder) playedPos() int64 {
+	r := d.resampler
+	if r == nil {
+		return d.pos
+	}
+	return 4 * (d.playedTime() / (r.out * speedScale))
+}
+
+// seekOutput moves to output sample g of the resampler. The source is read
+// from a little before the sample so that the kernel has all its inputs.
+func (d *Decoder) seekOutput(g int64) error {
+	if d.length == invalidLength {
+		return errors.New("mp3: seek not supported on non-seekable source")
+	}
+	r := d.resampler
+	g = min(max(g, 0), r.outSamples(d.length/4))
+	s := max(g*r.in/r.out-int64(r.half)+1, 0)
+	pos, err := d.seekSource(4*s, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	r.reset(r.timeOf(g), pos/4)
+	return nil
+}
+
+// seekResampled implements seek, whose offsets are in the source PCM, when
+// resampling.
+func (d *Decoder) seekResampled(offset int64, whence int) (int64, error) {
+	pos := d.playedPos()
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		if offset == 0 {
+			return pos, nil
+		}
+		pos += offset
+	case io.SeekEnd:
+		pos = d.length + offset
+	default:
+		return 0, errors.New("mp3: invalid whence")
+	}
+	return pos, d.seekOutput(d.resampler.outSamples(max(pos, 0) / 4))
+}
+
+// seekOutputBytes implements Seek when resampling.
+func (d *Decoder) seekOutputBytes(offset int64, whence int) (int64, error) {
+	size := int64(2 * d.outFormat.BytesPerSample())
+	pos := d.outPos() / 4 * size
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		if offset == 0 {
+			return pos, nil
+		}
+		pos += offset
+	case io.SeekEnd:
+		pos = d.Length() + offset
+	default:
+		return 0, errors.New("mp3: invalid whence")
+	}
+	// Land on the start of the sample holding pos.
+	if pos > 0 {
+		pos -= pos % size
+	}
+	return pos, d.seekOutput(max(pos, 0) / size)
+}
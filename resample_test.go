@@ -0,0 +1,132 @@
+package mp3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+// sineSource returns a reader of n samples of 16-bit stereo PCM of a sine
+// of frequency f at rate, and the sine.
+func sineSource(n, rate int, f float64) (func([]byte) (int, error), func(t float64) float64) {
+	sine := func(t float64) float64 { return 10000 * math.Sin(2*math.Pi*f*t) }
+	var pcm []byte
+	for i := range n {
+		v := uint16(int16(math.Round(sine(float64(i) / float64(rate)))))
+		pcm = binary.LittleEndian.AppendUint16(pcm, v)
+		pcm = binary.LittleEndian.AppendUint16(pcm, v)
+	}
+	return bytes.NewReader(pcm).Read, sine
+}
+
+func TestResampler_Sine(t *testing.T) {
+	for _, tt := range []struct{ in, out int }{
+		{44100, 48000},
+		{48000, 44100},
+		{16000, 48000},
+		{48000, 8000},
+	} {
+		const f = 1000
+		read, sine := sineSource(tt.in, tt.in, f)
+		r := newResampler(tt.in, tt.out, speedScale)
+		pcm, err := io.ReadAll(readerFunc(func(p []byte) (int, error) { return r.read(p, read) }))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(pcm)/4, tt.out; got != want {
+			t.Errorf("%d to %d Hz: %d samples, want %d", tt.in, tt.out, got, want)
+		}
+		// Skip the edges, where the kernel lacks inputs.
+		var signal, noise float64
+		for i := tt.out / 10; i < len(pcm)/4-tt.out/10; i++ {
+			want := sine(float64(i) / float64(tt.out))
+			got := float64(int16(binary.LittleEndian.Uint16(pcm[4*i:])))
+			signal += want * want
+			noise += (got - want) * (got - want)
+		}
+		if snr := 10 * math.Log10(signal/noise); snr < 70 {
+			t.Errorf("%d to %d Hz: SNR %.1f dB, want at least 70 dB", tt.in, tt.out, snr)
+		}
+	}
+}
+
+type readerFunc func([]byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) { return f(p) }
+
+func TestWithOutputSampleRate(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data), WithOutputSampleRate(48000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.SampleRate() != 48000 {
+		t.Errorf("SampleRate() = %d, want 48000", d.SampleRate())
+	}
+	// An odd buffer size splits samples across reads.
+	var all []byte
+	buf := make([]byte, 4093)
+	for {
+		n, err := d.Read(buf)
+		all = append(all, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if int64(len(all)) != d.Length() {
+		t.Errorf("read %d bytes, Length() = %d", len(all), d.Length())
+	}
+	if want := (1774080/4*48000 + 44099) / 44100 * 4; d.Length() != int64(want) {
+		t.Errorf("Length() = %d, want %d", d.Length(), want)
+	}
+	if d.SampleCount() != d.Length()/4 || d.SamplePosition() != d.SampleCount() {
+		t.Errorf("SampleCount() = %d, SamplePosition() = %d at the end", d.SampleCount(), d.SamplePosition())
+	}
+
+	// Seeking gives the same samples as reading from the start.
+	for _, sample := range []int64{0, 1, 48000, 300001} {
+		if err := d.SeekToSample(sample); err != nil {
+			t.Fatal(err)
+		}
+		if d.SamplePosition() != sample {
+			t.Errorf("SamplePosition() = %d after seeking to %d", d.SamplePosition(), sample)
+		}
+		got := make([]byte, 8192)
+		if _, err := io.ReadFull(d, got); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, all[4*sample:4*sample+8192]) {
+			t.Errorf("PCM after seeking to sample %d differs", sample)
+		}
+	}
+	pos, err := d.Seek(-4000, io.SeekEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rest, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos != d.Length()-4000 || !bytes.Equal(rest, all[pos:]) {
+		t.Errorf("Seek(-4000, io.SeekEnd) = %d, read %d bytes", pos, len(rest))
+	}
+	if err := d.SeekToTime(5 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if d.SamplePosition() != 5*48000 || d.Position() != 5*time.Second {
+		t.Errorf("SamplePosition() = %d, Position() = %v after SeekToTime(5s)", d.SamplePosition(), d.Position())
+	}
+	if _, _, err := d.DecodeFrame(); err == nil {
+		t.Error("DecodeFrame with resampling succeeded")
+	}
+}
@@ -0,0 +1,110 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+// decodeFresh decodes data with a new decoder.
+func decodeFresh(t *testing.T, data []byte) (*Decoder, []byte) {
+	t.Helper()
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d, pcm
+}
+
+func TestDecoder_ResetAcrossSampleRates(t *testing.T) {
+	files := []string{"example/classic_lame.mp3", "example/mpeg2.mp3", "example/classic_lame.mp3"}
+	var data [][]byte
+	for _, name := range files {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("failed to read test file: %v", err)
+		}
+		data = append(data, b)
+	}
+
+	d, err := NewDecoder(bytes.NewReader(data[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Stop the first file midway, with the filterbank and buffers in use.
+	if _, err := io.ReadFull(d, make([]byte, 123457)); err != nil {
+		t.Fatal(err)
+	}
+	d.SetChannelEnabled(0, false)
+
+	for i, b := range data[1:] {
+		if err := d.Reset(bytes.NewReader(b)); err != nil {
+			t.Fatalf("Reset to %s failed: %v", files[i+1], err)
+		}
+		ref, want := decodeFresh(t, b)
+		if d.SampleRate() != ref.SampleRate() || d.BytesPerFrame() != ref.BytesPerFrame() ||
+			d.Length() != ref.Length() || len(d.frameStarts) != len(ref.frameStarts) {
+			t.Errorf("%s: SampleRate %d, BytesPerFrame %d, Length %d after Reset, want %d, %d, %d",
+				files[i+1], d.SampleRate(), d.BytesPerFrame(), d.Length(),
+				ref.SampleRate(), ref.BytesPerFrame(), ref.Length())
+		}
+		got, err := io.ReadAll(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: PCM after Reset differs from a new decoder", files[i+1])
+		}
+	}
+	if d.SampleRate() != 44100 {
+		t.Errorf("SampleRate() = %d, want 44100", d.SampleRate())
+	}
+}
+
+func TestDecoder_ResetClearsMetadataAndOptions(t *testing.T) {
+	tagged, err := testsupport.Generate(testsupport.Options{Tags: map[string]string{"TIT2": "First"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := testsupport.Generate(testsupport.Options{SampleRate: 22050, Bitrate: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewDecoder(bytes.NewReader(tagged), WithPrimingSilence(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Metadata() == nil {
+		t.Fatal("no metadata for the tagged stream")
+	}
+	if err := d.Reset(bytes.NewReader(plain)); err != nil {
+		t.Fatal(err)
+	}
+	if d.Metadata() != nil {
+		t.Errorf("Metadata() = %+v after Reset to an untagged stream, want nil", d.Metadata())
+	}
+	if d.priming != 0 {
+		t.Errorf("priming = %d after Reset without options, want 0", d.priming)
+	}
+	if want := int64(10 * 576 * 4); d.Length() != want {
+		t.Errorf("Length() = %d, want %d", d.Length(), want)
+	}
+
+	if err := d.Reset(bytes.NewReader(nil)); err == nil {
+		t.Error("Reset to an empty stream succeeded")
+	}
+	if err := d.Reset(bytes.NewReader(tagged)); err != nil {
+		t.Fatalf("Reset after a failed Reset: %v", err)
+	}
+	if d.Metadata().Title() != "First" {
+		t.Errorf("title = %q after Reset, want First", d.Metadata().Title())
+	}
+}
@@ -0,0 +1,21 @@
+package mp3
+
+// A ScanProgressFunc receives the progress of the scan of the frames of a
+// seekable source: the number of bytes scanned so far out of the size of
+// the stream.
+type ScanProgressFunc func(scanned, total int64)
+
+// scanProgressInterval is the number of frames scanned between calls to
+// the ScanProgressFunc.
+const scanProgressInterval = 256
+
+// WithScanProgress makes NewDecoder call fn while it scans the frames of an
+// io.Seeker source to index them, which can take seconds on large files, so
+// that user interfaces can show a loading indicator. fn is called every few
+// hundred frames and once at the end with scanned equal to total. It is not
+// called for other sources, which are not scanned.
+func WithScanProgress(fn ScanProgressFunc) Option {
+	return func(c *config) {
+		c.scanProgress = fn
+	}
+}
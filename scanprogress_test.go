@@ -0,0 +1,45 @@
+package mp3
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestWithScanProgress(t *testing.T) {
+	data, err := os.ReadFile("example/mpeg2.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	var calls [][2]int64
+	_, err = NewDecoder(bytes.NewReader(data), WithScanProgress(func(scanned, total int64) {
+		calls = append(calls, [2]int64{scanned, total})
+	}))
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if len(calls) < 2 {
+		t.Fatalf("got %d calls, want several", len(calls))
+	}
+	for i, c := range calls {
+		if c[1] != int64(len(data)) {
+			t.Fatalf("call %d: total = %d, want %d", i, c[1], len(data))
+		}
+		if i > 0 && c[0] <= calls[i-1][0] {
+			t.Fatalf("call %d: scanned = %d, not after %d", i, c[0], calls[i-1][0])
+		}
+	}
+	if last := calls[len(calls)-1]; last[0] != last[1] {
+		t.Errorf("last call scanned %d of %d bytes, want all", last[0], last[1])
+	}
+
+	calls = nil
+	if _, err := NewDecoder(&nonSeekableReader{bytes.NewReader(data)}, WithScanProgress(func(scanned, total int64) {
+		calls = append(calls, [2]int64{scanned, total})
+	})); err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("got %d calls for a non-seekable source, want none", len(calls))
+	}
+}
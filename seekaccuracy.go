@@ -0,0 +1,66 @@
+package mp3
+
+// A SeekMode selects the trade-off between speed and precision of Seek.
+type SeekMode int
+
+const (
+	// SeekModeExact makes Seek decode warmup frames and discard samples to
+	// land on the requested sample, with the same audio as linear playback.
+	// It is the default.
+	SeekModeExact SeekMode = iota
+
+	// SeekModeFast makes Seek jump to the frame boundary nearest to the
+	// requested position without decoding warmup frames. It is cheap enough
+	// for scrubbing, at the cost of a slight glitch in the first granule.
+	SeekModeFast
+)
+
+// WithSeekMode sets the seek mode. Seek returns the position it actually
+// landed on, which differs from the requested one with SeekModeFast.
+func WithSeekMode(m SeekMode) Option {
+	return func(c *config) {
+		c.seekMode = m
+	}
+}
+
+// SeekAccuracy describes how precisely Seek lands on the requested position,
+// depending on the seeking strategy of the decoder.
+type SeekAccuracy int
+
+const (
+	// SeekUnsupported means the decoder cannot seek, because the source is
+	// not io.Seeker.
+	SeekUnsupported SeekAccuracy = iota
+
+	// SeekFrameAccurate means Seek lands on the frame boundary nearest to
+	// the requested position.
+	SeekFrameAccurate
+
+	// SeekExact means Seek lands on the requested sample, using the index
+	// of all frames built when the decoder was created.
+	SeekExact
+)
+
+// String returns the name of the accuracy, such as "exact".
+func (a SeekAccuracy) String() string {
+	switch a {
+	case SeekFrameAccurate:
+		return "frame-accurate"
+	case SeekExact:
+		return "exact"
+	}
+	return "unsupported"
+}
+
+// SeekAccuracy reports how precisely Seek and the methods built on it, such
+// as SeekToTime, land on the requested position with the current seeking
+// strategy. Applications can use it to display honest scrubber behavior.
+func (d *Decoder) SeekAccuracy() SeekAccuracy {
+	if d.length == invalidLength {
+		return SeekUnsupported
+	}
+	if d.seekMode == SeekModeFast {
+		return SeekFrameAccurate
+	}
+	return SeekExact
+}
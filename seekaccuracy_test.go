@@ -0,0 +1,79 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestSeekAccuracy(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.SeekAccuracy(); got != SeekExact {
+		t.Errorf("SeekAccuracy() = %v, want exact", got)
+	}
+
+	ns, err := NewDecoder(nonSeekable{bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ns.SeekAccuracy(); got != SeekUnsupported {
+		t.Errorf("SeekAccuracy() = %v for a non-seekable source, want unsupported", got)
+	}
+}
+
+func TestSeekAccuracy_String(t *testing.T) {
+	for a, want := range map[SeekAccuracy]string{
+		SeekUnsupported:   "unsupported",
+		SeekFrameAccurate: "frame-accurate",
+		SeekExact:         "exact",
+	} {
+		if got := a.String(); got != want {
+			t.Errorf("SeekAccuracy(%d).String() = %q, want %q", int(a), got, want)
+		}
+	}
+}
+
+func TestWithSeekMode_Fast(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data), WithSeekMode(SeekModeFast), WithPrimingSilence(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.SeekAccuracy(); got != SeekFrameAccurate {
+		t.Errorf("SeekAccuracy() = %v, want frame-accurate", got)
+	}
+	bpf := int64(d.BytesPerFrame())
+	for _, tt := range []struct{ pos, want int64 }{
+		{200, 200}, // In the priming silence
+		{400 + 10*bpf + 100, 400 + 10*bpf},
+		{400 + 10*bpf - 100, 400 + 10*bpf},
+		{400 + 10*bpf + bpf/2, 400 + 11*bpf},
+	} {
+		got, err := d.Seek(tt.pos, io.SeekStart)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want || d.pos != tt.want {
+			t.Errorf("Seek(%d) = %d, position %d, want %d", tt.pos, got, d.pos, tt.want)
+		}
+	}
+	// Decoding continues from the frame boundary.
+	rest, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(rest)) != d.Length()-(400+11*bpf) {
+		t.Errorf("read %d bytes after the seek, want %d", len(rest), d.Length()-(400+11*bpf))
+	}
+}
@@ -0,0 +1,174 @@
+// Package seekcache makes a non-seekable stream, such as a pipe or a network
+// connection, seekable by keeping the bytes already read.
+//
+// Wrapping such a stream before giving it to mp3.NewDecoder enables
+// Duration, backward Skip and SeekToTime. Note that the decoder scans the
+// whole stream to compute its length, so the stream is read to its end, and
+// cached, when the decoder is created.
+package seekcache
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// store is the append-only storage of the cached bytes.
+type store interface {
+	io.ReaderAt
+	io.Writer
+}
+
+type memStore struct {
+	data []byte
+}
+
+func (m *memStore) ReadAt(buf []byte, off int64) (int, error) {
+	if off >= int64(len(m.data)) {
+		return 0, io.EOF
+	}
+	n := copy(buf, m.data[off:])
+	if n < len(buf) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (m *memStore) Write(buf []byte) (int, error) {
+	m.data = append(m.data, buf...)
+	return len(buf), nil
+}
+
+// A Reader reads an underlying stream and keeps what it read, so that it can
+// seek anywhere in the part already read. Seeking further reads the stream up
+// to the new position. It implements io.ReadSeekCloser.
+//
+// A Reader is not safe for concurrent use.
+type Reader struct {
+	src   io.Reader
+	store store
+	file  *os.File
+
+	// cached is the number of bytes read from src and stored.
+	cached int64
+	pos    int64
+	srcErr error
+
+	// buf is the buffer of fill, allocated when first needed.
+	buf []byte
+}
+
+// New returns a Reader that keeps the bytes of r in memory.
+func New(r io.Reader) *Reader {
+	return &Reader{src: r, store: &memStore{}}
+}
+
+// NewTempFile returns a Reader that keeps the bytes of r in a temporary file
+// created in dir, or in the default directory for temporary files if dir is
+// empty. The file is removed by Close.
+func NewTempFile(r io.Reader, dir string) (*Reader, error) {
+	f, err := os.CreateTemp(dir, "seekcache-*")
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{src: r, store: f, file: f}, nil
+}
+
+// Read implements io.Reader.
+func (r *Reader) Read(buf []byte) (int, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	if err := r.fill(r.pos); err != nil {
+		return 0, err
+	}
+	if r.pos < r.cached {
+		n, err := r.store.ReadAt(buf[:min(int64(len(buf)), r.cached-r.pos)], r.pos)
+		r.pos += int64(n)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+		return n, err
+	}
+	if r.srcErr != nil {
+		return 0, r.srcErr
+	}
+	n, err := r.readSource(buf)
+	r.pos += int64(n)
+	if n > 0 {
+		return n, nil
+	}
+	return 0, err
+}
+
+// readSource reads from the underlying stream and stores the bytes.
+func (r *Reader) readSource(buf []byte) (int, error) {
+	n, err := r.src.Read(buf)
+	if n > 0 {
+		if _, werr := r.store.Write(buf[:n]); werr != nil {
+			return 0, werr
+		}
+		r.cached += int64(n)
+	}
+	if err != nil {
+		r.srcErr = err
+	}
+	return n, err
+}
+
+// fill reads the underlying stream until pos bytes are cached or the stream
+// ends.
+func (r *Reader) fill(pos int64) error {
+	if r.cached >= pos || r.srcErr != nil {
+		return nil
+	}
+	if r.buf == nil {
+		r.buf = make([]byte, 32*1024)
+	}
+	for r.cached < pos && r.srcErr == nil {
+		if _, err := r.readSource(r.buf[:min(int64(len(r.buf)), pos-r.cached)]); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Seek implements io.Seeker. Seeking relative to the end reads the whole
+// underlying stream.
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = r.pos + offset
+	case io.SeekEnd:
+		for r.srcErr == nil {
+			if err := r.fill(r.cached + 32*1024); err != nil {
+				return 0, err
+			}
+		}
+		pos = r.cached + offset
+	default:
+		return 0, errors.New("seekcache: invalid whence")
+	}
+	if pos < 0 {
+		return 0, errors.New("seekcache: negative position")
+	}
+	r.pos = pos
+	return pos, nil
+}
+
+// Close removes the temporary file, if any. It does not close the
+// underlying stream.
+func (r *Reader) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	if rerr := os.Remove(r.file.Name()); err == nil {
+		err = rerr
+	}
+	r.file = nil
+	return err
+}
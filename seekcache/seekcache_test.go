@@ -0,0 +1,118 @@
+package seekcache
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/llehouerou/go-mp3"
+)
+
+func TestReader_Seek(t *testing.T) {
+	data := make([]byte, 100000)
+	for i := range data {
+		data[i] = byte(i * 13)
+	}
+	r := New(io.MultiReader(bytes.NewReader(data)))
+
+	buf := make([]byte, 100)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatalf("ReadFull failed: %v", err)
+	}
+	// Forward past what was read.
+	if _, err := r.Seek(50000, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatalf("ReadFull failed: %v", err)
+	}
+	if !bytes.Equal(buf, data[50000:50100]) {
+		t.Error("data after forward Seek differs")
+	}
+	// Back into the cached part.
+	if _, err := r.Seek(-40000, io.SeekCurrent); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatalf("ReadFull failed: %v", err)
+	}
+	if !bytes.Equal(buf, data[10100:10200]) {
+		t.Error("data after backward Seek differs")
+	}
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	if end != int64(len(data)) {
+		t.Errorf("Seek(0, SeekEnd) = %d, want %d", end, len(data))
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	all, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(all, data) {
+		t.Error("full read differs")
+	}
+
+	// Reading the cached part does not allocate.
+	allocs := testing.AllocsPerRun(100, func() {
+		r.Seek(1000, io.SeekStart)
+		r.Read(buf[:4])
+	})
+	if allocs != 0 {
+		t.Errorf("cached Read allocates %v times", allocs)
+	}
+}
+
+func TestReader_Decoder(t *testing.T) {
+	data, err := os.ReadFile("../example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	local, err := mp3.NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if err := local.SeekToTime(3 * time.Second); err != nil {
+		t.Fatalf("SeekToTime failed: %v", err)
+	}
+	want, err := io.ReadAll(local)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	r, err := NewTempFile(io.MultiReader(bytes.NewReader(data)), t.TempDir())
+	if err != nil {
+		t.Fatalf("NewTempFile failed: %v", err)
+	}
+	d, err := mp3.NewDecoder(r)
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	if d.Duration() != local.Duration() {
+		t.Errorf("Duration() = %v, want %v", d.Duration(), local.Duration())
+	}
+	if err := d.SeekToTime(3 * time.Second); err != nil {
+		t.Fatalf("SeekToTime failed: %v", err)
+	}
+	got, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("decoding through the cache differs from local decoding")
+	}
+
+	name := r.file.Name()
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Errorf("temporary file still exists: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+// SilenceConfig configures DetectSilence. Zero fields take their defaults.
+type SilenceConfig struct {
+	// Threshold is the RMS level in dBFS below which a frame is considered
+	// silent. The default is -60.
+	Threshold float64
+
+	// MinDuration is the shortest silent stretch within the audio reported
+	// as a region. The default is 2 seconds.
+	MinDuration time.Duration
+}
+
+// A SilentRegion is a silent stretch of a stream.
+type SilentRegion struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// A SilenceReport describes the silence of a stream found by DetectSilence.
+type SilenceReport struct {
+	// Leading and Trailing are the lengths of the silence at the start and
+	// at the end of the stream. A stream that is silent throughout only has
+	// leading silence.
+	Leading  time.Duration
+	Trailing time.Duration
+
+	// Regions lists the silent stretches of at least MinDuration between the
+	// leading and the trailing silence, in order.
+	Regions []SilentRegion
+
+	// Duration is the duration of the stream.
+	Duration time.Duration
+}
+
+// DetectSilence scans the MP3 stream of r and reports its silent stretches,
+// such as the silence to trim at the start and end of a track or the pauses
+// between the tracks of a recording. It uses AnalyzeFrame, so it is much
+// faster than decoding the stream. The resolution is a frame, about 26 ms at
+// 44100 Hz.
+func DetectSilence(r io.Reader, cfg SilenceConfig) (*SilenceReport, error) {
+	if cfg.Threshold == 0 {
+		cfg.Threshold = -60
+	}
+	if cfg.MinDuration == 0 {
+		cfg.MinDuration = 2 * time.Second
+	}
+	d, err := NewDecoder(r)
+	if err != nil {
+		return nil, err
+	}
+	threshold := silencePower(cfg.Threshold)
+	rep := &SilenceReport{}
+	var silenceStart time.Duration // Start of the current silent stretch
+	silent, sound := false, false
+	for {
+		a, err := d.AnalyzeFrame()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if max(a.Power[0], a.Power[1]) < threshold {
+			if !silent {
+				silent, silenceStart = true, a.Time
+			}
+			continue
+		}
+		if silent {
+			switch {
+			case !sound:
+				rep.Leading = a.Time
+			case a.Time-silenceStart >= cfg.MinDuration:
+				rep.Regions = append(rep.Regions, SilentRegion{Start: silenceStart, End: a.Time})
+			}
+		}
+		silent, sound = false, true
+	}
+	rep.Duration = d.Position()
+	switch {
+	case silent && sound:
+		rep.Trailing = rep.Duration - silenceStart
+	case silent:
+		rep.Leading = rep.Duration
+	}
+	return rep, nil
+}
+
+// silencePower returns the mean square of the samples at an RMS level in
+// dBFS.
+func silencePower(dB float64) float64 {
+	return math.Pow(10, dB/10)
+}
+
+// WithSkipLeadingSilence makes the decoder skip the silent frames at the
+// start of the stream, whose RMS level is below thresholdDB dBFS, such as
+// -60. Read then starts with the first frame that is not silent, skipping
+// the priming silence of WithPrimingSilence too. The skipped silence still
+// counts in Length and Position.
+func WithSkipLeadingSilence(thresholdDB float64) Option {
+	return func(c *config) {
+		c.skipSilence = true
+		c.silenceLevel = thresholdDB
+	}
+}
+
+// skipLeadingSilence decodes frames until one is not silent, dropping the
+// PCM of the silent ones.
+func (d *Decoder) skipLeadingSilence(thresholdDB float64) error {
+	threshold := silencePower(thresholdDB)
+	d.pos = max(d.pos, d.priming)
+	d.decodePending()
+	for {
+		n := d.buf.Len() / 4
+		var power float64
+		for ch := range d.pcm {
+			var sum float64
+			for _, v := range d.pcm[ch][:n] {
+				sum += float64(v) * float64(v)
+			}
+			power = max(power, sum/float64(max(n, 1)))
+		}
+		if power >= threshold {
+			return nil
+		}
+		d.pos += int64(d.buf.Len())
+		d.buf.Reset()
+		if err := d.readFrame(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+// silenceFrames returns n frames of digital silence at 44100 Hz.
+func silenceFrames(t *testing.T, n int) []byte {
+	t.Helper()
+	data, err := testsupport.Generate(testsupport.Options{Frames: n})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDetectSilence(t *testing.T) {
+	music, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	frame := time.Duration(1152) * time.Second / 44100
+	// The music fades in and out from a noise floor at about -84 dBFS.
+	cfg := SilenceConfig{Threshold: -90}
+	stream := slices.Concat(silenceFrames(t, 40), music, silenceFrames(t, 120), music, silenceFrames(t, 60))
+	rep, err := DetectSilence(bytes.NewReader(stream), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Leading < 40*frame || rep.Leading > 50*frame {
+		t.Errorf("leading silence %v, want about %v", rep.Leading, 40*frame)
+	}
+	if rep.Trailing < 60*frame || rep.Trailing > 70*frame {
+		t.Errorf("trailing silence %v, want about %v", rep.Trailing, 60*frame)
+	}
+	if len(rep.Regions) != 1 {
+		t.Fatalf("got regions %v, want 1", rep.Regions)
+	}
+	if gap := rep.Regions[0].End - rep.Regions[0].Start; gap < 120*frame || gap > 130*frame {
+		t.Errorf("region %v long, want about %v", gap, 120*frame)
+	}
+	d, err := NewDecoder(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Duration != d.Duration() {
+		t.Errorf("Duration = %v, want %v", rep.Duration, d.Duration())
+	}
+
+	// Shorter gaps are not reported.
+	rep, err = DetectSilence(bytes.NewReader(stream), SilenceConfig{Threshold: -90, MinDuration: 4 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Regions) != 0 {
+		t.Errorf("got regions %v with a 4 s minimum, want none", rep.Regions)
+	}
+
+	rep, err = DetectSilence(bytes.NewReader(silenceFrames(t, 10)), SilenceConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 10 * 1152 * time.Second / 44100; rep.Leading != want || rep.Trailing != 0 {
+		t.Errorf("silent stream: leading %v, trailing %v, want %v and 0", rep.Leading, rep.Trailing, want)
+	}
+}
+
+func TestWithSkipLeadingSilence(t *testing.T) {
+	music, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	stream := slices.Concat(silenceFrames(t, 40), music)
+	for _, seekable := range []bool{true, false} {
+		var r io.Reader = bytes.NewReader(stream)
+		if !seekable {
+			r = nonSeekable{r}
+		}
+		d, err := NewDecoder(r, WithSkipLeadingSilence(-90))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pos := d.SamplePosition(); pos < 40*1152 || pos > 50*1152 {
+			t.Errorf("seekable %v: starts at sample %d, want about %d", seekable, pos, 40*1152)
+		}
+		pcm, err := io.ReadAll(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pcm) < 1152*4 || bytes.Count(pcm[:1152*4], []byte{0}) == 1152*4 {
+			t.Errorf("seekable %v: output starts with a silent frame", seekable)
+		}
+	}
+}
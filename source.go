@@ -15,14 +15,39 @@
 package mp3
 
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
 	"io"
+	"slices"
+
+	"github.com/llehouerou/go-mp3/id3v2"
+	"github.com/llehouerou/go-mp3/internal/frameheader"
 )
 
 type source struct {
 	reader io.Reader
 	buf    []byte
 	pos    int64
+
+	// maxTagSize is the largest ID3v2 tag read by skipTags, or 0.
+	maxTagSize int
+
+	// onID3v2, if set, receives every ID3v2 tag skipped by skipTags,
+	// including its 10-byte header.
+	onID3v2 func(tag []byte)
+
+	// onTag, if set, receives every tag skipped by skipTags along with its
+	// offset.
+	onTag TagFunc
+
+	// While recording is set, the bytes read are appended to record, so that
+	// a partially read frame can be unread.
+	recording bool
+	record    []byte
+
+	// consumed counts the bytes read from reader.
+	consumed int64
 }
 
 func (s *source) Seek(position int64, whence int) (int64, error) {
@@ -47,33 +72,65 @@ func (s *source) skipTags() error {
 		}
 		switch string(buf) {
 		case "TAG":
-			buf := make([]byte, 125)
-			if _, err := s.ReadFull(buf); err != nil {
+			buf := make([]byte, 128)
+			copy(buf, "TAG")
+			if _, err := s.ReadFull(buf[3:]); err != nil {
 				return err
 			}
-
-		case "ID3":
-			// Skip version (2 bytes) and flag (1 byte)
-			buf := make([]byte, 3)
-			if _, err := s.ReadFull(buf); err != nil {
-				return err
+			if s.onTag != nil {
+				s.onTag(TagID3v1, s.pos-128, buf)
 			}
 
-			buf = make([]byte, 4)
-			n, err := s.ReadFull(buf)
+		case "ID3":
+			// Read version (2 bytes), flag (1 byte) and size (4 bytes)
+			header := make([]byte, 10)
+			copy(header, buf)
+			n, err := s.ReadFull(header[3:])
 			if err != nil {
 				return err
 			}
-			if n != 4 {
+			if n != 7 {
 				return nil
 			}
-			//nolint:gosec // buf is guaranteed to have 4 elements after ReadFull check above
-			size := (uint32(buf[0]) << 21) | (uint32(buf[1]) << 14) |
-				(uint32(buf[2]) << 7) | uint32(buf[3])
-			buf = make([]byte, size)
+			limit := s.remaining()
+			if s.maxTagSize > 0 && (limit < 0 || limit > int64(s.maxTagSize)) {
+				limit = int64(s.maxTagSize)
+			}
+			tagSize := id3v2TagSize(header, limit)
+			if exceeds(tagSize, s.maxTagSize) {
+				return &LimitError{Limit: "MaxTagSize", Max: s.maxTagSize}
+			}
+			buf = make([]byte, tagSize)
 			if _, err := s.ReadFull(buf); err != nil {
 				return err
 			}
+			extra, err := s.id3v2SizeCorrection(header, buf)
+			if err != nil {
+				return err
+			}
+			if extra > 0 {
+				more := make([]byte, extra)
+				if _, err := s.ReadFull(more); err != nil {
+					return err
+				}
+				buf = append(buf, more...)
+				// Pass on the tag with its size fixed.
+				size := len(buf)
+				if header[3] >= 4 && header[5]&id3v2.FlagFooter != 0 {
+					size -= id3v2.HeaderSize
+				}
+				header[6] = byte(size >> 21 & 0x7f)
+				header[7] = byte(size >> 14 & 0x7f)
+				header[8] = byte(size >> 7 & 0x7f)
+				header[9] = byte(size & 0x7f)
+			}
+			tag := append(header, buf...)
+			if s.onID3v2 != nil {
+				s.onID3v2(tag)
+			}
+			if s.onTag != nil {
+				s.onTag(TagID3v2, s.pos-int64(len(tag)), tag)
+			}
 
 		default:
 			s.Unread(buf)
@@ -82,6 +139,158 @@ func (s *source) skipTags() error {
 	}
 }
 
+// appendedTagStart returns the offset of the ID3v2 tag appended at the end of
+// the stream, or -1 if there is none. Such a tag is found through its "3DI"
+// footer, which is the last thing in the stream or is followed by an ID3v1
+// tag. The source position is left undefined.
+func (s *source) appendedTagStart() (int64, error) {
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	footerEnd := end
+	if end >= 128 {
+		if _, err := s.Seek(end-128, io.SeekStart); err != nil {
+			return 0, err
+		}
+		buf := make([]byte, 3)
+		if _, err := s.ReadFull(buf); err != nil {
+			return 0, err
+		}
+		if string(buf) == "TAG" {
+			footerEnd -= 128
+		}
+	}
+	if footerEnd < 20 {
+		return -1, nil
+	}
+	if _, err := s.Seek(footerEnd-10, io.SeekStart); err != nil {
+		return 0, err
+	}
+	footer := make([]byte, 10)
+	if _, err := s.ReadFull(footer); err != nil {
+		return 0, err
+	}
+	if string(footer[:3]) != "3DI" {
+		return -1, nil
+	}
+	size := (int64(footer[6]&0x7f) << 21) | (int64(footer[7]&0x7f) << 14) |
+		(int64(footer[8]&0x7f) << 7) | int64(footer[9]&0x7f)
+	start := footerEnd - 20 - size
+	if start < 0 {
+		return -1, nil
+	}
+	if _, err := s.Seek(start, io.SeekStart); err != nil {
+		return 0, err
+	}
+	header := make([]byte, 3)
+	if _, err := s.ReadFull(header); err != nil {
+		return 0, err
+	}
+	if string(header) != "ID3" {
+		return -1, nil
+	}
+	return start, nil
+}
+
+// id3v2TagSize returns the number of bytes following the 10-byte ID3v2 tag
+// header: the tag body and the footer, if any.
+//
+// limit is the number of bytes that can follow the header, or -1 if it is
+// unknown. A size that is not a syncsafe integer is read as a plain 32-bit
+// integer, as some old taggers store it, only if it is within limit, so that
+// a crafted header cannot declare a tag of several GiB.
+func id3v2TagSize(header []byte, limit int64) int {
+	size := (int(header[6]) << 21) | (int(header[7]) << 14) |
+		(int(header[8]) << 7) | int(header[9])
+	if header[6]|header[7]|header[8]|header[9] >= 0x80 {
+		if plain := int64(binary.BigEndian.Uint32(header[6:10])); plain <= limit {
+			size = int(plain)
+		}
+	}
+	if header[3] >= 4 && header[5]&id3v2.FlagFooter != 0 {
+		size += id3v2.HeaderSize
+	}
+	return size
+}
+
+// id3v2SizeCorrection returns how many bytes past its declared size an ID3v2
+// tag really ends, for tags whose size was computed by a faulty tagger.
+//
+// The size must count the extended header and the bytes inserted by
+// unsynchronisation, but some taggers leave them out. When the data right
+// after the declared end does not look like audio or another tag, the sizes
+// with either of these amounts added are tried instead.
+func (s *source) id3v2SizeCorrection(header, body []byte) (int, error) {
+	var candidates []int
+	if header[5]&id3v2.FlagUnsynchronisation != 0 {
+		if n := bytes.Count(body, []byte{0xff, 0x00}); n > 0 {
+			candidates = append(candidates, n)
+		}
+	}
+	if header[5]&id3v2.FlagExtendedHeader != 0 && header[3] == 3 && len(body) >= 4 {
+		// The version 2.3 extended header size excludes its own 4 bytes.
+		candidates = append(candidates, int(binary.BigEndian.Uint32(body[:4]))+4)
+	}
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+	if exceeds(len(body)+slices.Max(candidates), s.maxTagSize) {
+		return 0, &LimitError{Limit: "MaxTagSize", Max: s.maxTagSize}
+	}
+
+	// Peek far enough to check every candidate.
+	peek := make([]byte, slices.Max(candidates)+4)
+	n, err := s.ReadFull(peek)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+	peek = peek[:n]
+	s.Unread(peek)
+	if startsAudioOrTag(peek) {
+		return 0, nil
+	}
+	for _, c := range candidates {
+		if c+4 <= len(peek) && startsAudioOrTag(peek[c:]) {
+			return c, nil
+		}
+	}
+	return 0, nil
+}
+
+// startsAudioOrTag reports whether b starts with a frame header or a tag.
+func startsAudioOrTag(b []byte) bool {
+	if len(b) < 4 {
+		return false
+	}
+	switch string(b[:3]) {
+	case "ID3", "TAG":
+		return true
+	}
+	return frameheader.FrameHeader(binary.BigEndian.Uint32(b)).IsValid()
+}
+
+// remaining returns the number of bytes left to read from the source, or -1
+// if it is unknown.
+func (s *source) remaining() int64 {
+	seeker, ok := s.reader.(io.Seeker)
+	if !ok {
+		return -1
+	}
+	pos, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return -1
+	}
+	if _, err := seeker.Seek(pos, io.SeekStart); err != nil {
+		return -1
+	}
+	return end - pos + int64(len(s.buf))
+}
+
 func (s *source) rewind() error {
 	if _, err := s.Seek(0, io.SeekStart); err != nil {
 		return err
@@ -94,6 +303,9 @@ func (s *source) rewind() error {
 func (s *source) Unread(buf []byte) {
 	s.buf = append(buf, s.buf...)
 	s.pos -= int64(len(buf))
+	if s.recording {
+		s.record = s.record[:max(len(s.record)-len(buf), 0)]
+	}
 }
 
 func (s *source) ReadFull(buf []byte) (int, error) {
@@ -105,7 +317,11 @@ func (s *source) ReadFull(buf []byte) (int, error) {
 		} else {
 			s.buf = nil
 		}
+		s.pos += int64(read)
 		if len(buf) == read {
+			if s.recording {
+				s.record = append(s.record, buf...)
+			}
 			return read, nil
 		}
 	}
@@ -118,5 +334,9 @@ func (s *source) ReadFull(buf []byte) (int, error) {
 		}
 	}
 	s.pos += int64(n)
+	s.consumed += int64(n)
+	if s.recording {
+		s.record = append(s.record, buf[:n+read]...)
+	}
 	return n + read, err
 }
@@ -0,0 +1,58 @@
+package mp3
+
+import "github.com/llehouerou/go-mp3/internal/consts"
+
+// A Spectrum holds the frequency lines of a granule of a channel, as
+// decoded from the stream before the synthesis filterbank.
+type Spectrum struct {
+	// Sample is the index of the first sample of the granule in the decoded
+	// stream, counted per channel.
+	Sample int64
+
+	// Channel is the channel of the lines: 0 for left and 1 for right. Mono
+	// frames only have channel 0.
+	Channel int
+
+	// Short reports whether the granule uses short blocks. Lines then holds
+	// three successive spectra of 192 lines, interleaved: line 3*i+w is the
+	// line i of window w. In mixed blocks, the lowest 36 lines are those of
+	// a long block.
+	Short bool
+
+	// Lines holds the 576 frequency lines of the granule, from 0 Hz up to
+	// half the sample rate of the stream. They are scaled so that the mean
+	// square of the samples of the granule is half the sum of their squares.
+	Lines []float32
+}
+
+// A SpectrumFunc receives the spectra decoded by a Decoder. The lines are
+// only valid during the call and must not be modified.
+type SpectrumFunc func(s Spectrum)
+
+// SetSpectrumFunc sets a function called with the frequency lines of each
+// granule and channel of the frames decoded by Read, DecodeFrame and
+// AnalyzeFrame, so that visualizers and analysis tools can use the
+// frequency-domain data of the stream instead of running an FFT on the PCM.
+// The warmup frames decoded by Seek are not reported. A nil fn removes the
+// function.
+//
+// Like SetChannelEnabled, the setting applies from the next decoded frame.
+func (d *Decoder) SetSpectrumFunc(fn SpectrumFunc) {
+	d.spectrum = fn
+	if fn != nil && d.spectrumHook == nil {
+		d.spectrumHook = d.reportSpectrum
+	}
+}
+
+// reportSpectrum calls the SpectrumFunc with the lines of granule gr and
+// channel ch of the current frame.
+func (d *Decoder) reportSpectrum(gr, ch int, lines *[consts.SamplesPerGr]float32) {
+	h := d.frame.Header()
+	n := d.pcmBytes(h) / 4 / int64(h.Granules())
+	d.spectrum(Spectrum{
+		Sample:  d.frameSample + int64(gr)*n,
+		Channel: ch,
+		Short:   d.frame.ShortBlocks(gr, ch),
+		Lines:   lines[:],
+	})
+}
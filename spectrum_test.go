@@ -0,0 +1,86 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestSetSpectrumFunc(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	collect := func(s *[]Spectrum) SpectrumFunc {
+		return func(g Spectrum) {
+			g.Lines = slices.Clone(g.Lines)
+			*s = append(*s, g)
+		}
+	}
+
+	var read []Spectrum
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SetSpectrumFunc(collect(&read))
+	if _, err := io.Copy(io.Discard, d); err != nil {
+		t.Fatal(err)
+	}
+	if want := 2 * 2 * d.FrameCount(); len(read) != want {
+		t.Fatalf("got %d spectra, want %d", len(read), want)
+	}
+	short := 0
+	for i, s := range read {
+		if want := int64(i/2) * 576; s.Sample != want || s.Channel != i%2 || len(s.Lines) != 576 {
+			t.Fatalf("spectrum %d: sample %d, channel %d, %d lines, want %d, %d, 576",
+				i, s.Sample, s.Channel, len(s.Lines), want, i%2)
+		}
+		if s.Short {
+			short++
+		}
+	}
+	if short == 0 {
+		t.Error("no granule with short blocks")
+	}
+
+	// AnalyzeFrame reports the same lines.
+	var analyzed []Spectrum
+	d, err = NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SetSpectrumFunc(collect(&analyzed))
+	for {
+		if _, err := d.AnalyzeFrame(); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !slices.EqualFunc(read, analyzed, func(a, b Spectrum) bool {
+		return a.Sample == b.Sample && a.Channel == b.Channel && a.Short == b.Short && slices.Equal(a.Lines, b.Lines)
+	}) {
+		t.Error("spectra of AnalyzeFrame differ from those of Read")
+	}
+
+	// Warmup frames of Seek are not reported.
+	var seeked []Spectrum
+	d.SetSpectrumFunc(collect(&seeked))
+	if _, err := d.Seek(100*d.BytesPerFrame()+400, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if len(seeked) != 4 || seeked[0].Sample != 100*1152 || !slices.Equal(seeked[0].Lines, read[400].Lines) {
+		t.Errorf("Seek reported %d spectra, want those of frame 100", len(seeked))
+	}
+	d.SetSpectrumFunc(nil)
+	if _, err := io.Copy(io.Discard, d); err != nil {
+		t.Fatal(err)
+	}
+	if len(seeked) != 4 {
+		t.Errorf("%d spectra reported after SetSpectrumFunc(nil)", len(seeked)-4)
+	}
+}
@@ -0,0 +1,50 @@
+package mp3
+
+import (
+	"errors"
+	"math"
+)
+
+// SetSpeed plays the audio ratio times faster, such as 1.25 or 1.5 for
+// podcasts, by resampling it: the pitch changes along with the tempo. The
+// ratio is rounded to thousandths and must be between 0.25 and 4.
+//
+// Position, Duration and the other times stay in the time of the stream,
+// as do the byte and sample counts and positions, such as those of Length,
+// Seek and SamplePosition, which are those of playback at speed 1. The
+// speed applies from the next sample read. DecodeFrame and SetLoop cannot
+// be used along with SetSpeed.
+func (d *Decoder) SetSpeed(ratio float64) error {
+	if !(ratio >= 0.25 && ratio <= 4) {
+		return errors.New("mp3: speed must be between 0.25 and 4")
+	}
+	if d.loopEnd != 0 {
+		return errors.New("mp3: speed cannot be changed with a loop")
+	}
+	speed := int64(math.Round(ratio * speedScale))
+	if d.resampler != nil {
+		d.resampler.setSpeed(speed)
+		return nil
+	}
+	if speed == speedScale {
+		return nil
+	}
+	r := newResampler(d.sampleRate, d.sampleRate, speed)
+	d.resampler = r
+	if d.length != invalidLength {
+		// Read the samples before the position again for the kernel.
+		return d.seekOutput(d.pos / 4)
+	}
+	// The source is read on from after the PCM kept for WithBlockSize.
+	next := (d.pos + int64(len(d.blockTail))) / 4
+	r.reset(r.timeOf(next), next)
+	return nil
+}
+
+// Speed returns the playback speed set with SetSpeed.
+func (d *Decoder) Speed() float64 {
+	if d.resampler == nil {
+		return 1
+	}
+	return float64(d.resampler.speed) / speedScale
+}
@@ -0,0 +1,71 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSetSpeed(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, want := decodeFresh(t, data)
+
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	half := make([]byte, len(want)/2)
+	if _, err := io.ReadFull(d, half); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetSpeed(1.5); err != nil {
+		t.Fatal(err)
+	}
+	if d.Speed() != 1.5 {
+		t.Errorf("Speed() = %v, want 1.5", d.Speed())
+	}
+	at := d.Position()
+	buf := make([]byte, 44100*4)
+	if _, err := io.ReadFull(d, buf); err != nil {
+		t.Fatal(err)
+	}
+	// A second of output plays 1.5 seconds of the stream.
+	if got := d.Position() - at; got < 1499*time.Millisecond || got > 1501*time.Millisecond {
+		t.Errorf("Position() advanced by %v, want 1.5s", got)
+	}
+	rest, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remaining := int64(len(want)-len(half)) / 4
+	if got, want := int64(len(buf)+len(rest))/4, remaining*2/3; got < want-1 || got > want+1 {
+		t.Errorf("played %d samples at 1.5x, want %d", got, want)
+	}
+	if d.Position() != d.Duration() || d.Length() != int64(len(want)) {
+		t.Errorf("Position() = %v, Length() = %d at the end", d.Position(), d.Length())
+	}
+
+	// Back at speed 1, the samples are those of the stream.
+	if err := d.SetSpeed(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SeekToSample(1000); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want[4000:]) {
+		t.Error("PCM at speed 1 differs from the stream")
+	}
+
+	if err := d.SetSpeed(5); err == nil {
+		t.Error("SetSpeed(5) succeeded")
+	}
+}
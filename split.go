@@ -0,0 +1,123 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/llehouerou/go-mp3/id3v2"
+)
+
+// Split cuts the stream at points and writes the raw MP3 frames of each
+// segment to the writer returned by open for it, without decoding and
+// re-encoding them. Segment i, counted from 0, runs from point i-1 to point
+// i, the first one from the start of the stream and the last one to its
+// end.
+//
+// The points are moved to the nearest frame boundary, so that every frame
+// of the stream belongs to exactly one segment; points that fall on the
+// same boundary or at the ends of the stream are merged. As with CopyRange,
+// each segment also starts with the frames its first frame takes data from,
+// which are not counted in the audio of the segment. Split returns the
+// description of the segments written, and is typically given the times
+// returned by SplitEvery, ChapterStarts, SilenceReport.Midpoints or
+// BoundaryTimes. The writers are not closed.
+//
+// Split returns an error if the source is not io.Seeker. The position of
+// the decoder is left unchanged.
+func (d *Decoder) Split(points []time.Duration, open func(segment int) (io.Writer, error)) ([]RangeCopy, error) {
+	if d.length == invalidLength {
+		return nil, errors.New("mp3: Split requires a seekable source")
+	}
+	pos := d.source.pos
+	defer func() {
+		_, _ = d.source.Seek(pos, io.SeekStart)
+	}()
+	lowest := d.firstAudioFrame()
+	n := int64(len(d.frameOffsets))
+	bounds := []int64{lowest}
+	for _, p := range slices.Sorted(slices.Values(points)) {
+		if f := d.frameNear(p); f > bounds[len(bounds)-1] && f < n {
+			bounds = append(bounds, f)
+		}
+	}
+	bounds = append(bounds, n)
+
+	var segments []RangeCopy
+	for i := range len(bounds) - 1 {
+		if bounds[i] >= bounds[i+1] {
+			break // A stream without audio frames.
+		}
+		w, err := open(i)
+		if err != nil {
+			return segments, err
+		}
+		c, err := d.copyFrames(w, bounds[i], bounds[i+1], lowest)
+		segments = append(segments, c)
+		if err != nil {
+			return segments, err
+		}
+	}
+	return segments, nil
+}
+
+// frameNear returns the index of the frame starting at the frame boundary
+// nearest to time t.
+func (d *Decoder) frameNear(t time.Duration) int64 {
+	apos := max(d.posAt(max(t, 0))-d.priming, 0)
+	f := d.frameAt(apos)
+	end := d.length - d.priming
+	if f+1 < int64(len(d.frameOffsets)) {
+		end = d.frameOffsets[f+1]
+	}
+	if apos-d.frameOffsets[f] >= end-apos {
+		f++
+	}
+	return f
+}
+
+// SplitEvery returns the points that cut a stream of the given duration
+// into segments of interval, the last one being shorter, for Split.
+func SplitEvery(duration, interval time.Duration) []time.Duration {
+	if interval <= 0 {
+		return nil
+	}
+	var points []time.Duration
+	for t := interval; t < duration; t += interval {
+		points = append(points, t)
+	}
+	return points
+}
+
+// ChapterStarts returns the start times of chapters, such as those of
+// Decoder.Chapters, for Split.
+func ChapterStarts(chapters []id3v2.Chapter) []time.Duration {
+	points := make([]time.Duration, 0, len(chapters))
+	for _, c := range chapters {
+		points = append(points, c.Start)
+	}
+	return points
+}
+
+// Midpoints returns the middles of the silent regions of the report, for
+// Split.
+func (r *SilenceReport) Midpoints() []time.Duration {
+	points := make([]time.Duration, 0, len(r.Regions))
+	for _, s := range r.Regions {
+		points = append(points, s.Start+(s.End-s.Start)/2)
+	}
+	return points
+}
+
+// BoundaryTimes returns the times of the boundaries with at least the given
+// confidence, such as those of TrackBoundaries, for Split.
+func BoundaryTimes(boundaries []TrackBoundary, minConfidence float64) []time.Duration {
+	var points []time.Duration
+	for _, b := range boundaries {
+		if b.Confidence >= minConfidence {
+			points = append(points, b.Time)
+		}
+	}
+	return points
+}
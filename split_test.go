@@ -0,0 +1,83 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/llehouerou/go-mp3/id3v2"
+)
+
+func TestSplit(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := SplitEvery(d.Duration(), 2*time.Second)
+	// Unsorted, duplicated and out of range points are handled.
+	points = append(points, time.Second+time.Millisecond, time.Second, -time.Second, time.Hour)
+	var outs []*bytes.Buffer
+	segments, err := d.Split(points, func(i int) (io.Writer, error) {
+		if i != len(outs) {
+			t.Fatalf("segment %d opened after %d", i, len(outs))
+		}
+		outs = append(outs, &bytes.Buffer{})
+		return outs[i], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := len(SplitEvery(d.Duration(), 2*time.Second)) + 2; len(segments) != want {
+		t.Fatalf("got %d segments, want %d", len(segments), want)
+	}
+	frames := 0
+	end := time.Duration(1152) * time.Second / 44100 // The Xing header frame is left out.
+	for i, s := range segments {
+		if s.Start != end {
+			t.Errorf("segment %d starts at %v, want %v", i, s.Start, end)
+		}
+		end = s.End
+		frames += s.Frames - s.ReservoirFrames
+		if s.Bytes != int64(outs[i].Len()) {
+			t.Errorf("segment %d: %d bytes written, %d reported", i, outs[i].Len(), s.Bytes)
+		}
+		sd, _ := decodeFresh(t, outs[i].Bytes())
+		if sd.FrameCount() != s.Frames {
+			t.Errorf("segment %d has %d frames, want %d", i, sd.FrameCount(), s.Frames)
+		}
+	}
+	if end != d.Duration() || frames != d.FrameCount()-1 {
+		t.Errorf("segments end at %v with %d frames, want %v and %d", end, frames, d.Duration(), d.FrameCount()-1)
+	}
+	if d.SamplePosition() != 0 {
+		t.Errorf("Split moved the decoder to sample %d", d.SamplePosition())
+	}
+}
+
+func TestSplitPoints(t *testing.T) {
+	if got, want := SplitEvery(25*time.Second, 10*time.Second), []time.Duration{10 * time.Second, 20 * time.Second}; !slices.Equal(got, want) {
+		t.Errorf("SplitEvery = %v, want %v", got, want)
+	}
+	if got := SplitEvery(time.Minute, 0); got != nil {
+		t.Errorf("SplitEvery with a zero interval = %v", got)
+	}
+	chapters := []id3v2.Chapter{{Start: 0}, {Start: time.Minute}}
+	if got, want := ChapterStarts(chapters), []time.Duration{0, time.Minute}; !slices.Equal(got, want) {
+		t.Errorf("ChapterStarts = %v, want %v", got, want)
+	}
+	r := &SilenceReport{Regions: []SilentRegion{{Start: time.Second, End: 3 * time.Second}}}
+	if got, want := r.Midpoints(), []time.Duration{2 * time.Second}; !slices.Equal(got, want) {
+		t.Errorf("Midpoints = %v, want %v", got, want)
+	}
+	b := []TrackBoundary{{Time: time.Second, Confidence: 0.9}, {Time: 2 * time.Second, Confidence: 0.2}}
+	if got, want := BoundaryTimes(b, 0.5), []time.Duration{time.Second}; !slices.Equal(got, want) {
+		t.Errorf("BoundaryTimes = %v, want %v", got, want)
+	}
+}
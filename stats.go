@@ -0,0 +1,53 @@
+package mp3
+
+import (
+	"github.com/llehouerou/go-mp3/internal/frame"
+)
+
+// Stats holds statistics about the audio decoded so far by a Decoder.
+type Stats struct {
+	// StereoCorrelation is the correlation between the left and right
+	// channels, from -1 (opposite phase) through 0 (unrelated) to 1
+	// (identical, i.e. mono). It is 0 when nothing but silence was decoded.
+	StereoCorrelation float64
+
+	// StereoWidth is the share of the signal energy carried by the side
+	// (L-R) signal: 0 for mono, about 0.5 for unrelated channels and 1 for
+	// channels in opposite phase.
+	StereoWidth float64
+}
+
+// stereoStats accumulates the stereo image metrics of decoded PCM.
+type stereoStats struct {
+	phase     phaseAccumulator
+	mid, side float64
+}
+
+func (s *stereoStats) add(pcm *frame.PCM, n int) {
+	for i := range n {
+		l := float64(pcm[0][i])
+		r := float64(pcm[1][i])
+		s.phase.add(l, r)
+		m := l + r
+		d := l - r
+		s.mid += m * m
+		s.side += d * d
+	}
+}
+
+func (s *stereoStats) width() float64 {
+	total := s.mid + s.side
+	if total == 0 {
+		return 0
+	}
+	return s.side / total
+}
+
+// Stats returns statistics about the audio decoded so far. They cover every
+// decoded frame, including frames decoded to prepare a seek.
+func (d *Decoder) Stats() Stats {
+	return Stats{
+		StereoCorrelation: d.stereo.phase.correlation(),
+		StereoWidth:       d.stereo.width(),
+	}
+}
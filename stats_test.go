@@ -0,0 +1,58 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/internal/frame"
+)
+
+func TestStats_StereoMetrics(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	if _, err := io.ReadAll(d); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	s := d.Stats()
+	if s.StereoCorrelation <= 0 || s.StereoCorrelation >= 1 {
+		t.Errorf("StereoCorrelation = %v, want within (0, 1) for a stereo recording", s.StereoCorrelation)
+	}
+	if s.StereoWidth <= 0 || s.StereoWidth >= 0.5 {
+		t.Errorf("StereoWidth = %v, want within (0, 0.5) for correlated channels", s.StereoWidth)
+	}
+}
+
+func TestStereoStats_Extremes(t *testing.T) {
+	var pcm frame.PCM
+	for i := range 100 {
+		pcm[0][i] = float32(i%7) - 3
+		pcm[1][i] = pcm[0][i]
+	}
+	var mono stereoStats
+	mono.add(&pcm, 100)
+	if mono.phase.correlation() < 0.999 || mono.width() != 0 {
+		t.Errorf("mono: correlation %v width %v, want 1 and 0", mono.phase.correlation(), mono.width())
+	}
+
+	for i := range 100 {
+		pcm[1][i] = -pcm[0][i]
+	}
+	var inverted stereoStats
+	inverted.add(&pcm, 100)
+	if inverted.phase.correlation() > -0.999 || inverted.width() != 1 {
+		t.Errorf("inverted: correlation %v width %v, want -1 and 1", inverted.phase.correlation(), inverted.width())
+	}
+
+	var silent stereoStats
+	if silent.width() != 0 || silent.phase.correlation() != 0 {
+		t.Error("metrics of silence should be 0")
+	}
+}
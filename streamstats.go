@@ -0,0 +1,136 @@
+package mp3
+
+import (
+	"errors"
+
+	"github.com/llehouerou/go-mp3/frameheader"
+	"github.com/llehouerou/go-mp3/lameinfo"
+)
+
+// BitrateMode is the way the bitrate of a stream varies.
+type BitrateMode int
+
+const (
+	// BitrateCBR is a constant bitrate.
+	BitrateCBR BitrateMode = iota
+
+	// BitrateVBR is a variable bitrate, driven by a quality target.
+	BitrateVBR
+
+	// BitrateABR is an average bitrate: the bitrate varies around a target.
+	BitrateABR
+)
+
+// String returns "CBR", "VBR" or "ABR".
+func (m BitrateMode) String() string {
+	switch m {
+	case BitrateVBR:
+		return "VBR"
+	case BitrateABR:
+		return "ABR"
+	}
+	return "CBR"
+}
+
+// StreamStats describes the frames of a stream, as found by the scan made
+// when the decoder is created.
+type StreamStats struct {
+	// FrameCount is the number of audio frames, excluding a Xing or Info
+	// header frame.
+	FrameCount int
+
+	// MinBitrate, MaxBitrate and AverageBitrate are in bits per second. The
+	// average is the size of the audio frames over their duration.
+	MinBitrate     int
+	MaxBitrate     int
+	AverageBitrate int
+
+	// BitrateHistogram maps each bitrate in bits per second to the number
+	// of frames using it.
+	BitrateHistogram map[int]int
+
+	// ModeHistogram maps each channel mode to the number of frames using
+	// it.
+	ModeHistogram map[frameheader.Mode]int
+
+	// MSStereoFrames and IntensityStereoFrames count the joint stereo
+	// frames using middle/side and intensity stereo. A frame can use both.
+	MSStereoFrames        int
+	IntensityStereoFrames int
+
+	// SkippedBytes is the number of bytes between frames that are not part
+	// of any frame, such as garbage or tags in the middle of the stream.
+	SkippedBytes int64
+
+	// Mode tells whether the stream is CBR, VBR or ABR. It comes from the
+	// LAME tag when there is one, and otherwise from the bitrates used.
+	Mode BitrateMode
+}
+
+// StreamStats returns statistics about the frames of the stream. It returns
+// an error if the source is not io.Seeker.
+func (d *Decoder) StreamStats() (StreamStats, error) {
+	if d.length == invalidLength {
+		return StreamStats{}, errors.New("mp3: stream statistics require a seekable source")
+	}
+
+	info := d.xing
+	headers := d.frameHeaders
+	if info != nil {
+		headers = headers[1:]
+	}
+
+	s := StreamStats{
+		FrameCount:       len(headers),
+		BitrateHistogram: map[int]int{},
+		ModeHistogram:    map[frameheader.Mode]int{},
+		SkippedBytes:     d.skippedBytes,
+	}
+	var bytes, samples int64
+	for i, h := range headers {
+		b := h.Bitrate()
+		if i == 0 {
+			s.MinBitrate, s.MaxBitrate = b, b
+		}
+		s.MinBitrate = min(s.MinBitrate, b)
+		s.MaxBitrate = max(s.MaxBitrate, b)
+		s.BitrateHistogram[b]++
+		s.ModeHistogram[frameheader.Mode(h.Mode())]++
+		if h.UseMSStereo() {
+			s.MSStereoFrames++
+		}
+		if h.UseIntensityStereo() {
+			s.IntensityStereoFrames++
+		}
+		size, err := h.FrameSize()
+		if err != nil {
+			return StreamStats{}, err
+		}
+		bytes += int64(size)
+		samples += int64(h.SamplesPerFrame())
+	}
+	if samples > 0 {
+		rate := int64(d.sampleRate)
+		if d.halfRate {
+			rate *= 2 // Samples are counted at the stream rate.
+		}
+		s.AverageBitrate = int(bytes * 8 * rate / samples)
+	}
+
+	s.Mode = BitrateCBR
+	if len(s.BitrateHistogram) > 1 {
+		s.Mode = BitrateVBR
+	}
+	if info != nil && info.HasLAMEInfo() {
+		switch info.VBRMethod {
+		case lameinfo.VBRMethodCBR, lameinfo.VBRMethodCBRTwoPass:
+			s.Mode = BitrateCBR
+		case lameinfo.VBRMethodABR, lameinfo.VBRMethodABRTwoPass:
+			s.Mode = BitrateABR
+		case lameinfo.VBRMethodUnknown:
+		default:
+			s.Mode = BitrateVBR
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,154 @@
+package mp3
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/frameheader"
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+func TestStreamStats_VBRFile(t *testing.T) {
+	f, err := os.Open("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	d, err := NewDecoder(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := d.StreamStats()
+	if err != nil {
+		t.Fatalf("StreamStats failed: %v", err)
+	}
+	if s.Mode != BitrateVBR {
+		t.Errorf("Mode = %v, want VBR", s.Mode)
+	}
+	if s.FrameCount != len(d.frameStarts)-1 {
+		t.Errorf("FrameCount = %d, want %d without the Xing frame", s.FrameCount, len(d.frameStarts)-1)
+	}
+	total := 0
+	for _, n := range s.BitrateHistogram {
+		total += n
+	}
+	if total != s.FrameCount || len(s.BitrateHistogram) < 2 {
+		t.Errorf("histogram %v covers %d frames, want %d over several bitrates", s.BitrateHistogram, total, s.FrameCount)
+	}
+	if s.MinBitrate >= s.AverageBitrate || s.AverageBitrate >= s.MaxBitrate {
+		t.Errorf("bitrates min %d, average %d, max %d are not ordered", s.MinBitrate, s.AverageBitrate, s.MaxBitrate)
+	}
+	if s.SkippedBytes != 0 {
+		t.Errorf("SkippedBytes = %d, want 0", s.SkippedBytes)
+	}
+}
+
+func TestStreamStats_CBRWithXing(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{Frames: 20, Xing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := d.StreamStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Mode != BitrateCBR || s.FrameCount != 20 {
+		t.Errorf("Mode %v, FrameCount %d, want CBR and 20", s.Mode, s.FrameCount)
+	}
+	if s.MinBitrate != 128000 || s.MaxBitrate != 128000 || s.BitrateHistogram[128000] != 20 {
+		t.Errorf("stats = %+v, want 20 frames at 128 kbps", s)
+	}
+	// Padding makes the average bitrate exact.
+	if s.AverageBitrate < 127900 || s.AverageBitrate > 128100 {
+		t.Errorf("AverageBitrate = %d, want about 128000", s.AverageBitrate)
+	}
+}
+
+func TestStreamStats_MixedBitratesAndJunk(t *testing.T) {
+	var data []byte
+	for i, bitrate := range []int{128, 192, 128, 320} {
+		frame, err := testsupport.Frame(44100, bitrate, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 2 {
+			data = append(data, make([]byte, 37)...)
+		}
+		data = append(data, frame...)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := d.StreamStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Mode != BitrateVBR || s.FrameCount != 4 || s.MinBitrate != 128000 || s.MaxBitrate != 320000 {
+		t.Errorf("stats = %+v, want 4 VBR frames from 128 to 320 kbps", s)
+	}
+	if s.BitrateHistogram[128000] != 2 {
+		t.Errorf("histogram = %v, want 2 frames at 128 kbps", s.BitrateHistogram)
+	}
+	if s.SkippedBytes != 37 {
+		t.Errorf("SkippedBytes = %d, want 37", s.SkippedBytes)
+	}
+
+	ns, err := NewDecoder(nonSeekable{bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ns.StreamStats(); err == nil {
+		t.Error("StreamStats succeeded on a non-seekable source")
+	}
+}
+
+func TestStreamStats_StereoModes(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := d.StreamStats()
+	if err != nil {
+		t.Fatalf("StreamStats failed: %v", err)
+	}
+	total := 0
+	for _, n := range s.ModeHistogram {
+		total += n
+	}
+	if total != s.FrameCount {
+		t.Errorf("mode histogram %v covers %d frames, want %d", s.ModeHistogram, total, s.FrameCount)
+	}
+	joint := s.ModeHistogram[frameheader.ModeJointStereo]
+	if s.MSStereoFrames == 0 || s.MSStereoFrames > joint || s.IntensityStereoFrames > joint {
+		t.Errorf("%d M/S and %d intensity stereo frames, want some M/S frames among the %d joint stereo frames",
+			s.MSStereoFrames, s.IntensityStereoFrames, joint)
+	}
+
+	data, err = testsupport.Generate(testsupport.Options{Frames: 20, Mono: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err = NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err = d.StreamStats()
+	if err != nil {
+		t.Fatalf("StreamStats failed: %v", err)
+	}
+	if s.ModeHistogram[frameheader.ModeSingleChannel] != 20 || s.MSStereoFrames != 0 || s.IntensityStereoFrames != 0 {
+		t.Errorf("mono stream: modes %v, %d M/S and %d intensity stereo frames, want 20 mono frames",
+			s.ModeHistogram, s.MSStereoFrames, s.IntensityStereoFrames)
+	}
+}
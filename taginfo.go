@@ -0,0 +1,88 @@
+package mp3
+
+import (
+	"cmp"
+	"slices"
+)
+
+// A RegionKind identifies what a Region of the stream holds. The kinds of
+// tags have the values of the matching TagKind.
+type RegionKind int
+
+const (
+	// RegionID3v2, RegionID3v1 and RegionAPE are tags.
+	RegionID3v2 RegionKind = RegionKind(TagID3v2)
+	RegionID3v1 RegionKind = RegionKind(TagID3v1)
+	RegionAPE   RegionKind = RegionKind(TagAPE)
+
+	// RegionJunk is data in which no frame or tag was found, such as
+	// garbage between frames or after the last one.
+	RegionJunk RegionKind = iota
+)
+
+// String returns the name of the region kind, such as "APE" or "junk".
+func (k RegionKind) String() string {
+	if k == RegionJunk {
+		return "junk"
+	}
+	return TagKind(k).String()
+}
+
+// A Region is a range of bytes of the stream that holds no audio.
+type Region struct {
+	Kind   RegionKind
+	Offset int64
+	Size   int64
+}
+
+// TagInfo describes where the audio lies in the stream, for tag editors and
+// repair tools.
+type TagInfo struct {
+	// AudioStart is the offset of the first frame, and AudioEnd the offset
+	// past the last one, or -1 if the source is not io.Seeker.
+	AudioStart int64
+	AudioEnd   int64
+
+	// Regions lists the tags and junk found in the stream, in order. Only
+	// the regions before the first frame are found if the source is not
+	// io.Seeker.
+	Regions []Region
+}
+
+// TagInfo returns the byte ranges of the audio and of the regions of the
+// stream that hold no audio, found while reading its start and indexing its
+// frames.
+func (d *Decoder) TagInfo() TagInfo {
+	info := TagInfo{
+		AudioStart: d.audioStart,
+		AudioEnd:   d.framesEnd,
+		Regions:    slices.Clone(d.regions),
+	}
+	if d.length == invalidLength {
+		info.AudioEnd = -1
+	}
+	return info
+}
+
+// addRegion records a region of size bytes at offset.
+func (d *Decoder) addRegion(kind RegionKind, offset, size int64) {
+	if size > 0 {
+		d.regions = append(d.regions, Region{Kind: kind, Offset: offset, Size: size})
+	}
+}
+
+// sortRegions orders the regions by offset.
+func (d *Decoder) sortRegions() {
+	slices.SortFunc(d.regions, func(a, b Region) int {
+		return cmp.Compare(a.Offset, b.Offset)
+	})
+}
+
+// onLeadingTag records a tag read at the start of the stream and passes it
+// to the TagFunc.
+func (d *Decoder) onLeadingTag(kind TagKind, offset int64, data []byte) {
+	d.addRegion(RegionKind(kind), offset, int64(len(data)))
+	if d.tagFunc != nil {
+		d.tagFunc(kind, offset, data)
+	}
+}
@@ -0,0 +1,61 @@
+package mp3
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+func TestDecoder_TagInfo(t *testing.T) {
+	audio, err := testsupport.Generate(testsupport.Options{Tags: map[string]string{"TIT2": "Title"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := testsupport.Generate(testsupport.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag := audio[:len(audio)-len(plain)]
+	ape := slices.Concat(createAPETagHeader(32), createAPETagHeader(32))
+	junk := bytes.Repeat([]byte{0x55}, 100)
+	pd, err := NewDecoder(bytes.NewReader(plain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	split := pd.frameStarts[5]
+	stream := slices.Concat(tag, junk, plain[:split], junk, plain[split:], junk, ape, createID3v1Tag())
+
+	d, err := NewDecoder(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatal(err)
+	}
+	audioStart := int64(len(tag) + len(junk))
+	audioEnd := audioStart + int64(len(plain)+len(junk))
+	want := TagInfo{
+		AudioStart: audioStart,
+		AudioEnd:   audioEnd,
+		Regions: []Region{
+			{RegionID3v2, 0, int64(len(tag))},
+			{RegionJunk, int64(len(tag)), 100},
+			{RegionJunk, audioStart + split, 100},
+			{RegionJunk, audioEnd, 100},
+			{RegionAPE, audioEnd + 100, int64(len(ape))},
+			{RegionID3v1, int64(len(stream) - 128), 128},
+		},
+	}
+	got := d.TagInfo()
+	if got.AudioStart != want.AudioStart || got.AudioEnd != want.AudioEnd || !slices.Equal(got.Regions, want.Regions) {
+		t.Errorf("TagInfo() = %+v\nwant %+v", got, want)
+	}
+
+	d, err = NewDecoder(&nonSeekableReader{r: bytes.NewReader(stream)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = d.TagInfo()
+	if got.AudioStart != want.AudioStart || got.AudioEnd != -1 || !slices.Equal(got.Regions, want.Regions[:2]) {
+		t.Errorf("TagInfo() of a non-seekable stream = %+v", got)
+	}
+}
@@ -0,0 +1,118 @@
+package mp3
+
+import "io"
+
+// A TagKind identifies the format of a tag passed to a TagFunc.
+type TagKind int
+
+const (
+	// TagID3v2 is an ID3v2 tag, starting with its 10-byte header.
+	TagID3v2 TagKind = iota
+
+	// TagID3v1 is a 128-byte ID3v1 tag, starting with "TAG".
+	TagID3v1
+
+	// TagAPE is an APEv1 or APEv2 tag, ending with its 32-byte footer.
+	TagAPE
+)
+
+// String returns the name of the tag format, such as "ID3v2".
+func (k TagKind) String() string {
+	switch k {
+	case TagID3v2:
+		return "ID3v2"
+	case TagID3v1:
+		return "ID3v1"
+	case TagAPE:
+		return "APE"
+	}
+	return "unknown"
+}
+
+// A TagFunc receives the raw bytes of a tag skipped by the decoder and their
+// offset in the stream. The function may keep data.
+type TagFunc func(kind TagKind, offset int64, data []byte)
+
+// WithTagFunc makes NewDecoder pass the tags it skips to fn, in stream
+// order, so that applications can parse them with their own tag library
+// without reading the file a second time. The tags at the start of the
+// stream are passed as they are read. The tags at its end, an appended
+// ID3v2 tag, an APE tag and an ID3v1 tag, are found for io.Seeker sources
+// only, while the frames are indexed.
+func WithTagFunc(fn TagFunc) Option {
+	return func(c *config) {
+		c.tagFunc = fn
+	}
+}
+
+// A tagSpan is the byte range of a tag.
+type tagSpan struct {
+	kind       TagKind
+	start, end int64
+}
+
+// trailingTags returns the tags at the end of the stream, in order, and the
+// size of the stream. An ID3v2 tag appended to the stream starts at audioEnd
+// if it is not -1. The source position is left undefined.
+func (s *source) trailingTags(audioEnd int64) ([]tagSpan, int64, error) {
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, 0, err
+	}
+	apeStart, err := s.trailingTagsStart()
+	if err != nil {
+		return nil, 0, err
+	}
+	id3v1Start := end
+	if end >= 128 {
+		head, err := s.readAt(end-128, 3)
+		if err != nil {
+			return nil, 0, err
+		}
+		if string(head) == "TAG" {
+			id3v1Start = end - 128
+		}
+	}
+	if audioEnd >= 0 {
+		// The appended tag runs up to the ID3v1 tag, since it does not
+		// coexist with an APE tag.
+		apeStart = id3v1Start
+	}
+
+	var spans []tagSpan
+	for _, t := range []tagSpan{
+		{TagID3v2, audioEnd, apeStart},
+		{TagAPE, apeStart, id3v1Start},
+		{TagID3v1, id3v1Start, end},
+	} {
+		if t.start >= 0 && t.start < t.end {
+			spans = append(spans, t)
+		}
+	}
+	return spans, end, nil
+}
+
+// reportTags passes the tags of spans to fn. The source position is left
+// undefined.
+func (s *source) reportTags(fn TagFunc, spans []tagSpan) error {
+	for _, t := range spans {
+		data, err := s.readAt(t.start, int(t.end-t.start))
+		if err != nil {
+			return err
+		}
+		fn(t.kind, t.start, data)
+	}
+	return nil
+}
+
+// readAt reads n bytes at offset off.
+func (s *source) readAt(off int64, n int) ([]byte, error) {
+	if _, err := s.Seek(off, io.SeekStart); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	if _, err := s.ReadFull(buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
@@ -0,0 +1,67 @@
+package mp3
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+type seenTag struct {
+	kind   TagKind
+	offset int64
+	data   []byte
+}
+
+func TestWithTagFunc(t *testing.T) {
+	audio, err := testsupport.Generate(testsupport.Options{Tags: map[string]string{"TIT2": "Title"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := testsupport.Generate(testsupport.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id3v2 := audio[:len(audio)-len(plain)]
+	// An empty APE tag: a header and a footer, which are alike.
+	ape := slices.Concat(createAPETagHeader(32), createAPETagHeader(32))
+	id3v1 := createID3v1Tag()
+	stream := slices.Concat(id3v1, audio, ape, id3v1)
+
+	want := []seenTag{
+		{TagID3v1, 0, id3v1},
+		{TagID3v2, 128, id3v2},
+		{TagAPE, int64(len(id3v1) + len(audio)), ape},
+		{TagID3v1, int64(len(stream) - 128), id3v1},
+	}
+	var got []seenTag
+	fn := func(kind TagKind, offset int64, data []byte) {
+		got = append(got, seenTag{kind, offset, data})
+	}
+	d, err := NewDecoder(bytes.NewReader(stream), WithTagFunc(fn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d tags, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].kind != want[i].kind || got[i].offset != want[i].offset || !bytes.Equal(got[i].data, want[i].data) {
+			t.Errorf("tag %d: got %v at %d (%d bytes), want %v at %d (%d bytes)", i,
+				got[i].kind, got[i].offset, len(got[i].data), want[i].kind, want[i].offset, len(want[i].data))
+		}
+	}
+	if d.Metadata() == nil {
+		t.Error("the ID3v2 tag was not parsed along")
+	}
+
+	// The leading tags only are found in a non-seekable stream.
+	got = nil
+	if _, err := NewDecoder(&nonSeekableReader{r: bytes.NewReader(stream)}, WithTagFunc(fn)); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d tags in a non-seekable stream, want 2", len(got))
+	}
+}
@@ -0,0 +1,309 @@
+// Package testsupport synthesizes valid MP3 streams for tests, so that
+// fixtures can be generated in code instead of being shipped as binary files.
+//
+// The generated frames are silent Layer III frames: their side information
+// and main data are all zeros, which every decoder decodes as digital
+// silence. Streams can be given ID3v2 and ID3v1 tags and a Xing/Info header
+// with a LAME tag.
+package testsupport
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Options describes a stream generated by Generate. The zero value is
+// valid and produces 10 frames of 128 kbps, 44100 Hz joint stereo audio.
+type Options struct {
+	// SampleRate is the sample rate in Hz. It selects the MPEG version:
+	// 32000, 44100 and 48000 are MPEG-1, 16000, 22050 and 24000 are
+	// MPEG-2, and 8000, 11025 and 12000 are MPEG-2.5. Defaults to 44100.
+	// The mp3 package does not decode MPEG-2.5; such streams are meant
+	// for testing other readers or error paths.
+	SampleRate int
+
+	// Bitrate is the bitrate in kbps. It must be a bitrate of the MPEG
+	// version selected by SampleRate. Defaults to 128.
+	Bitrate int
+
+	// Mono selects single channel frames instead of joint stereo.
+	Mono bool
+
+	// Frames is the number of audio frames. Defaults to 10.
+	Frames int
+
+	// Xing adds an "Info" header frame, as written by LAME for CBR files,
+	// before the audio frames. It holds the frame count, the byte count and
+	// a seek table.
+	Xing bool
+
+	// EncoderDelay and EncoderPadding, if not both zero, add a LAME tag with
+	// these values to the Xing header. They imply Xing.
+	EncoderDelay   int
+	EncoderPadding int
+
+	// Tags, if not empty, adds an ID3v2.4 tag before the audio holding a
+	// text frame for each entry, such as "TIT2": "Title".
+	Tags map[string]string
+
+	// ID3v1 appends an ID3v1 tag after the audio.
+	ID3v1 bool
+}
+
+var (
+	// ErrSampleRate is returned for unsupported sample rates.
+	ErrSampleRate = errors.New("testsupport: unsupported sample rate")
+
+	// ErrBitrate is returned for bitrates not available for the sample rate.
+	ErrBitrate = errors.New("testsupport: unsupported bitrate")
+)
+
+// MPEG version bits of the frame header.
+const (
+	versionMPEG25 = 0
+	versionMPEG2  = 2
+	versionMPEG1  = 3
+)
+
+var sampleRates = map[int]struct{ version, index uint32 }{
+	44100: {versionMPEG1, 0}, 48000: {versionMPEG1, 1}, 32000: {versionMPEG1, 2},
+	22050: {versionMPEG2, 0}, 24000: {versionMPEG2, 1}, 16000: {versionMPEG2, 2},
+	11025: {versionMPEG25, 0}, 12000: {versionMPEG25, 1}, 8000: {versionMPEG25, 2},
+}
+
+var (
+	bitratesMPEG1 = []int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320}
+	bitratesMPEG2 = []int{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160}
+)
+
+// format holds the frame parameters derived from Options.
+type format struct {
+	version      uint32
+	rateIndex    uint32
+	bitrateIndex uint32
+	sampleRate   int
+	bitrate      int
+	mono         bool
+}
+
+func newFormat(sampleRate, bitrate int, mono bool) (format, error) {
+	if sampleRate == 0 {
+		sampleRate = 44100
+	}
+	if bitrate == 0 {
+		bitrate = 128
+	}
+	r, ok := sampleRates[sampleRate]
+	if !ok {
+		return format{}, fmt.Errorf("%w: %d", ErrSampleRate, sampleRate)
+	}
+	bitrates := bitratesMPEG2
+	if r.version == versionMPEG1 {
+		bitrates = bitratesMPEG1
+	}
+	for i, b := range bitrates {
+		if i > 0 && b == bitrate {
+			return format{
+				version:      r.version,
+				rateIndex:    r.index,
+				bitrateIndex: uint32(i), //nolint:gosec // index of a 15-entry table
+				sampleRate:   sampleRate,
+				bitrate:      bitrate,
+				mono:         mono,
+			}, nil
+		}
+	}
+	return format{}, fmt.Errorf("%w: %d kbps at %d Hz", ErrBitrate, bitrate, sampleRate)
+}
+
+// slotsPerFrame returns the frame size in bytes divided by the bitrate over
+// the sample rate.
+func (f format) slotsPerFrame() int {
+	if f.version == versionMPEG1 {
+		return 144
+	}
+	return 72
+}
+
+// frameSize returns the size of a frame in bytes.
+func (f format) frameSize(padding bool) int {
+	n := f.slotsPerFrame() * f.bitrate * 1000 / f.sampleRate
+	if padding {
+		n++
+	}
+	return n
+}
+
+// sideInfoSize returns the size of the side information in bytes.
+func (f format) sideInfoSize() int {
+	switch {
+	case f.version == versionMPEG1 && f.mono:
+		return 17
+	case f.version == versionMPEG1:
+		return 32
+	case f.mono:
+		return 9
+	default:
+		return 17
+	}
+}
+
+// header returns the 4-byte frame header.
+func (f format) header(padding bool) uint32 {
+	h := uint32(0xffe00000)
+	h |= f.version << 19
+	h |= 1 << 17 // Layer III
+	h |= 1 << 16 // No CRC
+	h |= f.bitrateIndex << 12
+	h |= f.rateIndex << 10
+	if padding {
+		h |= 1 << 9
+	}
+	if f.mono {
+		h |= 3 << 6
+	} else {
+		h |= 1 << 6 // Joint stereo
+	}
+	h |= 1 << 2 // Original
+	return h
+}
+
+func (f format) frame(padding bool) []byte {
+	b := make([]byte, f.frameSize(padding))
+	binary.BigEndian.PutUint32(b, f.header(padding))
+	return b
+}
+
+// Frame returns a single silent frame. A padded frame is one byte longer,
+// as encoders use to keep the average bitrate exact.
+func Frame(sampleRate, bitrate int, mono, padding bool) ([]byte, error) {
+	f, err := newFormat(sampleRate, bitrate, mono)
+	if err != nil {
+		return nil, err
+	}
+	return f.frame(padding), nil
+}
+
+// SamplesPerFrame returns the number of samples per channel in a frame at
+// the given sample rate: 1152 for MPEG-1 and 576 for MPEG-2 and 2.5.
+func SamplesPerFrame(sampleRate int) int {
+	if r, ok := sampleRates[sampleRate]; ok && r.version != versionMPEG1 {
+		return 576
+	}
+	return 1152
+}
+
+// Generate returns a stream described by o.
+func Generate(o Options) ([]byte, error) {
+	f, err := newFormat(o.SampleRate, o.Bitrate, o.Mono)
+	if err != nil {
+		return nil, err
+	}
+	frames := o.Frames
+	if frames == 0 {
+		frames = 10
+	}
+
+	var audio []byte
+	// Pad frames like encoders do, so that the average frame size matches
+	// the bitrate exactly.
+	rem := f.slotsPerFrame() * f.bitrate * 1000 % f.sampleRate
+	acc := 0
+	for range frames {
+		acc += rem
+		padding := acc >= f.sampleRate
+		if padding {
+			acc -= f.sampleRate
+		}
+		audio = append(audio, f.frame(padding)...)
+	}
+
+	var b []byte
+	if len(o.Tags) > 0 {
+		b = append(b, ID3v2(o.Tags)...)
+	}
+	if o.Xing || o.EncoderDelay != 0 || o.EncoderPadding != 0 {
+		b = append(b, f.xingFrame(o, frames, len(audio))...)
+	}
+	b = append(b, audio...)
+	if o.ID3v1 {
+		b = append(b, ID3v1()...)
+	}
+	return b, nil
+}
+
+// xingFrame returns an Info header frame for audio of the given number of
+// frames and bytes.
+func (f format) xingFrame(o Options, frames, audioBytes int) []byte {
+	// Header, side information, tag, flags, counts, TOC and LAME tag.
+	need := 4 + f.sideInfoSize() + 4 + 4 + 8 + 100 + 36
+	bitrates := bitratesMPEG2
+	if f.version == versionMPEG1 {
+		bitrates = bitratesMPEG1
+	}
+	// Like LAME, use a higher bitrate if the frame is too small to hold the
+	// header.
+	for f.frameSize(false) < need && int(f.bitrateIndex) < len(bitrates)-1 {
+		f.bitrateIndex++
+		f.bitrate = bitrates[f.bitrateIndex]
+	}
+	b := f.frame(false)
+	pos := 4 + f.sideInfoSize()
+	copy(b[pos:], "Info")
+	pos += 4
+	// Frame count, byte count and TOC.
+	binary.BigEndian.PutUint32(b[pos:], 0x7)
+	pos += 4
+	binary.BigEndian.PutUint32(b[pos:], uint32(frames))              //nolint:gosec // test stream sizes fit
+	binary.BigEndian.PutUint32(b[pos+4:], uint32(len(b)+audioBytes)) //nolint:gosec // test stream sizes fit
+	pos += 8
+	for i := range 100 {
+		b[pos+i] = byte(i * 256 / 100)
+	}
+	pos += 100
+	if o.EncoderDelay == 0 && o.EncoderPadding == 0 {
+		return b
+	}
+	copy(b[pos:], "LAME3.100")
+	// Revision, lowpass, peak, replay gains, flags and ABR bitrate.
+	pos += 9 + 12
+	b[pos] = byte(o.EncoderDelay >> 4)
+	b[pos+1] = byte(o.EncoderDelay<<4) | byte(o.EncoderPadding>>8&0x0f)
+	b[pos+2] = byte(o.EncoderPadding)
+	return b
+}
+
+// ID3v2 returns an ID3v2.4 tag holding a UTF-8 text frame for each entry of
+// frames, in sorted order of frame IDs.
+func ID3v2(frames map[string]string) []byte {
+	ids := make([]string, 0, len(frames))
+	for id := range frames {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	var body []byte
+	for _, id := range ids {
+		data := append([]byte{3}, frames[id]...)
+		body = append(body, id...)
+		body = append(body, syncsafe(len(data))...)
+		body = append(body, 0, 0)
+		body = append(body, data...)
+	}
+	b := []byte{'I', 'D', '3', 4, 0, 0}
+	b = append(b, syncsafe(len(body))...)
+	return append(b, body...)
+}
+
+// ID3v1 returns an empty ID3v1 tag.
+func ID3v1() []byte {
+	b := make([]byte, 128)
+	copy(b, "TAG")
+	return b
+}
+
+func syncsafe(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7f), byte(n >> 14 & 0x7f), byte(n >> 7 & 0x7f), byte(n & 0x7f)}
+}
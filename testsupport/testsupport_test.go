@@ -0,0 +1,120 @@
+package testsupport_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/llehouerou/go-mp3"
+	"github.com/llehouerou/go-mp3/lameinfo"
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+func TestGenerate_Decodes(t *testing.T) {
+	tests := []struct {
+		sampleRate int
+		bitrate    int
+		mono       bool
+	}{
+		{44100, 128, false},
+		{48000, 320, false},
+		{32000, 32, true},
+		{22050, 64, false},
+		{24000, 8, true},
+	}
+	for _, tt := range tests {
+		data, err := testsupport.Generate(testsupport.Options{
+			SampleRate: tt.sampleRate,
+			Bitrate:    tt.bitrate,
+			Mono:       tt.mono,
+			Frames:     20,
+			Xing:       true,
+			Tags:       map[string]string{"TIT2": "Generated"},
+			ID3v1:      true,
+		})
+		if err != nil {
+			t.Fatalf("%d Hz %d kbps: Generate failed: %v", tt.sampleRate, tt.bitrate, err)
+		}
+		d, err := mp3.NewDecoder(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%d Hz %d kbps: NewDecoder failed: %v", tt.sampleRate, tt.bitrate, err)
+		}
+		if d.SampleRate() != tt.sampleRate {
+			t.Errorf("%d Hz: SampleRate() = %d", tt.sampleRate, d.SampleRate())
+		}
+		if d.Metadata() == nil || d.Metadata().Title() != "Generated" {
+			t.Errorf("%d Hz %d kbps: missing ID3v2 title", tt.sampleRate, tt.bitrate)
+		}
+		pcm, err := io.ReadAll(d)
+		if err != nil {
+			t.Fatalf("%d Hz %d kbps: ReadAll failed: %v", tt.sampleRate, tt.bitrate, err)
+		}
+		// The Info frame decodes as a frame of silence too.
+		want := 21 * testsupport.SamplesPerFrame(tt.sampleRate) * 4
+		if len(pcm) != want {
+			t.Errorf("%d Hz %d kbps: decoded %d bytes, want %d", tt.sampleRate, tt.bitrate, len(pcm), want)
+		}
+		if !bytes.Equal(pcm, make([]byte, len(pcm))) {
+			t.Errorf("%d Hz %d kbps: output is not silent", tt.sampleRate, tt.bitrate)
+		}
+	}
+}
+
+func TestGenerate_MPEG25(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{SampleRate: 8000, Bitrate: 8, Xing: true})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if _, err := lameinfo.ParseFromReader(bytes.NewReader(data)); err != nil {
+		t.Errorf("ParseFromReader failed: %v", err)
+	}
+	if _, err := mp3.NewDecoder(bytes.NewReader(data)); err == nil {
+		t.Error("NewDecoder should reject MPEG-2.5 streams")
+	}
+}
+
+func TestGenerate_LAMETag(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{
+		Frames:         50,
+		EncoderDelay:   576,
+		EncoderPadding: 1234,
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	info, err := lameinfo.ParseFromReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseFromReader failed: %v", err)
+	}
+	if info.IsXing || !info.HasTOC() || info.FrameCount != 50 {
+		t.Errorf("unexpected Info header: %+v", info)
+	}
+	if int(info.ByteCount) != len(data) {
+		t.Errorf("ByteCount = %d, want %d", info.ByteCount, len(data))
+	}
+	if info.EncoderDelay != 576 || info.EncoderPadding != 1234 {
+		t.Errorf("delay/padding = %d/%d, want 576/1234", info.EncoderDelay, info.EncoderPadding)
+	}
+}
+
+func TestGenerate_AverageBitrate(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{Frames: 1000})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	// 1000 frames of 1152 samples at 128 kbps.
+	want := 1000 * 1152 * 128000 / 8 / 44100
+	if len(data) < want-1 || len(data) > want+1 {
+		t.Errorf("stream size = %d, want about %d", len(data), want)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	if _, err := testsupport.Generate(testsupport.Options{SampleRate: 44000}); !errors.Is(err, testsupport.ErrSampleRate) {
+		t.Errorf("unsupported sample rate: err = %v", err)
+	}
+	if _, err := testsupport.Generate(testsupport.Options{SampleRate: 22050, Bitrate: 320}); !errors.Is(err, testsupport.ErrBitrate) {
+		t.Errorf("unsupported bitrate: err = %v", err)
+	}
+}
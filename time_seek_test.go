@@ -7,6 +7,8 @@ import (
 	"os"
 	"testing"
 	"time"
+
+	"github.com/llehouerou/go-mp3/testsupport"
 )
 
 // Test for Unread prepend semantics (issue #2)
@@ -209,6 +211,46 @@ func TestPosition_AfterSeek(t *testing.T) {
 	}
 }
 
+func TestPosition_AfterUnalignedSeek(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	_, pcm := decodeFresh(t, data)
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+
+	pos, err := d.Seek(40003, io.SeekStart)
+	if err != nil || pos != 40000 {
+		t.Fatalf("Seek = %d, %v, want 40000", pos, err)
+	}
+	if got := d.SamplePosition(); got != 10000 {
+		t.Errorf("SamplePosition() = %d, want 10000", got)
+	}
+	want := d.timeAt(40000)
+	if got := d.Position(); got != want {
+		t.Errorf("Position() = %v, want %v", got, want)
+	}
+	buf := make([]byte, 6)
+	if _, err := io.ReadFull(d, buf); err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	if !bytes.Equal(buf, pcm[40000:40006]) {
+		t.Errorf("read %v, want %v", buf, pcm[40000:40006])
+	}
+
+	// Within a sample, the position is that of the sample.
+	if got, want := d.Position(), d.timeAt(40004); got != want {
+		t.Errorf("Position() within a sample = %v, want %v", got, want)
+	}
+	pos, err = d.Seek(1, io.SeekCurrent)
+	if err != nil || pos != 40004 {
+		t.Errorf("relative Seek = %d, %v, want 40004", pos, err)
+	}
+}
+
 func TestPosition_NonSeekable(t *testing.T) {
 	// Even for non-seekable streams, Position() should track read progress
 	data, err := os.ReadFile("example/classic.mp3")
@@ -238,6 +280,10 @@ func TestPosition_NonSeekable(t *testing.T) {
 	if pos < 900*time.Millisecond || pos > 1100*time.Millisecond {
 		t.Errorf("Position() after 1s read = %v, expected ~1s", pos)
 	}
+
+	if _, err := d.Seek(0, io.SeekStart); err == nil {
+		t.Error("Seek on a non-seekable source succeeded")
+	}
 }
 
 // Tests for SeekToTime()
@@ -1309,3 +1355,90 @@ func TestSeek_SeekEndNegativeBeyondStartShouldNotPanic(t *testing.T) {
 		}
 	}
 }
+
+// mixedRateStream returns three MPEG-1 frames at 44100 Hz, three MPEG-2
+// frames at 22050 Hz and two MPEG-1 frames at 44100 Hz. MPEG-1 frames hold
+// 1152 samples and MPEG-2 frames 576.
+func mixedRateStream(t *testing.T) []byte {
+	t.Helper()
+	var data []byte
+	for _, rate := range []int{44100, 44100, 44100, 22050, 22050, 22050, 44100, 44100} {
+		b, err := testsupport.Frame(rate, 64, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = append(data, b...)
+	}
+	return data
+}
+
+func TestSeek_MixedFrameSizes(t *testing.T) {
+	d, err := NewDecoder(bytes.NewReader(mixedRateStream(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(5*1152+3*576) * 4; d.Length() != want {
+		t.Fatalf("Length() = %d, want %d", d.Length(), want)
+	}
+	for _, tt := range []struct {
+		frame  int
+		sample int64
+	}{
+		{1, 1152},
+		{4, 3*1152 + 576},
+		{5, 3*1152 + 2*576},
+		{7, 4*1152 + 3*576},
+	} {
+		pos := (tt.sample + 100) * 4
+		if _, err := d.Seek(pos, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		info, _ := d.CurrentFrameInfo()
+		if info.Offset != d.frameStarts[tt.frame] || info.Sample != tt.sample {
+			t.Errorf("Seek(%d): current frame at %d, sample %d, want frame %d at sample %d",
+				pos, info.Offset, info.Sample, tt.frame, tt.sample)
+		}
+		if fi, _ := d.FrameInfo(tt.frame); fi.Sample != tt.sample {
+			t.Errorf("FrameInfo(%d).Sample = %d, want %d", tt.frame, fi.Sample, tt.sample)
+		}
+		rest, err := io.ReadAll(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int64(len(rest)) != d.Length()-pos {
+			t.Errorf("Seek(%d): read %d bytes, want %d", pos, len(rest), d.Length()-pos)
+		}
+	}
+}
+
+func TestSeekToTime_MixedSampleRates(t *testing.T) {
+	d, err := NewDecoder(bytes.NewReader(mixedRateStream(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mpeg1 := 1152 * time.Second / 44100
+	mpeg2 := 576 * time.Second / 22050
+	if got, want := d.Duration(), 5*mpeg1+3*mpeg2; got < want || got > want+time.Microsecond {
+		t.Errorf("Duration() = %v, want %v", got, want)
+	}
+	for _, tt := range []struct {
+		time  time.Duration
+		frame int
+	}{
+		{mpeg1 / 2, 0},
+		{3*mpeg1 + mpeg2/2, 3},
+		{3*mpeg1 + 2*mpeg2 + time.Millisecond, 5},
+		{3*mpeg1 + 3*mpeg2 + mpeg1 + time.Millisecond, 7},
+	} {
+		if err := d.SeekToTime(tt.time); err != nil {
+			t.Fatal(err)
+		}
+		info, _ := d.CurrentFrameInfo()
+		if info.Offset != d.frameStarts[tt.frame] {
+			t.Errorf("SeekToTime(%v): current frame at %d, want frame %d", tt.time, info.Offset, tt.frame)
+		}
+		if diff := tt.time - d.Position(); diff < 0 || diff > 50*time.Microsecond {
+			t.Errorf("SeekToTime(%v): Position() = %v", tt.time, d.Position())
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package mp3
+
+import (
+	"slices"
+	"time"
+
+	"github.com/llehouerou/go-mp3/internal/frameheader"
+)
+
+// A rateSegment is a run of frames with the same sample rate. Times within
+// a segment are computed from its start, so that they are exact for streams
+// with a single sample rate.
+type rateSegment struct {
+	frame  int64         // Index of the first frame
+	offset int64         // PCM byte offset in the audio
+	start  time.Duration // Time in the audio
+	rate   int
+}
+
+// addFrameTime adds the frame with header h, whose PCM starts at byte offset
+// in the audio, to the time index. Frames must be added in order.
+func (d *Decoder) addFrameTime(h frameheader.FrameHeader, offset int64) {
+	rate, err := h.SamplingFrequencyValue()
+	if d.halfRate {
+		rate /= 2
+	}
+	n := len(d.rateSegments)
+	if err != nil && n > 0 {
+		rate = d.rateSegments[n-1].rate
+	}
+	if n == 0 || d.rateSegments[n-1].rate != rate {
+		var start time.Duration
+		if n > 0 {
+			start = d.rateSegments[n-1].timeAt(offset)
+		}
+		d.rateSegments = append(d.rateSegments, rateSegment{
+			frame:  int64(len(d.frameTimes)),
+			offset: offset,
+			start:  start,
+			rate:   rate,
+		})
+		n++
+	}
+	d.frameTimes = append(d.frameTimes, d.rateSegments[n-1].timeAt(offset))
+}
+
+// timeAt returns the time of PCM byte offset apos within the segment.
+func (s rateSegment) timeAt(apos int64) time.Duration {
+	return s.start + samplesDuration((apos-s.offset)/4, s.rate)
+}
+
+// samplesDuration returns the duration of n samples at rate.
+func samplesDuration(n int64, rate int) time.Duration {
+	if rate == 0 {
+		return 0
+	}
+	r := int64(rate)
+	// Split the seconds off to avoid overflowing on long streams.
+	return time.Duration(n/r)*time.Second + time.Duration(n%r)*time.Second/time.Duration(r)
+}
+
+// segmentOf returns the segment holding frame f.
+func (d *Decoder) segmentOf(f int64) rateSegment {
+	i, found := slices.BinarySearchFunc(d.rateSegments, f, func(s rateSegment, f int64) int {
+		return int(s.frame - f)
+	})
+	if !found {
+		i--
+	}
+	return d.rateSegments[i]
+}
+
+// timeAt returns the time of the byte position pos. Frames are timed with
+// their own sample rate, so that times stay exact when it changes within the
+// stream.
+func (d *Decoder) timeAt(pos int64) time.Duration {
+	if len(d.frameTimes) == 0 || pos <= d.priming {
+		return d.bytesToDuration(pos)
+	}
+	apos := pos - d.priming
+	return d.bytesToDuration(d.priming) + d.segmentOf(d.frameAt(apos)).timeAt(apos)
+}
+
+// posAt returns the byte position of the sample at time t, found by binary
+// search in the time index.
+func (d *Decoder) posAt(t time.Duration) int64 {
+	primed := d.bytesToDuration(d.priming)
+	if len(d.frameTimes) == 0 || t <= primed {
+		return d.durationToBytes(t) &^ 3
+	}
+	at := t - primed
+	f, found := slices.BinarySearch(d.frameTimes, at)
+	if !found {
+		f--
+	}
+	s := d.segmentOf(int64(f))
+	n := int64(at-s.start) / int64(time.Second) * int64(s.rate)
+	n += int64(at-s.start) % int64(time.Second) * int64(s.rate) / int64(time.Second)
+	return min(d.priming+s.offset+4*n, d.length)
+}
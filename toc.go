@@ -0,0 +1,121 @@
+package mp3
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/llehouerou/go-mp3/lameinfo"
+)
+
+// ErrNoTOC is returned by TOCAccuracy when the stream has no Xing header or
+// the header carries no seek table.
+var ErrNoTOC = errors.New("mp3: no Xing TOC")
+
+// TOCAccuracy describes how far the positions predicted by the Xing seek
+// table (TOC) are from the actual frame positions of the stream.
+//
+// For each of the 100 TOC entries the error is the difference between the
+// time the entry stands for and the start time of the frame a seek through
+// that entry lands on.
+type TOCAccuracy struct {
+	// MaxError is the largest error over all TOC entries.
+	MaxError time.Duration
+
+	// MeanError is the average error over all TOC entries.
+	MeanError time.Duration
+}
+
+// TOCAccuracy compares the Xing seek table of the stream with the frame
+// positions found by the full scan of the stream. Applications can use it to
+// decide whether seeking through the TOC alone is good enough for a file.
+//
+// It returns ErrNoTOC if the stream has no TOC, and an error if the source is
+// not io.Seeker.
+func (d *Decoder) TOCAccuracy() (TOCAccuracy, error) {
+	if d.length == invalidLength {
+		return TOCAccuracy{}, errors.New("mp3: TOC accuracy requires a seekable source")
+	}
+	if len(d.frameStarts) < 2 {
+		return TOCAccuracy{}, ErrNoTOC
+	}
+
+	info := d.xing
+	if info == nil || !info.HasTOC() {
+		return TOCAccuracy{}, ErrNoTOC
+	}
+	size, err := d.sourceSize()
+	if err != nil {
+		return TOCAccuracy{}, err
+	}
+	byteCount := size - d.frameStarts[0]
+	if info.HasByteCount() && info.ByteCount != 0 {
+		byteCount = xingByteCount(info.ByteCount, byteCount)
+	}
+
+	// The Xing frame itself carries no audio; the TOC maps the remaining
+	// frames. Frames are timed from the time index, so that frames of
+	// different sizes or sample rates are accounted for.
+	n := len(d.frameStarts)
+	frameTime := func(i int) time.Duration {
+		if i == n {
+			return d.timeAt(d.priming + d.frameOffsets[n-1] + d.pcmBytes(d.frameHeaders[n-1]))
+		}
+		return d.timeAt(d.priming + d.frameOffsets[i])
+	}
+	first, last := frameTime(1), frameTime(n)
+	audioStarts := d.frameStarts[1:]
+	var sum, maxErr time.Duration
+	for i, entry := range info.TOC {
+		target := d.frameStarts[0] + int64(entry)*byteCount/256
+		// A seek to target resyncs on the next frame header.
+		k := sort.Search(len(audioStarts), func(j int) bool {
+			return audioStarts[j] >= target
+		})
+		want := first + time.Duration(float64(last-first)*float64(i)/100)
+		e := frameTime(k+1) - want
+		if e < 0 {
+			e = -e
+		}
+		sum += e
+		maxErr = max(maxErr, e)
+	}
+	return TOCAccuracy{
+		MaxError:  maxErr,
+		MeanError: sum / time.Duration(len(info.TOC)),
+	}, nil
+}
+
+// scanXingHeader parses the Xing header of the first frame of a seekable
+// source, found at pos with the given size, into d.xing. The source is left
+// after the frame header.
+func (d *Decoder) scanXingHeader(pos int64, size int) error {
+	frame, err := d.source.readAt(pos, size)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if _, err := d.source.Seek(pos+4, io.SeekStart); err != nil {
+		return err
+	}
+	if frame == nil {
+		// The first frame is truncated.
+		return nil
+	}
+	if info, err := lameinfo.Parse(frame); err == nil {
+		d.xing = info
+	}
+	return nil
+}
+
+// xingByteCount returns the Xing byte count n of a stream of about size
+// bytes. The field is 32-bit and wraps past 4 GB, so multiples of 2^32 are
+// added to n as long as they bring it closer to size.
+func xingByteCount(n uint32, size int64) int64 {
+	const wrap = 1 << 32
+	c := int64(n)
+	for c+wrap/2 < size {
+		c += wrap
+	}
+	return c
+}
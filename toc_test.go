@@ -0,0 +1,71 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTOCAccuracy(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	before, _ := io.ReadAll(io.LimitReader(d, 4096))
+
+	acc, err := d.TOCAccuracy()
+	if err != nil {
+		t.Fatalf("TOCAccuracy failed: %v", err)
+	}
+	if acc.MeanError > acc.MaxError {
+		t.Errorf("MeanError %v > MaxError %v", acc.MeanError, acc.MaxError)
+	}
+	// LAME's TOC has a resolution of 1/256 of the file, so errors of a
+	// good table stay well below a second for this short file.
+	if acc.MaxError <= 0 || acc.MaxError > 500*time.Millisecond {
+		t.Errorf("MaxError = %v, want within (0, 500ms]", acc.MaxError)
+	}
+
+	// The decoding position must not be disturbed.
+	after, _ := io.ReadAll(io.LimitReader(d, 4096))
+	ref, _ := NewDecoder(bytes.NewReader(data))
+	want, _ := io.ReadAll(io.LimitReader(ref, 8192))
+	if !bytes.Equal(append(before, after...), want) {
+		t.Error("decoded output changed after TOCAccuracy")
+	}
+}
+
+func TestTOCAccuracy_NoTOC(t *testing.T) {
+	data, err := os.ReadFile("example/classic.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	if _, err := d.TOCAccuracy(); !errors.Is(err, ErrNoTOC) {
+		t.Errorf("TOCAccuracy error = %v, want ErrNoTOC", err)
+	}
+}
+
+func TestTOCAccuracy_NonSeekable(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(io.MultiReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	if _, err := d.TOCAccuracy(); err == nil {
+		t.Error("expected an error for a non-seekable source")
+	}
+}
@@ -4,47 +4,22 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"log/slog"
 	"os"
 	"testing"
 
 	"github.com/llehouerou/go-mp3/internal/frameheader"
+	"github.com/llehouerou/go-mp3/testsupport"
 )
 
 // createMinimalMP3Frame creates a minimal valid MPEG1 Layer3 frame for testing.
-// This creates a 417-byte frame (128kbps, 44100Hz, no padding).
+// This creates a 417-byte frame (128kbps, 44100Hz, no padding, joint stereo)
+// with main data begin 0 and silent main data.
 func createMinimalMP3Frame() []byte {
-	// MPEG1 Layer3 128kbps 44100Hz stereo frame
-	// Frame size = 144 * bitrate / samplerate + padding = 144 * 128000 / 44100 = 417 bytes
-	frame := make([]byte, 417)
-
-	// Frame header: 0xFFFB9044
-	// - Sync word: 0xFFF
-	// - ID: 11 (MPEG1)
-	// - Layer: 01 (Layer3)
-	// - Protection: 1 (no CRC)
-	// - Bitrate: 1001 (128kbps)
-	// - Sampling freq: 00 (44100Hz)
-	// - Padding: 0
-	// - Private: 0
-	// - Mode: 01 (Joint stereo)
-	// - Mode ext: 00
-	// - Copyright: 0
-	// - Original: 1
-	// - Emphasis: 00
-	frame[0] = 0xFF
-	frame[1] = 0xFB
-	frame[2] = 0x90
-	frame[3] = 0x44
-
-	// Side info for stereo MPEG1 (32 bytes)
-	// Main data begin = 0 (no bit reservoir)
-	frame[4] = 0x00
-	frame[5] = 0x00
-	// Rest of side info (30 bytes) - zeros are acceptable for minimal valid frame
-
-	// Main data - fill with zeros (silence)
-	// The rest of the frame is already zero-initialized
-
+	frame, err := testsupport.Frame(44100, 128, false, false)
+	if err != nil {
+		panic(err)
+	}
 	return frame
 }
 
@@ -548,3 +523,209 @@ func TestSyncSearchLimitError_TypeAssertion(t *testing.T) {
 		t.Errorf("Error() = %q, want specific message", msg)
 	}
 }
+
+// createAppendedID3v2Tag creates an ID3v2.4 tag with a footer, as appended
+// at the end of a file. Its body holds a copy of an MP3 frame header so that
+// a frame scan running into the tag would find a bogus frame.
+func createAppendedID3v2Tag() []byte {
+	body := createID3v2Frame("PRIV", append([]byte("test\x00"), createMinimalMP3Frame()[:64]...))
+	tag := createID3v2Tag(4, len(body))
+	tag[5] = 0x10 // Footer present
+	copy(tag[10:], body)
+	footer := append([]byte(nil), tag[:10]...)
+	copy(footer, "3DI")
+	return append(tag, footer...)
+}
+
+func TestDecoder_WithAppendedID3v2Tag(t *testing.T) {
+	tests := []struct {
+		name   string
+		suffix []byte
+	}{
+		{"footer at end", createAppendedID3v2Tag()},
+		{"followed by ID3v1", append(createAppendedID3v2Tag(), createID3v1Tag()...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			numFrames := 10
+			for range numFrames {
+				buf.Write(createMinimalMP3Frame())
+			}
+			buf.Write(tt.suffix)
+
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, nil))
+			d, err := NewDecoder(bytes.NewReader(buf.Bytes()), WithLogger(logger))
+			if err != nil {
+				t.Fatalf("NewDecoder() failed: %v", err)
+			}
+			expectedPCMLength := int64(numFrames * 1152 * 4)
+			if d.Length() != expectedPCMLength {
+				t.Errorf("Length() = %d, want %d", d.Length(), expectedPCMLength)
+			}
+			pcm, err := io.ReadAll(d)
+			if err != nil {
+				t.Fatalf("ReadAll() failed: %v", err)
+			}
+			if int64(len(pcm)) != expectedPCMLength {
+				t.Errorf("Decoded %d bytes, want %d", len(pcm), expectedPCMLength)
+			}
+			if logs.Len() != 0 {
+				t.Errorf("unexpected warnings: %s", logs.String())
+			}
+		})
+	}
+}
+
+// TestDecoder_MisSizedID3v2Tags tests tags whose size field was computed
+// wrongly by old taggers. Tags hold a copy of a frame header near their end,
+// so stopping short of the real end of the tag makes the sync search find a
+// bogus frame or cuts the metadata.
+func TestDecoder_MisSizedID3v2Tags(t *testing.T) {
+	bogus := createMinimalMP3Frame()[:10]
+	title := createID3v2Frame("TIT2", []byte("\x03Title"))
+
+	// Version 2.3 tag whose size leaves out the 10-byte extended header.
+	extBody := append([]byte{0, 0, 0, 6, 0, 0, 0, 0, 0, 0}, createID3v2Frame("PRIV", bogus)...)
+	extBody = append(extBody, title...)
+	extTag := createID3v2Tag(3, len(extBody)-10)
+	extTag[5] = 0x40
+	extTag = append(extTag[:10], extBody...)
+
+	// Tag whose size is a plain integer instead of a syncsafe one.
+	plainBody := append(append([]byte{}, title...), make([]byte, 400-len(title)-len(bogus))...)
+	plainBody = append(plainBody, bogus...)
+	plainTag := append([]byte{'I', 'D', '3', 4, 0, 0, 0, 0, 0x01, 0x90}, plainBody...)
+
+	// Version 2.3 unsynchronised tag whose size was computed before
+	// unsynchronisation.
+	unsyncBody := append(createID3v2Frame("PRIV", bogus), title...)
+	unsynced := bytes.ReplaceAll(unsyncBody, []byte{0xff}, []byte{0xff, 0x00})
+	unsyncTag := createID3v2Tag(3, len(unsyncBody))
+	unsyncTag[5] = 0x80
+	unsyncTag = append(unsyncTag[:10], unsynced...)
+
+	tests := []struct {
+		name string
+		tag  []byte
+	}{
+		{"extended header", extTag},
+		{"plain size", plainTag},
+		{"unsynchronisation", unsyncTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := append([]byte{}, tt.tag...)
+			numFrames := 10
+			for range numFrames {
+				buf = append(buf, createMinimalMP3Frame()...)
+			}
+			d, err := NewDecoder(bytes.NewReader(buf))
+			if err != nil {
+				t.Fatalf("NewDecoder() failed: %v", err)
+			}
+			expectedPCMLength := int64(numFrames * 1152 * 4)
+			if d.Length() != expectedPCMLength {
+				t.Errorf("Length() = %d, want %d", d.Length(), expectedPCMLength)
+			}
+			if d.frameStarts[0] != int64(len(tt.tag)) {
+				t.Errorf("first frame at %d, want %d", d.frameStarts[0], len(tt.tag))
+			}
+			if d.Metadata() == nil || d.Metadata().Title() != "Title" {
+				t.Errorf("Metadata() = %+v, want a tag titled Title", d.Metadata())
+			}
+		})
+	}
+}
+
+// TestDecoder_WithMidStreamID3v2Tag tests streams with an ID3v2 tag between
+// frames, larger than the sync search limit, as found in concatenated files.
+func TestDecoder_WithMidStreamID3v2Tag(t *testing.T) {
+	var buf bytes.Buffer
+	numFrames := 10
+	for i := range numFrames {
+		if i == numFrames/2 {
+			buf.Write(createID3v2Tag(3, 100*1024))
+		}
+		buf.Write(createMinimalMP3Frame())
+	}
+	expectedPCMLength := int64(numFrames * 1152 * 4)
+
+	d, err := NewDecoder(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("NewDecoder() failed: %v", err)
+	}
+	if d.Length() != expectedPCMLength {
+		t.Errorf("Length() = %d, want %d", d.Length(), expectedPCMLength)
+	}
+
+	// Non-seekable sources skip the tag while decoding.
+	d, err = NewDecoder(io.MultiReader(bytes.NewReader(buf.Bytes())))
+	if err != nil {
+		t.Fatalf("NewDecoder() failed: %v", err)
+	}
+	pcm, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("ReadAll() failed: %v", err)
+	}
+	if int64(len(pcm)) != expectedPCMLength {
+		t.Errorf("Decoded %d bytes, want %d", len(pcm), expectedPCMLength)
+	}
+}
+
+func TestNewDecoder_NoAudioFrames(t *testing.T) {
+	tag := testsupport.ID3v2(map[string]string{"TIT2": "Only Tags"})
+	tests := []struct {
+		name  string
+		data  []byte
+		title string
+	}{
+		{"empty", nil, ""},
+		{"ID3v2 only", tag, "Only Tags"},
+		{"ID3v2 and ID3v1", append(bytes.Clone(tag), testsupport.ID3v1()...), "Only Tags"},
+		{"ID3v1 only", testsupport.ID3v1(), ""},
+		{"ID3v2 and padding", append(bytes.Clone(tag), make([]byte, 5000)...), "Only Tags"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDecoder(bytes.NewReader(tt.data))
+			if !errors.Is(err, ErrNoAudioFrames) {
+				t.Fatalf("NewDecoder error = %v, want ErrNoAudioFrames", err)
+			}
+			var noAudio *NoAudioFramesError
+			if !errors.As(err, &noAudio) {
+				t.Fatalf("NewDecoder error %T is not a *NoAudioFramesError", err)
+			}
+			var title string
+			if noAudio.Metadata != nil {
+				title = noAudio.Metadata.Title()
+			}
+			if title != tt.title {
+				t.Errorf("title = %q, want %q", title, tt.title)
+			}
+		})
+	}
+}
+
+// TestID3v2TagSize_PlainSize tests that a size that is not a syncsafe integer
+// is read as a plain integer only when the bytes left can hold it.
+func TestID3v2TagSize_PlainSize(t *testing.T) {
+	tests := []struct {
+		size  []byte
+		limit int64
+		want  int
+	}{
+		{[]byte{0, 0, 0x01, 0x90}, 400, 400},
+		{[]byte{0, 0, 0x01, 0x90}, 399, 1<<7 | 0x90},
+		{[]byte{0, 0, 0x01, 0x90}, -1, 1<<7 | 0x90},
+		{[]byte{0x7f, 0, 0, 0x80}, -1, 0x7f<<21 | 0x80},
+		{[]byte{0x7f, 0, 0, 0x80}, 1 << 32, 0x7f000080},
+	}
+	for _, tt := range tests {
+		header := append([]byte{'I', 'D', '3', 4, 0, 0}, tt.size...)
+		if got := id3v2TagSize(header, tt.limit); got != tt.want {
+			t.Errorf("id3v2TagSize(% x, %d) = %d, want %d", tt.size, tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package mp3
+
+import (
+	"io"
+)
+
+// A TransformFunc turns bytes read from the input into MP3 data in place,
+// for example to decrypt or deobfuscate a scrambled container. offset is the
+// offset of b[0] in the input, so that position-dependent schemes keep
+// working across seeks.
+type TransformFunc func(b []byte, offset int64)
+
+// WithTransform makes the decoder pass every chunk read from the source
+// through f before looking for frames. Offsets, and so seeking, Length and
+// frame positions, refer to the input as it is stored. The source is still
+// seekable if it implements io.Seeker.
+func WithTransform(f TransformFunc) Option {
+	return func(c *config) {
+		c.transform = f
+	}
+}
+
+// transformReader applies a TransformFunc to the data read from reader.
+type transformReader struct {
+	reader    io.Reader
+	transform TransformFunc
+	offset    int64
+}
+
+func (r *transformReader) Read(buf []byte) (int, error) {
+	n, err := r.reader.Read(buf)
+	r.transform(buf[:n], r.offset)
+	r.offset += int64(n)
+	return n, err
+}
+
+// transformSeeker is a transformReader over an io.Seeker.
+type transformSeeker struct {
+	*transformReader
+}
+
+func (r transformSeeker) Seek(offset int64, whence int) (int64, error) {
+	n, err := r.reader.(io.Seeker).Seek(offset, whence)
+	if err != nil {
+		return n, err
+	}
+	r.offset = n
+	return n, nil
+}
+
+// newTransformReader wraps r, whose first byte is at offset in the input,
+// so that f is applied to the data read. The result implements io.Seeker if
+// r does.
+func newTransformReader(r io.Reader, f TransformFunc, offset int64) io.Reader {
+	t := &transformReader{reader: r, transform: f, offset: offset}
+	if _, ok := r.(io.Seeker); ok {
+		return transformSeeker{t}
+	}
+	return t
+}
@@ -0,0 +1,95 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+// scramble XORs b with a key stream that depends on the offset in the file.
+func scramble(b []byte, offset int64) {
+	for i := range b {
+		b[i] ^= byte((offset+int64(i))*7 + 3)
+	}
+}
+
+func TestWithTransform(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	scrambled := bytes.Clone(data)
+	scramble(scrambled, 0)
+
+	ref, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDecoder(bytes.NewReader(scrambled), WithTransform(scramble))
+	if err != nil {
+		t.Fatalf("NewDecoder with transform failed: %v", err)
+	}
+	if d.Length() != ref.Length() {
+		t.Errorf("Length() = %d, want %d", d.Length(), ref.Length())
+	}
+
+	// Seeking must keep the key stream in step with the offsets.
+	for _, pos := range []int64{400000, 4608, 1000000} {
+		if _, err := ref.Seek(pos, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.Seek(pos, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		want := make([]byte, 20000)
+		got := make([]byte, 20000)
+		if _, err := io.ReadFull(ref, want); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.ReadFull(d, got); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("PCM at %d differs from the plain file", pos)
+		}
+	}
+}
+
+func TestWithTransform_NonSeekableAndSetSource(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	scrambled := bytes.Clone(data)
+	scramble(scrambled, 0)
+
+	ref, err := NewDecoder(nonSeekable{bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := io.ReadAll(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const received = 200003
+	d, err := NewDecoder(&droppingReader{data: scrambled, n: received}, WithTransform(scramble))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Length() != -1 {
+		t.Errorf("Length() = %d, want -1 for a non-seekable source", d.Length())
+	}
+	got, _ := readUntilError(t, d)
+	if err := d.SetSource(nonSeekable{bytes.NewReader(scrambled[received:])}, received); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got = append(got, rest...); !bytes.Equal(got, want) {
+		t.Error("PCM of the resumed scrambled stream differs from the plain file")
+	}
+}
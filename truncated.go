@@ -0,0 +1,91 @@
+package mp3
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/llehouerou/go-mp3/internal/frame"
+)
+
+// TruncatedFrame is what the decoder does with a last frame cut off by the
+// end of the stream, as left by an interrupted download.
+type TruncatedFrame int
+
+const (
+	// TruncatedFrameDrop ends the stream before the truncated frame, as if
+	// the file ended cleanly. It is the default. The frame still counts in
+	// Length and Duration, whose index only reads frame headers.
+	TruncatedFrameDrop TruncatedFrame = iota
+
+	// TruncatedFramePartial decodes the truncated frame with its missing
+	// bytes replaced by zeros, so that the audio it holds is returned. The
+	// granules whose data is missing decode as silence.
+	TruncatedFramePartial
+
+	// TruncatedFrameError makes Read return a *TruncatedError instead of
+	// io.EOF, so that incomplete files can be told from complete ones.
+	TruncatedFrameError
+)
+
+// WithTruncatedFrame sets what the decoder does with a truncated last frame.
+//
+// Only frames whose header is complete count as truncated; a few bytes of a
+// header at the end of the stream are ignored. A source read with a
+// deadline or resumed with SetSource can continue a frame which ended the
+// data read so far, which the policies other than TruncatedFrameDrop
+// prevent.
+func WithTruncatedFrame(t TruncatedFrame) Option {
+	return func(c *config) {
+		c.truncatedFrame = t
+	}
+}
+
+// ErrTruncated matches, with errors.Is, the error returned with
+// TruncatedFrameError when the last frame is truncated.
+var ErrTruncated = errors.New("mp3: truncated frame")
+
+// TruncatedError is returned by Read with TruncatedFrameError when the
+// stream ends within a frame.
+type TruncatedError struct {
+	// Offset is the input offset of the truncated frame, before which the
+	// stream is complete.
+	Offset int64
+}
+
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("mp3: truncated frame at offset %d", e.Offset)
+}
+
+func (e *TruncatedError) Unwrap() error {
+	return ErrTruncated
+}
+
+// readTruncated applies the truncated frame policy to err, returned by read
+// for the frame at the source position pos, whose bytes are recorded. It
+// returns the frame to use, or the error to return if there is none.
+func (d *Decoder) readTruncated(err error, read func(frame.FullReader, int64, *frame.Frame) (*frame.Frame, int64, error), pos int64) (*frame.Frame, int64, error) {
+	var t *frame.TruncatedError
+	if d.truncatedFrame == TruncatedFrameDrop || !errors.As(err, &t) {
+		return nil, 0, err
+	}
+	if d.truncatedFrame == TruncatedFrameError {
+		return nil, 0, &TruncatedError{Offset: t.Position}
+	}
+	d.logger.Warn("mp3: truncated frame padded with silence",
+		slog.String("at", t.Err.At), slog.Int64("offset", t.Position))
+	return read(&zeroPadded{b: d.source.record}, pos, d.frame)
+}
+
+// zeroPadded reads b followed by zeros without end, standing in for the
+// missing end of a truncated frame.
+type zeroPadded struct {
+	b []byte
+}
+
+func (z *zeroPadded) ReadFull(buf []byte) (int, error) {
+	n := copy(buf, z.b)
+	z.b = z.b[n:]
+	clear(buf[n:])
+	return len(buf), nil
+}
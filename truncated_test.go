@@ -0,0 +1,92 @@
+package mp3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+// truncatedStream returns classic_lame.mp3 cut in the middle of a frame, and
+// the offset of that frame.
+func truncatedStream(t *testing.T) ([]byte, int64) {
+	t.Helper()
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	offset := d.frameStarts[len(d.frameStarts)/2]
+	return data[:offset+200], offset
+}
+
+func decodeTruncated(t *testing.T, data []byte, opts ...Option) (*Decoder, []byte, error) {
+	t.Helper()
+	d, err := NewDecoder(bytes.NewReader(data), opts...)
+	if err != nil {
+		t.Fatalf("NewDecoder failed: %v", err)
+	}
+	pcm, err := io.ReadAll(d)
+	return d, pcm, err
+}
+
+func TestWithTruncatedFrame(t *testing.T) {
+	data, offset := truncatedStream(t)
+
+	d, dropped, err := decodeTruncated(t, data)
+	if err != nil {
+		t.Fatalf("ReadAll with the default policy failed: %v", err)
+	}
+
+	_, partial, err := decodeTruncated(t, data, WithTruncatedFrame(TruncatedFramePartial))
+	if err != nil {
+		t.Fatalf("ReadAll with TruncatedFramePartial failed: %v", err)
+	}
+	if got, want := int64(len(partial)), int64(len(dropped))+d.BytesPerFrame(); got != want {
+		t.Errorf("TruncatedFramePartial returned %d bytes, want %d", got, want)
+	}
+	if int64(len(partial)) != d.Length() {
+		t.Errorf("TruncatedFramePartial returned %d bytes, Length is %d", len(partial), d.Length())
+	}
+	if !bytes.Equal(partial[:len(dropped)], dropped) {
+		t.Error("TruncatedFramePartial changed the audio before the truncated frame")
+	}
+	if tail := partial[len(dropped):]; bytes.Count(tail, []byte{0}) == len(tail) {
+		t.Error("TruncatedFramePartial returned silence for the truncated frame")
+	}
+
+	_, pcm, err := decodeTruncated(t, data, WithTruncatedFrame(TruncatedFrameError))
+	if !errors.Is(err, ErrTruncated) {
+		t.Fatalf("ReadAll with TruncatedFrameError returned %v, want ErrTruncated", err)
+	}
+	var te *TruncatedError
+	if !errors.As(err, &te) || te.Offset != offset {
+		t.Errorf("got error %v, want a *TruncatedError at offset %d", err, offset)
+	}
+	if !bytes.Equal(pcm, dropped) {
+		t.Errorf("TruncatedFrameError returned %d bytes before the error, want %d", len(pcm), len(dropped))
+	}
+}
+
+func TestWithTruncatedFrame_CompleteStream(t *testing.T) {
+	data, offset := truncatedStream(t)
+	data = data[:offset]
+
+	_, want, err := decodeTruncated(t, data)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	for _, tf := range []TruncatedFrame{TruncatedFramePartial, TruncatedFrameError} {
+		_, got, err := decodeTruncated(t, data, WithTruncatedFrame(tf))
+		if err != nil {
+			t.Errorf("policy %d: ReadAll failed: %v", tf, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("policy %d: got %d bytes, want %d", tf, len(got), len(want))
+		}
+	}
+}
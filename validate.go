@@ -0,0 +1,373 @@
+package mp3
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/llehouerou/go-mp3/internal/frameheader"
+	"github.com/llehouerou/go-mp3/lameinfo"
+)
+
+// A ValidationIssueType identifies the kind of a ValidationIssue.
+type ValidationIssueType int
+
+const (
+	// IssueResync is reported when bytes had to be skipped to find the next
+	// frame header.
+	IssueResync ValidationIssueType = iota
+
+	// IssueCRC is reported for a frame whose CRC does not match its header
+	// and side information.
+	IssueCRC
+
+	// IssueFormatChange is reported for a frame whose MPEG version, sample
+	// rate or channel count differs from those of the first frame.
+	IssueFormatChange
+
+	// IssueTruncatedFrame is reported when the last frame is cut short.
+	IssueTruncatedFrame
+
+	// IssueTrailingData is reported for bytes after the last frame that
+	// are not a tag.
+	IssueTrailingData
+
+	// IssueTag is reported for a tag that is damaged or in an unexpected
+	// place.
+	IssueTag
+
+	// IssueXing is reported when the Xing header or LAME tag do not match
+	// the frames of the stream, or the LAME tag is damaged.
+	IssueXing
+)
+
+// String returns the name of the issue type.
+func (t ValidationIssueType) String() string {
+	switch t {
+	case IssueResync:
+		return "resync"
+	case IssueCRC:
+		return "CRC mismatch"
+	case IssueFormatChange:
+		return "format change"
+	case IssueTruncatedFrame:
+		return "truncated frame"
+	case IssueTrailingData:
+		return "trailing data"
+	case IssueTag:
+		return "tag"
+	case IssueXing:
+		return "Xing header"
+	}
+	return "unknown"
+}
+
+// A ValidationIssue is a problem found by ValidateStream.
+type ValidationIssue struct {
+	Type ValidationIssueType
+
+	// Offset is the byte offset in the stream where the issue was found.
+	Offset int64
+
+	// Skipped is the number of bytes skipped for IssueResync and
+	// IssueTrailingData.
+	Skipped int64
+
+	// Message describes the issue.
+	Message string
+}
+
+// String returns a description of the issue.
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("%s at offset %d: %s", i.Type, i.Offset, i.Message)
+}
+
+// A ValidationReport is the result of ValidateStream.
+type ValidationReport struct {
+	// Frames is the number of audio frames, not counting the Xing header
+	// frame, and Duration is their duration.
+	Frames   int
+	Duration time.Duration
+
+	// SampleRate and Channels are those of the first frame.
+	SampleRate int
+	Channels   int
+
+	// Xing holds the Xing header of the stream, or nil if it has none.
+	Xing *lameinfo.Info
+
+	// ID3v2Tags is the number of ID3v2 tags at the start of the stream, and
+	// TrailingTagBytes the size of the ID3v1, APE and ID3v2 tags at its end.
+	ID3v2Tags        int
+	TrailingTagBytes int64
+
+	// Issues lists the problems found, in stream order except for those of
+	// the Xing header, which come last.
+	Issues []ValidationIssue
+}
+
+// OK reports whether no issue was found.
+func (r *ValidationReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// ValidateStream scans the whole MP3 stream of r without decoding it and
+// reports its frame count and duration along with the problems found: lost
+// sync, frame CRC failures, format changes, a truncated last frame, damaged
+// or misplaced tags, and Xing header counts that do not match the frames.
+// Services that ingest uploads can use it to reject damaged files before
+// accepting them.
+//
+// ValidateStream returns an error only if r cannot be read. A stream
+// without frames is reported with ErrNoAudioFrames.
+func ValidateStream(r io.ReadSeeker) (*ValidationReport, error) {
+	s := &source{reader: r}
+	v := &validator{report: &ValidationReport{}}
+	end, err := s.trailingTagsStart()
+	if err != nil {
+		return nil, err
+	}
+	size, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := s.appendedTagStart()
+	if err != nil {
+		return nil, err
+	}
+	if tag >= 0 {
+		end = min(end, tag)
+	}
+	v.report.TrailingTagBytes = size - end
+
+	if err := s.rewind(); err != nil {
+		return nil, err
+	}
+	s.onID3v2 = func([]byte) { v.report.ID3v2Tags++ }
+	if err := s.skipTags(); err != nil {
+		if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, err
+		}
+		v.issue(IssueTag, s.pos, 0, "tag extends past the end of the stream")
+		return v.report, ErrNoAudioFrames
+	}
+	start := s.pos
+	if _, err := s.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+	v.offset = start
+	v.end = end
+	v.r = bufio.NewReaderSize(io.LimitReader(r, max(end-start, 0)), 64*1024)
+	if err := v.scan(); err != nil {
+		return nil, err
+	}
+	if v.frames == 0 {
+		return v.report, ErrNoAudioFrames
+	}
+	v.checkXing()
+	return v.report, nil
+}
+
+// A validator scans the frames of a stream for ValidateStream.
+type validator struct {
+	r      *bufio.Reader
+	offset int64
+	report *ValidationReport
+
+	// end is the offset where the audio ends.
+	end int64
+
+	// first is the header of the first frame, and xingOffset and xingSize
+	// the position and size of the Xing header frame, if any. frames and
+	// bytes count all the frames, including the Xing header frame.
+	first      frameheader.FrameHeader
+	xingOffset int64
+	xingSize   int
+	frames     int
+	bytes      int64
+}
+
+// issue adds an issue to the report.
+func (v *validator) issue(t ValidationIssueType, offset, skipped int64, msg string) {
+	v.report.Issues = append(v.report.Issues, ValidationIssue{
+		Type: t, Offset: offset, Skipped: skipped, Message: msg,
+	})
+}
+
+// scan reads the frames up to the end of the audio.
+func (v *validator) scan() error {
+	for {
+		b, err := v.r.Peek(4)
+		if len(b) == 0 {
+			return nil
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		h := frameheader.FrameHeader(0)
+		if len(b) == 4 {
+			h = headerFromBytes(b)
+		}
+		size := framerFrameSize(h)
+		switch {
+		case size > 0:
+			if err := v.frame(h, size); err != nil {
+				return err
+			}
+		case len(b) >= 3 && string(b[:3]) == "ID3":
+			if err := v.skipID3v2(); err != nil {
+				return err
+			}
+		default:
+			if err := v.resync(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// frame reads the frame with header h and the given size.
+func (v *validator) frame(h frameheader.FrameHeader, size int) error {
+	b, err := v.r.Peek(size)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if len(b) < size {
+		v.issue(IssueTruncatedFrame, v.offset, 0,
+			fmt.Sprintf("frame of %d bytes cut to %d bytes", size, len(b)))
+		return v.discard(len(b))
+	}
+
+	if v.frames == 0 {
+		v.first = h
+		freq, _ := h.SamplingFrequencyValue()
+		v.report.SampleRate = freq
+		v.report.Channels = h.NumberOfChannels()
+		if info, err := lameinfo.Parse(b); err == nil {
+			v.report.Xing = info
+			v.xingOffset = v.offset
+			v.xingSize = size
+		}
+	} else if h.ID() != v.first.ID() || h.SamplingFrequency() != v.first.SamplingFrequency() ||
+		h.NumberOfChannels() != v.first.NumberOfChannels() {
+		v.issue(IssueFormatChange, v.offset, 0, "frame format differs from the first frame")
+	}
+	if h.ProtectionBit() == 0 {
+		if n := 6 + h.SideInfoSize(); n <= size && binary.BigEndian.Uint16(b[4:]) != frameCRC(b[:n]) {
+			v.issue(IssueCRC, v.offset, 0, "frame CRC does not match")
+		}
+	}
+	if v.frames > 0 || v.xingSize == 0 {
+		v.report.Frames++
+		v.report.Duration += h.FrameDuration()
+	}
+	v.frames++
+	v.bytes += int64(size)
+	return v.discard(size)
+}
+
+// skipID3v2 skips an ID3v2 tag found between frames.
+func (v *validator) skipID3v2() error {
+	header, err := v.r.Peek(10)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if len(header) < 10 || header[3] < 2 || header[3] > 4 {
+		return v.resync()
+	}
+	size := 10 + id3v2TagSize(header, v.end-v.offset-10)
+	v.issue(IssueTag, v.offset, 0, "ID3v2 tag between frames")
+	n, err := v.r.Discard(size)
+	v.offset += int64(n)
+	if errors.Is(err, io.EOF) {
+		v.issue(IssueTag, v.offset, 0, "tag extends past the end of the audio")
+		return nil
+	}
+	return err
+}
+
+// resync skips bytes up to the next frame header followed by another frame
+// header or the end of the audio.
+func (v *validator) resync() error {
+	start := v.offset
+	for {
+		if err := v.discard(1); err != nil {
+			return err
+		}
+		b, err := v.r.Peek(4)
+		if len(b) < 4 {
+			if err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+			n, _ := v.r.Discard(len(b))
+			v.offset += int64(n)
+			v.issue(IssueTrailingData, start, v.offset-start, "bytes after the last frame")
+			return nil
+		}
+		size := framerFrameSize(headerFromBytes(b))
+		if size == 0 {
+			continue
+		}
+		next, err := v.r.Peek(size + 4)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if len(next) == size || len(next) == size+4 && framerFrameSize(headerFromBytes(next[size:])) > 0 {
+			v.issue(IssueResync, start, v.offset-start, "bytes skipped to find the next frame")
+			return nil
+		}
+	}
+}
+
+// discard skips n bytes.
+func (v *validator) discard(n int) error {
+	m, err := v.r.Discard(n)
+	v.offset += int64(m)
+	return err
+}
+
+// checkXing compares the Xing header with the frames found.
+func (v *validator) checkXing() {
+	info := v.report.Xing
+	if info == nil {
+		return
+	}
+	if info.HasFrameCount() && int(info.FrameCount) != v.report.Frames {
+		v.issue(IssueXing, v.xingOffset, 0,
+			fmt.Sprintf("Xing header has %d frames, stream has %d", info.FrameCount, v.report.Frames))
+	}
+	// Encoders differ on whether the byte count includes the Xing header
+	// frame.
+	if n := xingByteCount(info.ByteCount, v.bytes); info.HasByteCount() && n != v.bytes && n != v.bytes-int64(v.xingSize) {
+		v.issue(IssueXing, v.xingOffset, 0,
+			fmt.Sprintf("Xing header has %d bytes, stream has %d", n, v.bytes))
+	}
+	if info.HasLAMEInfo() && !info.Valid {
+		v.issue(IssueXing, v.xingOffset, 0, "LAME tag CRC does not match")
+	}
+}
+
+// frameCRC returns the CRC-16 of a protected frame, computed over the last
+// two bytes of its header and its side information, which follow the CRC
+// in b.
+func frameCRC(b []byte) uint16 {
+	crc := uint16(0xffff)
+	update := func(p []byte) {
+		for _, c := range p {
+			crc ^= uint16(c) << 8
+			for range 8 {
+				if crc&0x8000 != 0 {
+					crc = crc<<1 ^ 0x8005
+				} else {
+					crc <<= 1
+				}
+			}
+		}
+	}
+	update(b[2:4])
+	update(b[6:])
+	return crc
+}
@@ -0,0 +1,143 @@
+package mp3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/llehouerou/go-mp3/testsupport"
+)
+
+// issueTypes returns the types of the issues of r.
+func issueTypes(r *ValidationReport) []ValidationIssueType {
+	var types []ValidationIssueType
+	for _, i := range r.Issues {
+		types = append(types, i.Type)
+	}
+	return types
+}
+
+// frameOffset returns the offset of frame i of data, a stream of frames
+// without tags.
+func frameOffset(data []byte, i int) int {
+	off := 0
+	for range i {
+		off += framerFrameSize(headerFromBytes(data[off:]))
+	}
+	return off
+}
+
+func TestValidateStream_Clean(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	r, err := ValidateStream(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ValidateStream() error = %v", err)
+	}
+	if !r.OK() {
+		t.Errorf("Issues = %v, want none", r.Issues)
+	}
+	if r.Xing == nil || int(r.Xing.FrameCount) != r.Frames {
+		t.Errorf("Frames = %d, want the count of the Xing header", r.Frames)
+	}
+	if r.SampleRate != 44100 || r.Channels != 2 {
+		t.Errorf("format = %d Hz, %d channels, want 44100 Hz stereo", r.SampleRate, r.Channels)
+	}
+}
+
+func TestValidateStream_Tags(t *testing.T) {
+	data, err := testsupport.Generate(testsupport.Options{
+		Frames: 10, ID3v1: true, Tags: map[string]string{"TIT2": "Title"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ValidateStream(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ValidateStream() error = %v", err)
+	}
+	if !r.OK() || r.Frames != 10 || r.ID3v2Tags != 1 || r.TrailingTagBytes != 128 {
+		t.Errorf("report %+v, want 10 frames, an ID3v2 tag and an ID3v1 tag", *r)
+	}
+}
+
+func TestValidateStream_Issues(t *testing.T) {
+	plain, err := testsupport.Generate(testsupport.Options{Frames: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f1, f3, f9 := frameOffset(plain, 1), frameOffset(plain, 3), frameOffset(plain, 9)
+	xing, err := testsupport.Generate(testsupport.Options{Frames: 10, Xing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := testsupport.Generate(testsupport.Options{Frames: 2, SampleRate: 48000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		data   []byte
+		want   ValidationIssueType
+		offset int64
+		frames int
+	}{
+		{"resync", slices.Concat(plain[:f3], []byte("garbage"), plain[f3:]), IssueResync, int64(f3), 10},
+		{"truncated", plain[:len(plain)-100], IssueTruncatedFrame, int64(f9), 9},
+		{"trailing data", slices.Concat(plain, []byte("some trailing bytes")),
+			IssueTrailingData, int64(len(plain)), 10},
+		{"tag between frames", slices.Concat(plain[:f1], testsupport.ID3v2(map[string]string{"TIT2": "x"}), plain[f1:]),
+			IssueTag, int64(f1), 10},
+		{"format change", slices.Concat(plain, other), IssueFormatChange, int64(len(plain)), 12},
+		{"Xing counts", slices.Concat(xing, plain[:f1]), IssueXing, 0, 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ValidateStream(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("ValidateStream() error = %v", err)
+			}
+			if len(r.Issues) == 0 || r.Issues[0].Type != tt.want || r.Issues[0].Offset != tt.offset {
+				t.Fatalf("Issues = %v, want %v at offset %d", r.Issues, tt.want, tt.offset)
+			}
+			if r.Frames != tt.frames {
+				t.Errorf("Frames = %d, want %d", r.Frames, tt.frames)
+			}
+		})
+	}
+}
+
+func TestValidateStream_CRC(t *testing.T) {
+	// A silent protected frame: header, CRC, side information and main data,
+	// all zeros.
+	frame, err := testsupport.Frame(44100, 128, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frame = bytes.Clone(frame)
+	frame[1] &^= 1 // Protected
+	clear(frame[4:])
+	binary.BigEndian.PutUint16(frame[4:], frameCRC(frame[:6+32]))
+	damaged := bytes.Clone(frame)
+	damaged[10] = 0xff // Side information
+
+	r, err := ValidateStream(bytes.NewReader(slices.Concat(frame, frame, damaged, frame)))
+	if err != nil {
+		t.Fatalf("ValidateStream() error = %v", err)
+	}
+	if types := issueTypes(r); !slices.Equal(types, []ValidationIssueType{IssueCRC}) || r.Issues[0].Offset != int64(2*len(frame)) {
+		t.Errorf("Issues = %v, want a CRC mismatch in the third frame", r.Issues)
+	}
+}
+
+func TestValidateStream_NoAudio(t *testing.T) {
+	_, err := ValidateStream(bytes.NewReader(testsupport.ID3v2(map[string]string{"TIT2": "x"})))
+	if !errors.Is(err, ErrNoAudioFrames) {
+		t.Errorf("ValidateStream() error = %v, want ErrNoAudioFrames", err)
+	}
+}
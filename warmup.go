@@ -0,0 +1,63 @@
+package mp3
+
+import (
+	"github.com/llehouerou/go-mp3/internal/consts"
+	"github.com/llehouerou/go-mp3/internal/frameheader"
+)
+
+// WithSeekWarmup sets the number of frames Seek decodes and discards before
+// the targeted frame, to fill the bit reservoir and the filterbank state.
+//
+// By default Seek decodes as many frames as the reservoir of the frame
+// before the target can reach back to, so that the audio after a seek is
+// identical to that of linear playback. A fixed count trades this for speed:
+// 1 decodes only the previous frame, as older versions did, and the first
+// granule after a seek may then differ slightly. Counts below 1 are treated as
+// 1. WithReservoirFree decoding needs no warmup and ignores this option.
+func WithSeekWarmup(frames int) Option {
+	return func(c *config) {
+		c.seekWarmup = max(frames, 1)
+	}
+}
+
+// warmupFrames returns the number of frames to decode before frame f when
+// seeking to it.
+func (d *Decoder) warmupFrames(f int64) int64 {
+	if d.seekWarmup > 0 {
+		return min(int64(d.seekWarmup), f)
+	}
+	// The filterbank state a granule starts from depends on the two granules
+	// before it, so the frames holding them must be decoded with their whole
+	// reservoir, which lies in the frames before them. The oldest frame
+	// decoded provides main data only.
+	n := min(int64(2/d.frameHeaders[f].Granules()), f)
+	maxBegin := maxMainDataBegin(d.frameHeaders[f-n])
+	for i, reservoir := f-n-1, 0; i >= 0 && reservoir < maxBegin; i-- {
+		reservoir += mainDataSize(d.frameHeaders[i])
+		n++
+	}
+	return n
+}
+
+// maxMainDataBegin returns the largest main_data_begin of a frame with
+// header h: how far back in bytes its main data can start.
+func maxMainDataBegin(h frameheader.FrameHeader) int {
+	if h.ID() == consts.Version1 {
+		return 511
+	}
+	return 255
+}
+
+// mainDataSize returns the number of main data bytes in a frame with header
+// h.
+func mainDataSize(h frameheader.FrameHeader) int {
+	size, err := h.FrameSize()
+	if err != nil {
+		return 0
+	}
+	size -= 4 + h.SideInfoSize()
+	if h.ProtectionBit() == 0 {
+		size -= 2
+	}
+	return max(size, 0)
+}
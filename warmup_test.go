@@ -0,0 +1,70 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestSeek_MatchesLinearDecoding(t *testing.T) {
+	for _, name := range []string{"example/classic_lame.mp3", "example/mpeg2.mp3"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("failed to read test file: %v", err)
+		}
+		d, want := decodeFresh(t, data)
+		buf := make([]byte, 3*d.BytesPerFrame())
+		for pos := int64(0); pos+int64(len(buf)) <= int64(len(want)); pos += 123456 {
+			if _, err := d.Seek(pos, io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := io.ReadFull(d, buf); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf, want[pos:pos+int64(len(buf))]) {
+				t.Fatalf("%s: PCM after seeking to %d differs from linear decoding", name, pos)
+			}
+		}
+	}
+}
+
+func TestWithSeekWarmup(t *testing.T) {
+	data, err := os.ReadFile("example/classic_lame.mp3")
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+	auto, want := decodeFresh(t, data)
+	if got := auto.warmupFrames(100); got < 2 {
+		t.Errorf("automatic warmupFrames(100) = %d, want enough frames for the reservoir", got)
+	}
+
+	d, err := NewDecoder(bytes.NewReader(data), WithSeekWarmup(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.warmupFrames(100); got != 1 {
+		t.Errorf("warmupFrames(100) = %d, want 1", got)
+	}
+	if got := d.warmupFrames(0); got != 0 {
+		t.Errorf("warmupFrames(0) = %d, want 0", got)
+	}
+
+	// A single warmup frame lands on the same position, but the reservoir
+	// of the previous frame is missing and the first granule differs.
+	pos := int64(200 * d.BytesPerFrame())
+	if _, err := d.Seek(pos, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 2*d.BytesPerFrame())
+	if _, err := io.ReadFull(d, buf); err != nil {
+		t.Fatal(err)
+	}
+	half := d.BytesPerFrame() / 2
+	if bytes.Equal(buf[:half], want[pos:pos+int64(half)]) {
+		t.Error("first granule after a seek with one warmup frame matches linear decoding")
+	}
+	if !bytes.Equal(buf[d.BytesPerFrame():], want[pos+int64(d.BytesPerFrame()):pos+int64(len(buf))]) {
+		t.Error("second frame after a seek with one warmup frame differs from linear decoding")
+	}
+}